@@ -1,55 +1,55 @@
 package main
 
 import (
-	//"bufio"
-	"fmt"
 	"io"
 	"os"
+
+	"github.com/fwip/posix-utils/pkg/flag"
+	"github.com/fwip/posix-utils/pkg/util"
 )
 
 // TODO: Support environment variables
 
-func write(r io.Reader, w io.Writer) (err error) {
-	_, err = io.Copy(w, r)
-	return err
+type settings struct {
+	unbuffered bool
+	filenames  []string
 }
 
-func writeFile(filename string) error {
-	var r io.ReadCloser
-	var err error
-	// Special filename '-' means stdin
-	if filename == "-" {
-		r = os.Stdin
-	} else {
-		r, err = os.Open(filename)
-		if err != nil {
-			return err
-		}
-		defer r.Close()
-	}
-
-	return write(r, os.Stdout)
+func parseSettings(args []string) (settings, error) {
+	var s settings
+	p := flag.Parser{Input: args}
+	// -u forces unbuffered output. Output is already unbuffered.
+	p.BoolVar(&s.unbuffered, 'u', "write without delay")
+	operands, err := p.Parse()
+	s.filenames = util.Operands(operands)
+	return s, err
 }
 
-func main() {
-	filenames := make([]string, 0)
-	for _, s := range os.Args[1:] {
-		// "-u" is an POSIX option that forces unbuffered output. Output is already unbuffered.
-		if s != "-u" {
-			filenames = append(filenames, s)
-		}
+func writeFile(u *util.Utility, filename string) error {
+	r, err := u.Open(filename)
+	if err != nil {
+		return err
 	}
+	defer r.Close()
+	_, err = io.Copy(u.Stdout, r)
+	return err
+}
 
-	// If called with no arguments, read from stdin
-	if len(filenames) == 0 {
-		filenames = append(filenames, "-")
+// run writes each file in turn, carrying on past any that can't be read
+func run(u *util.Utility, s settings) {
+	for _, fn := range s.filenames {
+		if err := writeFile(u, fn); err != nil {
+			u.Warn(err)
+		}
 	}
+}
 
-	for _, fn := range filenames {
-		err := writeFile(fn)
-		if err != nil {
-			os.Stderr.Write([]byte(fmt.Sprintf("Error encountered while attempting to read from %s: %s\n", fn, err)))
-			break
-		}
+func main() {
+	u := util.New("cat", "usage: cat [-u] [file...]")
+	s, err := parseSettings(os.Args[1:])
+	if err != nil {
+		u.UsageError(err, util.UsageStatus)
 	}
+	run(u, s)
+	u.Exit(0)
 }
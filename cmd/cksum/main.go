@@ -1,40 +1,164 @@
 package main
 
 import (
+	"bufio"
 	"fmt"
-	"io/ioutil"
+	"hash"
+	"io"
 	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fwip/posix-utils/pkg/cksum"
+	"github.com/fwip/posix-utils/pkg/flag"
+	"github.com/fwip/posix-utils/pkg/util"
 )
 
-func die(format string, args ...interface{}) {
-	fmt.Fprintf(os.Stderr, format, args...)
-	os.Exit(1)
+// algorithm is a checksum, and how the historical utility that computed it
+// wrote its result
+type algorithm struct {
+	newHash func() hash.Hash32
+	block   int64  // The size is counted in blocks of this many bytes
+	format  string // How the checksum is written
 }
 
-func run(filename string) {
-	reader := os.Stdin
-	if filename != "-" {
-		f, err := os.Open(filename)
-		if err != nil {
-			die("couldn't open file %s", err)
-		}
-		defer f.Close()
-		reader = f
+// algorithms maps the historical -o values to checksums. The BSD sum wrote
+// five digits and counted 1 KiB blocks, and the System V sum counted
+// 512-byte blocks. The POSIX CRC is the default.
+var algorithms = map[string]algorithm{
+	"1": {cksum.NewBSD, 1024, "%05d"},
+	"2": {cksum.NewSysV, 512, "%d"},
+	"3": {cksum.New, 1, "%d"},
+}
+
+type settings struct {
+	alg   algorithm
+	check bool
+	files []string
+}
+
+// algorithmFlag is the -o option, which selects a checksum
+type algorithmFlag struct {
+	dest *algorithm
+}
+
+func (a algorithmFlag) Set(value string) error {
+	alg, ok := algorithms[value]
+	if !ok {
+		return fmt.Errorf("unknown algorithm: %s", value)
+	}
+	*a.dest = alg
+	return nil
+}
+
+func (a algorithmFlag) String() string { return "" }
+
+func parseSettings(args []string) (settings, error) {
+	s := settings{alg: algorithms["3"]}
+	p := flag.Parser{Input: args}
+	p.BoolVar(&s.check, 'c', "check the sums in each file")
+	p.Var(algorithmFlag{&s.alg}, 'o', "use the historical `algorithm` 1, 2 or 3")
+	operands, err := p.Parse()
+	if err != nil {
+		return s, err
 	}
-	bytes, err := ioutil.ReadAll(reader)
+	s.files = util.Operands(operands)
+	return s, nil
+}
+
+// sum returns the checksum and size of the named file, the size in the
+// algorithm's blocks, rounded up
+func sum(u *util.Utility, s settings, filename string) (uint32, int64, error) {
+	reader, err := u.Open(filename)
 	if err != nil {
-		die("couldn't read input %s", err)
+		return 0, 0, err
 	}
+	defer reader.Close()
 
-	fmt.Printf("%d %d %s\n", memcrc(bytes), len(bytes), filename)
+	h := s.alg.newHash()
+	n, err := io.Copy(h, reader)
+	if err != nil {
+		return 0, 0, err
+	}
+	return h.Sum32(), (n + s.alg.block - 1) / s.alg.block, nil
+}
+
+func run(u *util.Utility, s settings, filename string) error {
+	crc, n, err := sum(u, s, filename)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(u.Stdout, s.alg.format+" %d %s\n", crc, n, filename)
+	return nil
+}
+
+// check verifies every "checksum size filename" line in the named list, as
+// written by run with the same algorithm. It reports whether every file
+// matched.
+func check(u *util.Utility, s settings, list string) (bool, error) {
+	reader, err := u.Open(list)
+	if err != nil {
+		return false, err
+	}
+	defer reader.Close()
+
+	ok := true
+	scanner := bufio.NewScanner(reader)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		// The filename may itself contain spaces, so only split twice
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) != 3 {
+			return false, fmt.Errorf("%s: %d: improperly formatted line", list, lineNo)
+		}
+		wantCrc, err1 := strconv.ParseUint(fields[0], 10, 32)
+		wantLen, err2 := strconv.ParseInt(fields[1], 10, 64)
+		if err1 != nil || err2 != nil {
+			return false, fmt.Errorf("%s: %d: improperly formatted line", list, lineNo)
+		}
+		filename := fields[2]
+
+		crc, n, err := sum(u, s, filename)
+		if err != nil {
+			u.Warn(err)
+			fmt.Fprintf(u.Stdout, "%s: FAILED open or read\n", filename)
+			ok = false
+			continue
+		}
+		if uint32(wantCrc) != crc || wantLen != n {
+			fmt.Fprintf(u.Stdout, "%s: FAILED\n", filename)
+			ok = false
+			continue
+		}
+		fmt.Fprintf(u.Stdout, "%s: OK\n", filename)
+	}
+	return ok, scanner.Err()
 }
 
 func main() {
-	files := os.Args[1:]
-	if len(files) == 0 {
-		files = append(files, "-")
+	u := util.New("cksum", "usage: cksum [-c] [-o 1|2|3] [file...]")
+	s, err := parseSettings(os.Args[1:])
+	if err != nil {
+		u.UsageError(err, util.UsageStatus)
 	}
-	for _, f := range files {
-		run(f)
+
+	for _, f := range s.files {
+		if s.check {
+			ok, err := check(u, s, f)
+			if err != nil {
+				u.Warn(err)
+			}
+			if !ok {
+				u.SetStatus(1)
+			}
+			continue
+		}
+		if err := run(u, s, f); err != nil {
+			u.Warn(err)
+		}
 	}
+	u.Exit(0)
 }
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fwip/posix-utils/pkg/util"
+)
+
+func TestRun(t *testing.T) {
+	big := strings.Repeat("x", 1000003)
+	tests := []struct {
+		o     string
+		input string
+		want  string
+	}{
+		{"3", "hello\n", "3015617425 6 -\n"},
+		{"1", "hello\n", "36979 1 -\n"},
+		{"2", "hello\n", "542 1 -\n"},
+		{"1", "", "00000 0 -\n"},
+		{"1", big, "53807 977 -\n"},
+		{"2", big, "5775 1954 -\n"},
+	}
+	for _, tt := range tests {
+		s, err := parseSettings([]string{"-o", tt.o})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var stdout bytes.Buffer
+		u := &util.Utility{Name: "cksum", Stdin: strings.NewReader(tt.input), Stdout: &stdout, Stderr: &stdout}
+		if err := run(u, s, "-"); err != nil || stdout.String() != tt.want {
+			t.Errorf("-o %s, %d bytes: got %q, %v, expected %q", tt.o, len(tt.input), stdout.String(), err, tt.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cksum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "a file")
+	if err := ioutil.WriteFile(file, []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		o    string
+		list string
+		ok   bool
+	}{
+		{"3", "3015617425 6 " + file, true},
+		{"3", "3015617425 7 " + file, false},
+		{"1", "36979 1 " + file, true},
+		{"1", "36979 6 " + file, false},
+		{"2", "542 1 " + file, true},
+	}
+	for _, tt := range tests {
+		s, err := parseSettings([]string{"-c", "-o", tt.o})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var stdout bytes.Buffer
+		u := &util.Utility{Name: "cksum", Stdin: strings.NewReader(tt.list + "\n"), Stdout: &stdout, Stderr: &stdout}
+		if ok, err := check(u, s, "-"); ok != tt.ok || err != nil {
+			t.Errorf("-o %s %q: got %v, %v, %q", tt.o, tt.list, ok, err, stdout.String())
+		}
+	}
+}
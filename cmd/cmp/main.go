@@ -2,117 +2,243 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"strconv"
+
+	"github.com/fwip/posix-utils/pkg/flag"
+	"github.com/fwip/posix-utils/pkg/util"
+)
+
+// Exit statuses, as specified by POSIX
+const (
+	exitSame    = 0
+	exitDiffer  = 1
+	exitTrouble = 2
 )
 
+// blockSize is how much of each file is compared at a time
+const blockSize = 64 * 1024
+
 type settings struct {
 	writeAll bool
 	silent   bool
 	file1    string
 	file2    string
+	skip1    int64
+	skip2    int64
+	limit    int64 // Maximum number of bytes to compare; -1 means no limit
 }
 
+// parseOffset reads a byte count, which is always decimal
+func parseOffset(s string) (int64, error) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid byte count: %s", s)
+	}
+	return n, nil
+}
+
+// byteCount is the -n option
+type byteCount struct {
+	dest *int64
+}
+
+func (b byteCount) Set(value string) error {
+	n, err := parseOffset(value)
+	if err == nil {
+		*b.dest = n
+	}
+	return err
+}
+
+func (b byteCount) String() string { return strconv.FormatInt(*b.dest, 10) }
+
 func parseSettings(args []string) (settings, error) {
-	s := settings{}
-	for i := 0; i < len(args); i++ {
-		a := args[i]
-		switch a {
-		case "-l":
-			s.writeAll = true
-		case "-s":
-			s.silent = true
-		default:
-			if s.file1 == "" {
-				s.file1 = a
-			} else if s.file2 == "" {
-				s.file2 = a
-			} else {
-				return s, fmt.Errorf("too many files: %s", a)
-			}
+	s := settings{limit: -1}
+	p := flag.Parser{Input: args}
+	p.BoolVar(&s.writeAll, 'l', "write every differing byte")
+	p.BoolVar(&s.silent, 's', "write nothing")
+	p.Var(byteCount{&s.limit}, 'n', "compare at most `bytes` bytes")
+	operands, err := p.Parse()
+	if err != nil {
+		return s, err
+	}
+
+	if s.writeAll && s.silent {
+		return s, fmt.Errorf("options -l and -s are mutually exclusive")
+	}
+	if len(operands) < 2 {
+		return s, fmt.Errorf("missing operand")
+	}
+	if len(operands) > 4 {
+		return s, fmt.Errorf("extra operand: %s", operands[4])
+	}
+	s.file1, s.file2 = operands[0], operands[1]
+	if len(operands) > 2 {
+		if s.skip1, err = parseOffset(operands[2]); err != nil {
+			return s, err
+		}
+	}
+	if len(operands) > 3 {
+		if s.skip2, err = parseOffset(operands[3]); err != nil {
+			return s, err
 		}
 	}
 	return s, nil
 }
 
-func open(filename string) (io.ReadCloser, error) {
-	if filename == "-" {
-		return os.Stdin, nil
+// skip discards the first n bytes of r, seeking past them when possible
+func skip(r io.Reader, n int64) error {
+	if n == 0 {
+		return nil
 	}
-	return os.Open(filename)
+	if seeker, ok := r.(io.Seeker); ok {
+		if _, err := seeker.Seek(n, io.SeekCurrent); err == nil {
+			return nil
+		}
+	}
+	_, err := io.CopyN(ioutil.Discard, r, n)
+	if err == io.EOF {
+		// Skipping past the end leaves nothing to compare, which is not an error
+		return nil
+	}
+	return err
 }
 
-func cmp(s settings) error {
-	f1, err := open(s.file1)
-	if err != nil {
-		return fmt.Errorf("could not open %s", s.file1)
+// readBlock fills buf as far as possible, reporting whether r is exhausted
+func readBlock(r io.Reader, buf []byte) (int, bool, error) {
+	n, err := io.ReadFull(r, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return n, true, nil
 	}
-	defer f1.Close()
-	f2, err := open(s.file2)
-	if err != nil {
-		return fmt.Errorf("could not open %s", s.file2)
+	return n, false, err
+}
+
+// comparer holds the state of a comparison between two streams
+type comparer struct {
+	s      settings
+	out    *bufio.Writer
+	errOut io.Writer
+}
+
+// compare reads r1 and r2 to the first difference (or to the end, when
+// writing all differences) and reports whether they differ.
+func (c comparer) compare(r1, r2 io.Reader) (bool, error) {
+	s := c.s
+	size := int64(blockSize)
+	if s.limit >= 0 && s.limit < size {
+		size = s.limit
 	}
-	defer f2.Close()
+	buf1 := make([]byte, size)
+	buf2 := make([]byte, size)
 
-	buf1 := bufio.NewReader(f1)
-	buf2 := bufio.NewReader(f2)
-	l := 1
-	for i := 1; err == nil; i++ {
-		b1, err1 := buf1.ReadByte()
-		b2, err2 := buf2.ReadByte()
-
-		if err1 == nil && err2 == nil {
-			if b1 != b2 {
-				if !s.silent {
-					if s.writeAll {
-						fmt.Printf("%d %o %o\n", i, b1, b2)
-					} else {
-						fmt.Printf("%s %s differ: char %d, line %d\n", s.file1, s.file2, i, l)
-					}
+	var offset int64 // Bytes compared so far
+	var line int64 = 1
+	differ := false
+	remaining := s.limit
+	for remaining != 0 {
+		if remaining > 0 && remaining < int64(len(buf1)) {
+			buf1 = buf1[:remaining]
+			buf2 = buf2[:remaining]
+		}
+		n1, eof1, err := readBlock(r1, buf1)
+		if err != nil {
+			return differ, fmt.Errorf("%s: %s", s.file1, err)
+		}
+		n2, eof2, err := readBlock(r2, buf2)
+		if err != nil {
+			return differ, fmt.Errorf("%s: %s", s.file2, err)
+		}
+
+		n := n1
+		if n2 < n {
+			n = n2
+		}
+		b1, b2 := buf1[:n], buf2[:n]
+		if !bytes.Equal(b1, b2) {
+			differ = true
+			for i := 0; i < n; i++ {
+				if b1[i] == b2[i] {
+					continue
 				}
 				if !s.writeAll {
-					os.Exit(1)
+					if !s.silent {
+						line += int64(bytes.Count(b1[:i], []byte{'\n'}))
+						fmt.Fprintf(c.out, "%s %s differ: char %d, line %d\n", s.file1, s.file2, offset+int64(i)+1, line)
+					}
+					return true, nil
 				}
+				fmt.Fprintf(c.out, "%d %o %o\n", offset+int64(i)+1, b1[i], b2[i])
 			}
-			if b1 == byte('\n') {
-				l++
-			}
-			continue
+		} else if !s.writeAll {
+			line += int64(bytes.Count(b1, []byte{'\n'}))
 		}
-		if err1 == io.EOF && err2 == io.EOF {
-			break
+		offset += int64(n)
+		if remaining > 0 {
+			remaining -= int64(n)
 		}
-		if err1 != nil {
-			if err1 == io.EOF {
-				fmt.Fprintf(os.Stderr, "cmp: EOF on %s%s\n", s.file1, "")
-				os.Exit(1)
+
+		if n1 != n2 {
+			shorter := s.file1
+			if n2 < n1 {
+				shorter = s.file2
 			}
-			return fmt.Errorf("Error reading %s: %s", s.file1, err1)
-		}
-		if err2 != nil {
-			if err2 == io.EOF {
-				fmt.Fprintf(os.Stderr, "cmp: EOF on %s%s\n", s.file2, "")
-				os.Exit(1)
+			if !s.silent {
+				c.out.Flush()
+				fmt.Fprintf(c.errOut, "cmp: EOF on %s\n", shorter)
 			}
-			return fmt.Errorf("Error reading %s: %s", s.file2, err2)
+			return true, nil
 		}
+		if eof1 || eof2 {
+			break
+		}
+	}
+	return differ, nil
+}
+
+func cmp(u *util.Utility, s settings) (bool, error) {
+	if s.file1 == "-" && s.file2 == "-" {
+		return false, fmt.Errorf("cannot compare standard input to itself")
+	}
+	f1, err := u.Open(s.file1)
+	if err != nil {
+		return false, err
+	}
+	defer f1.Close()
+	f2, err := u.Open(s.file2)
+	if err != nil {
+		return false, err
+	}
+	defer f2.Close()
 
+	if err = skip(f1, s.skip1); err != nil {
+		return false, fmt.Errorf("%s: %s", s.file1, err)
+	}
+	if err = skip(f2, s.skip2); err != nil {
+		return false, fmt.Errorf("%s: %s", s.file2, err)
 	}
 
-	return nil
+	c := comparer{s: s, out: bufio.NewWriter(u.Stdout), errOut: u.Stderr}
+	defer c.out.Flush()
+	return c.compare(f1, f2)
 }
 
 func main() {
-
+	u := util.New("cmp", "usage: cmp [-l|-s] [-n bytes] file1 file2 [skip1 [skip2]]")
 	s, err := parseSettings(os.Args[1:])
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %s", err)
-		os.Exit(2)
+		u.UsageError(err, exitTrouble)
 	}
-	err = cmp(s)
+	differ, err := cmp(u, s)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %s", err)
-		os.Exit(2)
+		u.Fatal(err, exitTrouble)
+	}
+	if differ {
+		u.Exit(exitDiffer)
 	}
+	u.Exit(exitSame)
 }
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fwip/posix-utils/pkg/util"
+)
+
+func TestParseSettings(t *testing.T) {
+	tests := []struct {
+		args []string
+		want settings
+	}{
+		{[]string{"a", "b"}, settings{file1: "a", file2: "b", limit: -1}},
+		{[]string{"-l", "-n", "16", "a", "b", "1", "2"}, settings{writeAll: true, file1: "a", file2: "b", skip1: 1, skip2: 2, limit: 16}},
+		{[]string{"-s", "-", "b", "010"}, settings{silent: true, file1: "-", file2: "b", skip1: 10, limit: -1}},
+	}
+	for _, tt := range tests {
+		s, err := parseSettings(tt.args)
+		if err != nil || !reflect.DeepEqual(s, tt.want) {
+			t.Errorf("%q: got %+v, %v", tt.args, s, err)
+		}
+	}
+	for _, args := range [][]string{{"a"}, {"-l", "-s", "a", "b"}, {"a", "b", "-1"}, {"a", "b", "1", "2", "3"}, {"-n", "x", "a", "b"}, {"-n", "0x10", "a", "b"}, {"a", "b", "0x10"}} {
+		if _, err := parseSettings(args); err == nil {
+			t.Errorf("%q: expected an error", args)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		s      settings
+		a, b   string
+		differ bool
+		stdout string
+		stderr string
+	}{
+		{settings{limit: -1}, "abc", "abc", false, "", ""},
+		{settings{limit: -1}, "", "", false, "", ""},
+		{settings{limit: -1}, "ab\ncd\n", "ab\ncx\n", true, "a b differ: char 5, line 2\n", ""},
+		{settings{limit: -1}, "abc", "xbc", true, "a b differ: char 1, line 1\n", ""},
+		{settings{limit: 3}, "abcd", "abcx", false, "", ""},
+		{settings{limit: 4}, "abcd", "abcx", true, "a b differ: char 4, line 1\n", ""},
+		{settings{limit: 0}, "a", "b", false, "", ""},
+		{settings{writeAll: true, limit: -1}, "abc", "xbz", true, "1 141 170\n3 143 172\n", ""},
+		{settings{silent: true, limit: -1}, "abc", "xbc", true, "", ""},
+		{settings{limit: -1}, "ab", "abc", true, "", "cmp: EOF on a\n"},
+		{settings{limit: -1}, "abc", "ab", true, "", "cmp: EOF on b\n"},
+		{settings{writeAll: true, limit: -1}, "xbc", "ab", true, "1 170 141\n", "cmp: EOF on b\n"},
+		{settings{silent: true, limit: -1}, "ab", "abc", true, "", ""},
+		{settings{limit: 2}, "ab", "abc", false, "", ""},
+	}
+	for _, tt := range tests {
+		tt.s.file1, tt.s.file2 = "a", "b"
+		var stdout, stderr bytes.Buffer
+		c := comparer{s: tt.s, out: bufio.NewWriter(&stdout), errOut: &stderr}
+		differ, err := c.compare(strings.NewReader(tt.a), strings.NewReader(tt.b))
+		c.out.Flush()
+		if err != nil || differ != tt.differ || stdout.String() != tt.stdout || stderr.String() != tt.stderr {
+			t.Errorf("%+v %q %q: got %v, %v, %q, %q", tt.s, tt.a, tt.b, differ, err, stdout.String(), stderr.String())
+		}
+	}
+
+	// A long identical prefix still counts lines across blocks
+	a := strings.Repeat("x\n", blockSize) + "a"
+	b := strings.Repeat("x\n", blockSize) + "b"
+	var stdout bytes.Buffer
+	c := comparer{s: settings{file1: "a", file2: "b", limit: -1}, out: bufio.NewWriter(&stdout), errOut: &stdout}
+	differ, err := c.compare(strings.NewReader(a), strings.NewReader(b))
+	c.out.Flush()
+	if want := "a b differ: char 131073, line 65537\n"; err != nil || !differ || stdout.String() != want {
+		t.Errorf("got %v, %v, %q, expected %q", differ, err, stdout.String(), want)
+	}
+}
+
+func TestSkip(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "abcdef"},
+		{2, "cdef"},
+		{6, ""},
+		{10, ""},
+	}
+	for _, tt := range tests {
+		// Once as a file that can seek past the bytes, and once as a stream
+		// that has to read them
+		for _, r := range []io.Reader{strings.NewReader("abcdef"), struct{ io.Reader }{strings.NewReader("abcdef")}} {
+			if err := skip(r, tt.n); err != nil {
+				t.Errorf("%d: %s", tt.n, err)
+				continue
+			}
+			if rest, _ := ioutil.ReadAll(r); string(rest) != tt.want {
+				t.Errorf("%d: got %q, expected %q", tt.n, rest, tt.want)
+			}
+		}
+	}
+}
+
+func TestCmp(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "file")
+	if err := ioutil.WriteFile(file, []byte("xxabc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		s      settings
+		stdin  string
+		differ bool
+		stdout string
+	}{
+		{settings{file1: file, file2: "-", skip1: 2, limit: -1}, "abc", false, ""},
+		{settings{file1: "-", file2: file, skip1: 1, skip2: 2, limit: -1}, "-abc", false, ""},
+		{settings{file1: file, file2: "-", skip1: 1, limit: -1}, "abc", true, file + " - differ: char 1, line 1\n"},
+		{settings{file1: file, file2: "-", skip1: 10, limit: -1}, "", false, ""},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		u := &util.Utility{Name: "cmp", Stdin: strings.NewReader(tt.stdin), Stdout: &stdout, Stderr: &stderr}
+		differ, err := cmp(u, tt.s)
+		if err != nil || differ != tt.differ || stdout.String() != tt.stdout {
+			t.Errorf("%+v: got %v, %v, %q, %q", tt.s, differ, err, stdout.String(), stderr.String())
+		}
+	}
+
+	u := &util.Utility{Name: "cmp", Stdin: strings.NewReader(""), Stdout: ioutil.Discard, Stderr: ioutil.Discard}
+	for _, s := range []settings{{file1: "-", file2: "-"}, {file1: file, file2: filepath.Join(dir, "missing")}} {
+		if _, err := cmp(u, s); err == nil {
+			t.Errorf("%+v: expected an error", s)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxPos stands in for the end of the line in open-ended ranges
+const maxPos = math.MaxInt32
+
+// span is an inclusive range of positions, counting from one
+type span struct {
+	lo, hi int
+}
+
+// list is a sorted set of non-overlapping spans
+type list []span
+
+// parseList parses a POSIX cut list, such as "1,3-5,7-". Elements are
+// separated by commas or blanks, and each is either a number, a range
+// "N-M", or a range open at one end ("-M" or "N-").
+func parseList(s string) (list, error) {
+	var l list
+	elements := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	if len(elements) == 0 {
+		return nil, fmt.Errorf("empty list")
+	}
+	for _, e := range elements {
+		sp := span{lo: 1, hi: maxPos}
+		var err error
+		dash := strings.IndexByte(e, '-')
+		switch {
+		case dash < 0:
+			sp.lo, err = parsePosition(e)
+			sp.hi = sp.lo
+		case e == "-":
+			err = fmt.Errorf("invalid range: %s", e)
+		default:
+			if dash > 0 {
+				sp.lo, err = parsePosition(e[:dash])
+			}
+			if err == nil && dash < len(e)-1 {
+				sp.hi, err = parsePosition(e[dash+1:])
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if sp.hi < sp.lo {
+			return nil, fmt.Errorf("invalid decreasing range: %s", e)
+		}
+		l = append(l, sp)
+	}
+	return l.normalize(), nil
+}
+
+func parsePosition(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || s[0] == '+' || s[0] == '-' {
+		return 0, fmt.Errorf("invalid position: %s", s)
+	}
+	if n < 1 {
+		return 0, fmt.Errorf("positions are numbered from 1: %s", s)
+	}
+	return n, nil
+}
+
+// normalize sorts the spans and merges any that overlap or touch
+func (l list) normalize() list {
+	sort.Slice(l, func(i, j int) bool { return l[i].lo < l[j].lo })
+	var out list
+	for _, sp := range l {
+		if n := len(out); n > 0 && sp.lo <= out[n-1].hi+1 {
+			if sp.hi > out[n-1].hi {
+				out[n-1].hi = sp.hi
+			}
+			continue
+		}
+		out = append(out, sp)
+	}
+	return out
+}
+
+// contains reports whether position p is in the list
+func (l list) contains(p int) bool {
+	i := sort.Search(len(l), func(i int) bool { return l[i].hi >= p })
+	return i < len(l) && l[i].lo <= p
+}
+
+// last returns the highest position in the list
+func (l list) last() int {
+	return l[len(l)-1].hi
+}
@@ -1,26 +1,33 @@
 package main
 
 import (
-	"flag"
+	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
+	"unicode/utf8"
+
+	"github.com/fwip/posix-utils/pkg/flag"
+	"github.com/fwip/posix-utils/pkg/util"
 )
 
-//type cutMode uint8
-//
-//const (
-//	fields cutMode = iota
-//	bytes
-//	chars
-//)
-//
-//type opts struct {
-//	mode cutMode
-//	list string
-//	delimiter string
-//	nosplit bool
-//	suppress bool
-//}
+type cutMode uint8
+
+const (
+	fields cutMode = iota
+	bytesMode
+	chars
+)
+
+type opts struct {
+	mode      cutMode
+	list      list
+	delimiter []byte
+	nosplit   bool
+	suppress  bool
+	multibyte bool // Characters are UTF-8 sequences rather than single bytes
+}
 
 func checkArgs(fields, bytes, chars, delim string, split, suppress bool) error {
 	modesSpecified := 0
@@ -33,31 +40,216 @@ func checkArgs(fields, bytes, chars, delim string, split, suppress bool) error {
 	if chars != "" {
 		modesSpecified++
 	}
-	if modesSpecified > 0 {
-		return fmt.Errorf("only specify one of -f, -b, and -c")
+	if modesSpecified > 1 {
+		return fmt.Errorf("specify only one of -b, -c, or -f")
 	}
 	if modesSpecified == 0 {
-		return fmt.Errorf("gotta specify at least one of -f, -b, and -c")
+		return fmt.Errorf("must specify one of -b, -c, or -f")
+	}
+	if fields == "" && (delim != "\t" || suppress) {
+		return fmt.Errorf("-d and -s may only be used with -f")
 	}
 	return nil
 }
 
+// charBounds returns the byte offset at which each character of line starts,
+// followed by len(line).
+func charBounds(line []byte) []int {
+	bounds := make([]int, 0, len(line)+1)
+	for i := 0; i < len(line); {
+		bounds = append(bounds, i)
+		_, size := utf8.DecodeRune(line[i:])
+		i += size
+	}
+	return append(bounds, len(line))
+}
+
+// cutBytes writes the selected bytes of line. When characters mustn't be
+// split, the low end of each range moves back to the start of its character
+// and the high end moves back to the end of the previous character if it
+// falls inside one.
+func (o opts) cutBytes(line []byte, w *bufio.Writer) {
+	var bounds []int
+	if o.nosplit && o.multibyte {
+		bounds = charBounds(line)
+	}
+	for _, sp := range o.list {
+		if sp.lo > len(line) {
+			break
+		}
+		lo, hi := sp.lo-1, sp.hi
+		if hi > len(line) {
+			hi = len(line)
+		}
+		if bounds != nil {
+			// Index of the first boundary after lo, and of the first at or after hi
+			i := findBound(bounds, lo+1) - 1
+			lo = bounds[i]
+			if j := findBound(bounds, hi); bounds[j] != hi {
+				hi = bounds[j-1]
+			}
+		}
+		if lo < hi {
+			w.Write(line[lo:hi])
+		}
+	}
+}
+
+// findBound returns the index of the first boundary >= n
+func findBound(bounds []int, n int) int {
+	lo, hi := 0, len(bounds)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if bounds[mid] < n {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// cutChars writes the selected characters of line
+func (o opts) cutChars(line []byte, w *bufio.Writer) {
+	if !o.multibyte {
+		o.cutBytes(line, w)
+		return
+	}
+	bounds := charBounds(line)
+	numChars := len(bounds) - 1
+	for _, sp := range o.list {
+		if sp.lo > numChars {
+			break
+		}
+		hi := sp.hi
+		if hi > numChars {
+			hi = numChars
+		}
+		w.Write(line[bounds[sp.lo-1]:bounds[hi]])
+	}
+}
+
+// cutFields writes the selected fields of line, or reports false if the line
+// should be suppressed.
+func (o opts) cutFields(line []byte, w *bufio.Writer) bool {
+	if !bytes.Contains(line, o.delimiter) {
+		if o.suppress {
+			return false
+		}
+		w.Write(line)
+		return true
+	}
+	first := true
+	for i := 1; i <= o.list.last(); i++ {
+		var field []byte
+		end := bytes.Index(line, o.delimiter)
+		if end < 0 {
+			field, line = line, nil
+		} else {
+			field, line = line[:end], line[end+len(o.delimiter):]
+		}
+		if o.list.contains(i) {
+			if !first {
+				w.Write(o.delimiter)
+			}
+			w.Write(field)
+			first = false
+		}
+		if line == nil {
+			break
+		}
+	}
+	return true
+}
+
+func (o opts) cutLine(line []byte, w *bufio.Writer) {
+	switch o.mode {
+	case bytesMode:
+		o.cutBytes(line, w)
+	case chars:
+		o.cutChars(line, w)
+	case fields:
+		if !o.cutFields(line, w) {
+			return
+		}
+	}
+	w.WriteByte('\n')
+}
+
+func cut(o opts, r io.Reader, w *bufio.Writer) error {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			line = bytes.TrimSuffix(line, []byte{'\n'})
+			o.cutLine(line, w)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
 func main() {
-	optFields := flag.String("f", "", "Fields to choose")
-	optBytes := flag.String("b", "", "Bytes to choose")
-	optChars := flag.String("c", "", "Chars to choose")
-	optDelim := flag.String("d", "\t", "Delimiter")
-	optNoSplit := flag.Bool("n", false, "Don't split bytes")
-	optSuppress := flag.Bool("s", false, "Suppress delimiter-less lines")
-	flag.Parse()
-
-	err := checkArgs(*optFields, *optBytes, *optChars, *optDelim, *optNoSplit, *optSuppress)
+	u := util.New("cut", "usage: cut -b list [-n] [file...]\n"+
+		"       cut -c list [file...]\n"+
+		"       cut -f list [-d delim] [-s] [file...]")
+	var optFields, optBytes, optChars string
+	optDelim := "\t"
+	var optNoSplit, optSuppress bool
+	p := flag.Parser{Input: os.Args[1:]}
+	p.StringVar(&optFields, 'f', "Fields to choose")
+	p.StringVar(&optBytes, 'b', "Bytes to choose")
+	p.StringVar(&optChars, 'c', "Chars to choose")
+	p.StringVar(&optDelim, 'd', "Delimiter")
+	p.BoolVar(&optNoSplit, 'n', "Don't split characters")
+	p.BoolVar(&optSuppress, 's', "Suppress delimiter-less lines")
+	filenames, err := p.Parse()
+	if err == nil {
+		err = checkArgs(optFields, optBytes, optChars, optDelim, optNoSplit, optSuppress)
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %s", err)
-		os.Exit(1)
+		u.UsageError(err, util.UsageStatus)
 	}
 
-	if *optFields != "" {
-		return
+	o := opts{
+		delimiter: []byte(optDelim),
+		nosplit:   optNoSplit,
+		suppress:  optSuppress,
+		multibyte: util.Multibyte(),
+	}
+	spec := optFields
+	switch {
+	case optBytes != "":
+		o.mode, spec = bytesMode, optBytes
+	case optChars != "":
+		o.mode, spec = chars, optChars
+	}
+	o.list, err = parseList(spec)
+	if err == nil && o.mode == fields {
+		if n := utf8.RuneCount(o.delimiter); n != 1 || (!o.multibyte && len(o.delimiter) != 1) {
+			err = fmt.Errorf("the delimiter must be a single character")
+		}
+	}
+	if err != nil {
+		u.Fatal(err, 1)
+	}
+
+	out := bufio.NewWriter(u.Stdout)
+	for _, fn := range util.Operands(filenames) {
+		r, err := u.Open(fn)
+		if err == nil {
+			err = cut(o, r, out)
+			r.Close()
+		}
+		if err != nil {
+			out.Flush()
+			u.Warn(err)
+		}
 	}
+	out.Flush()
+	u.Exit(0)
 }
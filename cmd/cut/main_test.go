@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseList(t *testing.T) {
+	tests := []struct {
+		in   string
+		want list
+	}{
+		{"1", list{{1, 1}}},
+		{"1,3-5,7-", list{{1, 1}, {3, 5}, {7, maxPos}}},
+		{"-3", list{{1, 3}}},
+		{"5,1 2", list{{1, 2}, {5, 5}}},
+		{"2-4,3-6,8", list{{2, 6}, {8, 8}}},
+	}
+	for _, test := range tests {
+		got, err := parseList(test.in)
+		if err != nil {
+			t.Errorf("%q: %s", test.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got %v, wanted %v", test.in, got, test.want)
+		}
+	}
+
+	for _, bad := range []string{"", "0", "-", "3-1", "a", "1-b", "+1"} {
+		if _, err := parseList(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+var cutTests = []struct {
+	o     opts
+	spec  string
+	input string
+	want  string
+}{
+	{opts{mode: bytesMode}, "1,3-4", "abcdef\nxy\n", "acd\nx\n"},
+	{opts{mode: bytesMode}, "4-", "abcdef", "def\n"},
+	{opts{mode: chars}, "2-3", "abcd\n", "bc\n"},
+	{opts{mode: chars, multibyte: true}, "2-3", "añbç\n", "ñb\n"},
+	{opts{mode: bytesMode, multibyte: true}, "2-3", "añb\n", "\xc3\xb1\n"},
+	{opts{mode: bytesMode, multibyte: true, nosplit: true}, "1-2", "añb\n", "a\n"},
+	{opts{mode: bytesMode, multibyte: true, nosplit: true}, "3-4", "añb\n", "ñb\n"},
+	{opts{mode: fields, delimiter: []byte(":")}, "1,3", "a:b:c:d\nnone\n", "a:c\nnone\n"},
+	{opts{mode: fields, delimiter: []byte(":"), suppress: true}, "2-", "a:b:c\nnone\n", "b:c\n"},
+	{opts{mode: fields, delimiter: []byte(":")}, "3", "a:b\n", "\n"},
+	{opts{mode: fields, delimiter: []byte("·"), multibyte: true}, "2", "a·b·c\n", "b\n"},
+}
+
+func TestCut(t *testing.T) {
+	for _, test := range cutTests {
+		var err error
+		test.o.list, err = parseList(test.spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out strings.Builder
+		w := bufio.NewWriter(&out)
+		if err = cut(test.o, strings.NewReader(test.input), w); err != nil {
+			t.Fatal(err)
+		}
+		w.Flush()
+		if out.String() != test.want {
+			t.Errorf("%+v %q on %q: got %q, wanted %q", test.o, test.spec, test.input, out.String(), test.want)
+		}
+	}
+}
+
+func TestCheckArgs(t *testing.T) {
+	if err := checkArgs("1", "", "", "\t", false, false); err != nil {
+		t.Errorf("-f 1: %s", err)
+	}
+	if err := checkArgs("1", "2", "", "\t", false, false); err == nil {
+		t.Errorf("-f 1 -b 2: expected an error")
+	}
+	if err := checkArgs("", "", "", "\t", false, false); err == nil {
+		t.Errorf("no list: expected an error")
+	}
+	if err := checkArgs("", "1", "", ":", false, false); err == nil {
+		t.Errorf("-b 1 -d: expected an error")
+	}
+}
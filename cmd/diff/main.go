@@ -4,9 +4,10 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"strconv"
 
 	"github.com/fwip/posix-utils/pkg/diff"
+	"github.com/fwip/posix-utils/pkg/flag"
+	"github.com/fwip/posix-utils/pkg/util"
 )
 
 type settings struct {
@@ -23,65 +24,42 @@ type settings struct {
 
 func parseSettings(args []string) (settings, error) {
 	s := settings{contextSize: 3}
-	var err error
-
-	for i := 0; i < len(args); i++ {
-		a := args[i]
-
-		switch a {
-		case "-b":
-			s.ignoreTrailingWhitespace = true
-		case "-c":
-			s.provideContext = true
-		case "-C":
-			if i+1 >= len(args) {
-				return s, fmt.Errorf("ah heck I though that a number was gonna follow %s", a)
-			}
-			s.provideContext = true
-			s.contextSize, err = strconv.Atoi(args[i+1])
-			i++
-		case "-e":
-			s.edFormat = true
-		case "-f":
-			s.fFormat = true
-		case "-r":
-			s.recursive = true
-		case "-u":
-			s.unifiedContext = true
-		case "-U":
-			if i+1 >= len(args) {
-				return s, fmt.Errorf("ah heck I though that a number was gonna follow %s", a)
-			}
-			s.unifiedContext = true
-			s.contextSize, err = strconv.Atoi(args[i+1])
-			if err != nil {
-				return s, err
-			}
-			i++
-		default:
-			if s.file1 == "" {
-				s.file1 = a
-			} else if s.file2 == "" {
-				s.file2 = a
-			} else {
-				return s, fmt.Errorf("aww jeez you can only compare two files, %s is the third", a)
-			}
-		}
-
+	context, unified := int64(-1), int64(-1)
+	p := flag.Parser{Input: args}
+	p.BoolVar(&s.ignoreTrailingWhitespace, 'b', "ignore changes in trailing white space")
+	p.BoolVar(&s.provideContext, 'c', "write three lines of context")
+	p.IntVar(&context, 'C', "write `n` lines of context")
+	p.BoolVar(&s.edFormat, 'e', "write an ed script")
+	p.BoolVar(&s.fFormat, 'f', "write a script in reverse order")
+	p.BoolVar(&s.recursive, 'r', "compare directories recursively")
+	p.BoolVar(&s.unifiedContext, 'u', "write three lines of unified context")
+	p.IntVar(&unified, 'U', "write `n` lines of unified context")
+	operands, err := p.Parse()
+	if err != nil {
+		return s, err
 	}
-
-	if s.file1 == "" {
-		return s, fmt.Errorf("oh no you forgot to give any files")
+	if context >= 0 {
+		s.provideContext = true
+		s.contextSize = int(context)
 	}
-	if s.file2 == "" {
-		return s, fmt.Errorf("oh no you forgot to give the second file")
+	if unified >= 0 {
+		s.unifiedContext = true
+		s.contextSize = int(unified)
 	}
 
+	switch len(operands) {
+	case 0, 1:
+		return s, fmt.Errorf("expected two files to compare")
+	case 2:
+	default:
+		return s, fmt.Errorf("extra operand: %s", operands[2])
+	}
+	s.file1, s.file2 = operands[0], operands[1]
 	return s, nil
 }
 
-func readLines(fn string) ([]string, error) {
-	f, err := os.Open(fn)
+func readLines(u *util.Utility, fn string) ([]string, error) {
+	f, err := u.Open(fn)
 	if err != nil {
 		return nil, err
 	}
@@ -95,15 +73,21 @@ func readLines(fn string) ([]string, error) {
 }
 
 func main() {
+	u := util.New("diff", "usage: diff [-c|-e|-f|-u|-C n|-U n] [-br] file1 file2")
 	settings, err := parseSettings(os.Args[1:])
 	if err != nil {
-		fmt.Fprintf(os.Stdout, "Oh heck an error: %s\n", err)
-		os.Exit(1)
+		u.UsageError(err, util.UsageStatus)
 	}
 
-	l1, _ := readLines(settings.file1)
-	l2, _ := readLines(settings.file2)
+	l1, err := readLines(u, settings.file1)
+	if err != nil {
+		u.Fatal(err, 2)
+	}
+	l2, err := readLines(u, settings.file2)
+	if err != nil {
+		u.Fatal(err, 2)
+	}
 	changes := diff.Diff(l1, l2)
 
-	fmt.Println(diff.Format(changes))
+	fmt.Fprintln(u.Stdout, diff.Format(changes))
 }
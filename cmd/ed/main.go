@@ -1,15 +1,21 @@
 package main
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"strings"
 
 	"github.com/fwip/posix-utils/pkg/ed"
+	"github.com/fwip/posix-utils/pkg/flag"
+	"github.com/fwip/posix-utils/pkg/util"
 )
 
-func process(in io.Reader, out io.Writer, edit *string) {
+func process(in io.Reader, out io.Writer, edit *string, prompt string) {
 	e := &ed.Itor{}
+	if prompt != "" {
+		e.SetPrompt(prompt)
+	}
 	if edit != nil {
 		// If an argument is supplid on the command-line, open it in the editor
 		editCmd := strings.NewReader("e " + *edit + "\n")
@@ -22,9 +28,24 @@ func process(in io.Reader, out io.Writer, edit *string) {
 }
 
 func main() {
+	u := util.New("ed", "usage: ed [-p string] [-s] [file]")
+	var quiet bool
+	var prompt string
+	p := flag.Parser{Input: os.Args[1:]}
+	p.StringVar(&prompt, 'p', "prompt for commands with string")
+	// Byte counts aren't written, so -s has nothing to suppress
+	p.BoolVar(&quiet, 's', "don't write byte counts")
+	operands, err := p.Parse()
+	if err == nil && len(operands) > 1 {
+		err = fmt.Errorf("extra operand: %s", operands[1])
+	}
+	if err != nil {
+		u.UsageError(err, util.UsageStatus)
+	}
+
 	var editFile *string
-	if len(os.Args) > 1 {
-		editFile = &os.Args[1]
+	if len(operands) > 0 {
+		editFile = &operands[0]
 	}
-	process(os.Stdin, os.Stdout, editFile)
+	process(u.Stdin, u.Stdout, editFile, prompt)
 }
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/fwip/posix-utils/pkg/flag"
+	"github.com/fwip/posix-utils/pkg/nls"
+	"github.com/fwip/posix-utils/pkg/util"
+)
+
+type settings struct {
+	catfile  string
+	msgfiles []string
+}
+
+func parseSettings(args []string) (settings, error) {
+	var s settings
+	p := flag.Parser{Input: args}
+	args, err := p.Parse()
+	if err != nil {
+		return s, err
+	}
+	if len(args) < 2 {
+		return s, fmt.Errorf("expected a catalog and at least one message file")
+	}
+	s.catfile, s.msgfiles = args[0], args[1:]
+	return s, nil
+}
+
+// readCatalog returns the existing catalog to add messages to, if any
+func readCatalog(path string) (*nls.Catalog, error) {
+	if path == "-" {
+		return &nls.Catalog{}, nil
+	}
+	c, err := nls.Load(path)
+	if os.IsNotExist(err) {
+		return &nls.Catalog{}, nil
+	}
+	return c, err
+}
+
+// write replaces the file at path, so that a reader never sees it half-written
+func write(path string, data []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), ".gencat")
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err == nil {
+		err = f.Chmod(0644)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// run merges the message files into the catalog, returning the exit status
+func run(u *util.Utility, s settings) int {
+	c, err := readCatalog(s.catfile)
+	if err != nil {
+		u.Warn(err)
+		return 1
+	}
+	for _, name := range s.msgfiles {
+		r, err := u.Open(name)
+		if err != nil {
+			u.Warn(err)
+			return 1
+		}
+		defer r.Close()
+		if name == "-" {
+			name = "stdin"
+		}
+		if err := c.ReadSource(r); err != nil {
+			u.Warnf("%s: %s", name, util.Message(err))
+			return 1
+		}
+	}
+
+	data, err := c.MarshalBinary()
+	if err == nil {
+		if s.catfile == "-" {
+			_, err = u.Stdout.Write(data)
+		} else {
+			err = write(s.catfile, data)
+		}
+	}
+	if err != nil {
+		u.Warn(err)
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	u := util.New("gencat", "usage: gencat catfile msgfile...")
+	s, err := parseSettings(os.Args[1:])
+	if err != nil {
+		u.UsageError(err, util.UsageStatus)
+	}
+	u.Exit(run(u, s))
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fwip/posix-utils/pkg/nls"
+	"github.com/fwip/posix-utils/pkg/util"
+)
+
+func TestParseSettings(t *testing.T) {
+	s, err := parseSettings([]string{"--", "cat", "a", "-"})
+	want := settings{catfile: "cat", msgfiles: []string{"a", "-"}}
+	if err != nil || !reflect.DeepEqual(s, want) {
+		t.Errorf("got %+v, %v", s, err)
+	}
+	for _, args := range [][]string{{}, {"cat"}, {"-x", "cat", "a"}} {
+		if _, err := parseSettings(args); err == nil {
+			t.Errorf("expected an error for %q", args)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gencat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	catfile := filepath.Join(dir, "test.cat")
+	msgfile := filepath.Join(dir, "test.msg")
+	if err := ioutil.WriteFile(msgfile, []byte("1 one\n2 two\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	u := &util.Utility{Name: "gencat", Stdout: &stdout, Stderr: &stderr}
+	if status := run(u, settings{catfile, []string{msgfile}}); status != 0 {
+		t.Fatalf("exit status %d: %s", status, stderr.String())
+	}
+
+	// A second run merges into the existing catalog
+	u.Stdin = strings.NewReader("2 deux\n$set 2\n1 autre\n")
+	if status := run(u, settings{catfile, []string{"-"}}); status != 0 {
+		t.Fatalf("exit status %d: %s", status, stderr.String())
+	}
+	c, err := nls.Load(catfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range []struct {
+		set, msg int
+		want     string
+	}{{1, 1, "one"}, {1, 2, "deux"}, {2, 1, "autre"}} {
+		if got := c.Get(m.set, m.msg, ""); got != m.want {
+			t.Errorf("message %d of set %d is %q, expected %q", m.msg, m.set, got, m.want)
+		}
+	}
+
+	// To standard output
+	stdout.Reset()
+	if status := run(u, settings{"-", []string{msgfile}}); status != 0 || !strings.HasPrefix(stdout.String(), "PUMC") {
+		t.Errorf("exit status %d, wrote %q", status, stdout.String())
+	}
+
+	stderr.Reset()
+	u.Stdin = strings.NewReader("1 ok\nbad\n")
+	if status := run(u, settings{catfile, []string{"-"}}); status == 0 ||
+		stderr.String() != "gencat: stdin: line 2: message number must be between 1 and 32767\n" {
+		t.Errorf("exit status %d: %s", status, stderr.String())
+	}
+}
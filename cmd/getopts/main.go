@@ -0,0 +1,120 @@
+// Command getopts parses options the way the shell's getopts does. Not being
+// part of the shell, it can't set the caller's variables, so it writes the
+// assignments for the shell to evaluate instead:
+//
+//	while eval "$(getopts ab: opt "$@")"; do
+//		case $opt in ...
+//	done
+//	shift $((OPTIND - 1))
+//
+// OPTIND, and OPTPOS, which holds the position within a bundle of options
+// like "-ab", are read from the environment, so they are exported. To start
+// again, unset both.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fwip/posix-utils/pkg/flag"
+	"github.com/fwip/posix-utils/pkg/util"
+)
+
+type settings struct {
+	optstring string
+	name      string
+	args      []string
+	optind    int
+	optpos    int
+}
+
+// isName reports whether s is a valid shell variable name
+func isName(s string) bool {
+	for i, c := range s {
+		if c != '_' && !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && (i == 0 || !(c >= '0' && c <= '9')) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// envInt reads a number from the environment, which defaults to def
+func envInt(getenv func(string) string, name string, def int) (int, error) {
+	v := getenv(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s: invalid %s", v, name)
+	}
+	return n, nil
+}
+
+func parseSettings(args []string, getenv func(string) string) (settings, error) {
+	var s settings
+	p := flag.Parser{Input: args}
+	args, err := p.Parse()
+	if err != nil {
+		return s, err
+	}
+	if len(args) < 2 {
+		return s, fmt.Errorf("expected an optstring and a name")
+	}
+	s.optstring, s.name, s.args = args[0], args[1], args[2:]
+	if !isName(s.name) {
+		return s, fmt.Errorf("%s: invalid name", s.name)
+	}
+	if s.optind, err = envInt(getenv, "OPTIND", 1); err != nil {
+		return s, err
+	}
+	if s.optpos, err = envInt(getenv, "OPTPOS", 0); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+// quote quotes s for the shell
+func quote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// run finds the next option and writes the assignments, returning the exit
+// status: 0 for an option, and 1 at the end of the options
+func run(s settings, stdout, stderr io.Writer) int {
+	g := flag.Getopts(s.optstring, s.args, s.optind)
+	g.Optpos = s.optpos
+	if g.Optind < 1 || g.Optind > len(g.Args) || g.Optpos >= len(g.Args[g.Optind-1]) {
+		g.Optpos = 0
+	}
+	c, ok := g.Next()
+	if g.Err != nil {
+		fmt.Fprintf(stderr, "getopts: %s\n", g.Err)
+	}
+
+	fmt.Fprintf(stdout, "%s=%s\n", s.name, quote(string(c)))
+	if g.HasOptarg {
+		fmt.Fprintf(stdout, "OPTARG=%s\n", quote(g.Optarg))
+	} else {
+		fmt.Fprintf(stdout, "unset OPTARG\n")
+	}
+	fmt.Fprintf(stdout, "OPTIND=%d OPTPOS=%d\nexport OPTIND OPTPOS\n", g.Optind, g.Optpos)
+	if !ok {
+		// eval's status is that of the last command, not getopts
+		fmt.Fprintf(stdout, "false\n")
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	u := util.New("getopts", "usage: getopts optstring name [arg...]")
+	s, err := parseSettings(os.Args[1:], os.Getenv)
+	if err != nil {
+		u.UsageError(err, util.UsageStatus)
+	}
+	u.Exit(run(s, u.Stdout, u.Stderr))
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func env(vars map[string]string) func(string) string {
+	return func(name string) string { return vars[name] }
+}
+
+func TestParseSettings(t *testing.T) {
+	s, err := parseSettings([]string{"ab:", "opt", "-a", "x"}, env(map[string]string{"OPTIND": "2", "OPTPOS": "1"}))
+	want := settings{"ab:", "opt", []string{"-a", "x"}, 2, 1}
+	if err != nil || !reflect.DeepEqual(s, want) {
+		t.Errorf("got %+v, %v", s, err)
+	}
+	s, err = parseSettings([]string{"--", "a", "_x1"}, env(nil))
+	want = settings{"a", "_x1", []string{}, 1, 0}
+	if err != nil || !reflect.DeepEqual(s, want) {
+		t.Errorf("got %+v, %v", s, err)
+	}
+	for _, args := range [][]string{{}, {"a"}, {"a", "1x"}, {"a", "x-y"}} {
+		if _, err := parseSettings(args, env(nil)); err == nil {
+			t.Errorf("expected an error for %q", args)
+		}
+	}
+	if _, err := parseSettings([]string{"a", "x"}, env(map[string]string{"OPTIND": "one"})); err == nil {
+		t.Errorf("expected an error for a bad OPTIND")
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		s              settings
+		status         int
+		stdout, stderr string
+	}{
+		{settings{"ab:", "opt", []string{"-ab", "it's"}, 1, 0}, 0,
+			"opt='a'\nunset OPTARG\nOPTIND=1 OPTPOS=2\nexport OPTIND OPTPOS\n", ""},
+		{settings{"ab:", "opt", []string{"-ab", "it's"}, 1, 2}, 0,
+			"opt='b'\nOPTARG='it'\\''s'\nOPTIND=3 OPTPOS=0\nexport OPTIND OPTPOS\n", ""},
+		{settings{"ab:", "opt", []string{"-ab", "it's"}, 3, 0}, 1,
+			"opt='?'\nunset OPTARG\nOPTIND=3 OPTPOS=0\nexport OPTIND OPTPOS\nfalse\n", ""},
+		{settings{"a", "opt", []string{"-x"}, 1, 0}, 0,
+			"opt='?'\nunset OPTARG\nOPTIND=2 OPTPOS=0\nexport OPTIND OPTPOS\n", "getopts: illegal option -- x\n"},
+		{settings{":a", "opt", []string{"-x"}, 1, 0}, 0,
+			"opt='?'\nOPTARG='x'\nOPTIND=2 OPTPOS=0\nexport OPTIND OPTPOS\n", ""},
+		// A stale OPTPOS past the end of the argument is ignored
+		{settings{"a", "opt", []string{"-a"}, 1, 5}, 0,
+			"opt='a'\nunset OPTARG\nOPTIND=2 OPTPOS=0\nexport OPTIND OPTPOS\n", ""},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		status := run(tt.s, &stdout, &stderr)
+		if status != tt.status || stdout.String() != tt.stdout || stderr.String() != tt.stderr {
+			t.Errorf("%+v: got status %d, stdout %q, stderr %q", tt.s, status, stdout.String(), stderr.String())
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fwip/posix-utils/pkg/ahocorasick"
+	"github.com/fwip/posix-utils/pkg/flag"
+	"github.com/fwip/posix-utils/pkg/regexes"
+	"github.com/fwip/posix-utils/pkg/util"
+)
+
+const usage = "usage: grep [-E|-F] [-c|-l|-q] [-insvx] -e pattern_list [-e pattern_list]...\n" +
+	"            [-f pattern_file]... [file...]\n" +
+	"       grep [-E|-F] [-c|-l|-q] [-insvx] [-e pattern_list]...\n" +
+	"            -f pattern_file [-f pattern_file]... [file...]\n" +
+	"       grep [-E|-F] [-c|-l|-q] [-insvx] pattern_list [file...]"
+
+// Exit statuses
+const (
+	exitSelected = 0
+	exitNone     = 1
+	exitTrouble  = 2
+)
+
+// stdinName is how the standard input is named in the output
+const stdinName = "(standard input)"
+
+type settings struct {
+	extended     bool
+	fixed        bool
+	count        bool
+	list         bool
+	quiet        bool
+	ignoreCase   bool
+	lineNumbers  bool
+	noMessages   bool
+	invert       bool
+	wholeLine    bool
+	patterns     []string // Each -e pattern_list, or the pattern_list operand
+	patternFiles []string
+	filenames    []string
+}
+
+func parseSettings(args []string) (settings, error) {
+	var s settings
+	p := flag.Parser{Input: args}
+	p.BoolVar(&s.extended, 'E', "match extended regular expressions")
+	p.BoolVar(&s.fixed, 'F', "match fixed strings")
+	p.BoolVar(&s.count, 'c', "write only a count of the selected lines")
+	p.StringsVar(&s.patterns, 'e', "match the patterns in `pattern_list`")
+	p.StringsVar(&s.patternFiles, 'f', "match the patterns in `pattern_file`")
+	p.BoolVar(&s.ignoreCase, 'i', "ignore case")
+	p.BoolVar(&s.list, 'l', "write only the names of files with selected lines")
+	p.BoolVar(&s.lineNumbers, 'n', "write line numbers")
+	p.BoolVar(&s.quiet, 'q', "write nothing, and exit 0 on the first selected line")
+	p.BoolVar(&s.noMessages, 's', "don't write errors about files")
+	p.BoolVar(&s.invert, 'v', "select lines that don't match")
+	p.BoolVar(&s.wholeLine, 'x', "match whole lines only")
+	operands, err := p.Parse()
+	if err != nil {
+		return s, err
+	}
+	if s.extended && s.fixed {
+		return s, errors.New("-E and -F can't be used together")
+	}
+	if len(s.patterns) == 0 && len(s.patternFiles) == 0 {
+		if len(operands) == 0 {
+			return s, errors.New("expected a pattern_list")
+		}
+		s.patterns = operands[:1]
+		operands = operands[1:]
+	}
+	s.filenames = util.Operands(operands)
+	return s, nil
+}
+
+// splitPatterns splits a pattern list into its patterns, one per line
+func splitPatterns(list string) []string {
+	return strings.Split(list, "\n")
+}
+
+// readPatterns returns the patterns of a pattern file, one per line
+func readPatterns(u *util.Utility, name string) ([]string, error) {
+	f, err := u.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, nil
+	}
+	return splitPatterns(strings.TrimSuffix(string(b), "\n")), nil
+}
+
+// matcher reports whether a line, without its newline, matches any pattern
+type matcher func(line string) bool
+
+func newMatcher(s settings, patterns []string) (matcher, error) {
+	if s.fixed {
+		return fixedMatcher(s, patterns), nil
+	}
+	var flags regexes.Flags
+	if s.extended {
+		flags |= regexes.Extended
+	}
+	if s.ignoreCase {
+		flags |= regexes.IgnoreCase
+	}
+	res := make([]*regexes.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		re, err := regexes.Compile(pattern, flags)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = re
+	}
+	return func(line string) bool {
+		for _, re := range res {
+			if !s.wholeLine {
+				if re.MatchString(line) {
+					return true
+				}
+				continue
+			}
+			// A match is leftmost, then longest, so if one spans the
+			// line, this is it
+			loc := re.FindStringSubmatchIndex(line)
+			if loc != nil && loc[0] == 0 && loc[1] == len(line) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// fixedMatcher matches lines against fixed strings, looking for all of them
+// at once
+func fixedMatcher(s settings, patterns []string) matcher {
+	fold := func(s string) string { return s }
+	if s.ignoreCase {
+		fold = strings.ToLower
+	}
+	if s.wholeLine {
+		lines := make(map[string]bool)
+		for _, p := range patterns {
+			lines[fold(p)] = true
+		}
+		return func(line string) bool {
+			return lines[fold(line)]
+		}
+	}
+	folded := make([]string, len(patterns))
+	for i, p := range patterns {
+		folded[i] = fold(p)
+	}
+	m := ahocorasick.New(folded)
+	return func(line string) bool {
+		return m.Contains(fold(line))
+	}
+}
+
+// grep writes the selected lines of r, or what -c and -l ask for instead,
+// and returns how many lines it selected. With -q, it stops at the first.
+func grep(s settings, match matcher, r io.Reader, w *bufio.Writer, name string, prefix bool) (int, error) {
+	br := bufio.NewReader(r)
+	selected := 0
+	for lineno := 1; ; lineno++ {
+		line, err := br.ReadString('\n')
+		if line == "" && err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return selected, err
+		}
+		text := strings.TrimSuffix(line, "\n")
+		if match(text) == s.invert {
+			continue
+		}
+		selected++
+		if s.quiet || s.list {
+			return selected, nil
+		}
+		if s.count {
+			continue
+		}
+		if prefix {
+			w.WriteString(name + ":")
+		}
+		if s.lineNumbers {
+			w.WriteString(strconv.Itoa(lineno) + ":")
+		}
+		w.WriteString(text + "\n")
+	}
+}
+
+// run searches each file, and returns the exit status
+func run(u *util.Utility, s settings) int {
+	patterns := []string{}
+	for _, list := range s.patterns {
+		patterns = append(patterns, splitPatterns(list)...)
+	}
+	for _, name := range s.patternFiles {
+		p, err := readPatterns(u, name)
+		if err != nil {
+			u.Report(err)
+			return exitTrouble
+		}
+		patterns = append(patterns, p...)
+	}
+	match, err := newMatcher(s, patterns)
+	if err != nil {
+		u.Report(err)
+		return exitTrouble
+	}
+
+	out := bufio.NewWriter(u.Stdout)
+	defer out.Flush()
+	status := exitNone
+	trouble := false
+	for _, filename := range s.filenames {
+		name := filename
+		if name == "-" {
+			name = stdinName
+		}
+		f, err := u.Open(filename)
+		if err != nil {
+			trouble = true
+			if !s.noMessages {
+				out.Flush()
+				u.Report(err)
+			}
+			continue
+		}
+		n, err := grep(s, match, f, out, name, len(s.filenames) > 1)
+		f.Close()
+		if err != nil {
+			trouble = true
+			if !s.noMessages {
+				out.Flush()
+				u.Report(err)
+			}
+		}
+		if n > 0 {
+			status = exitSelected
+		}
+		switch {
+		case s.quiet:
+			if n > 0 {
+				return exitSelected
+			}
+		case s.list:
+			if n > 0 {
+				fmt.Fprintln(out, name)
+			}
+		case s.count:
+			if len(s.filenames) > 1 {
+				fmt.Fprintf(out, "%s:", name)
+			}
+			fmt.Fprintln(out, n)
+		}
+	}
+	if err := out.Flush(); err != nil {
+		u.Report(err)
+		trouble = true
+	}
+	if trouble {
+		return exitTrouble
+	}
+	return status
+}
+
+func main() {
+	u := util.New("grep", usage)
+	s, err := parseSettings(os.Args[1:])
+	if err != nil {
+		u.UsageError(err, exitTrouble)
+	}
+	u.Exit(run(u, s))
+}
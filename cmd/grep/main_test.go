@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fwip/posix-utils/pkg/util"
+)
+
+func TestParseSettings(t *testing.T) {
+	tests := []struct {
+		args []string
+		want settings
+	}{
+		{[]string{"a"}, settings{patterns: []string{"a"}, filenames: []string{"-"}}},
+		{[]string{"-in", "a", "f", "g"}, settings{ignoreCase: true, lineNumbers: true, patterns: []string{"a"}, filenames: []string{"f", "g"}}},
+		{[]string{"-e", "a", "-eb", "f"}, settings{patterns: []string{"a", "b"}, filenames: []string{"f"}}},
+		{[]string{"-Fx", "-f", "p"}, settings{fixed: true, wholeLine: true, patternFiles: []string{"p"}, filenames: []string{"-"}}},
+		{[]string{"--", "-v"}, settings{patterns: []string{"-v"}, filenames: []string{"-"}}},
+	}
+	for _, tt := range tests {
+		s, err := parseSettings(tt.args)
+		if err != nil || !reflect.DeepEqual(s, tt.want) {
+			t.Errorf("%q: got %+v, %v", tt.args, s, err)
+		}
+	}
+	for _, args := range [][]string{{}, {"-v"}, {"-E", "-F", "a"}, {"-y", "a"}, {"-e"}} {
+		if _, err := parseSettings(args); err == nil {
+			t.Errorf("%q: expected an error", args)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "grep")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	write := func(name, contents string) string {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	a := write("a", "apple\nBanana\ncherry\n")
+	b := write("b", "date\nelderberry\nfig")
+	pats := write("pats", "an\nfig\n")
+	missing := filepath.Join(dir, "missing")
+
+	tests := []struct {
+		args   []string
+		stdin  string
+		status int
+		stdout string
+	}{
+		{[]string{"an"}, "man\nmoon\nvan\n", 0, "man\nvan\n"},
+		{[]string{"-v", "an"}, "man\nmoon\nvan\n", 0, "moon\n"},
+		{[]string{"xyz"}, "man\n", 1, ""},
+		{[]string{"-n", "o"}, "man\nmoon\nvan\n", 0, "2:moon\n"},
+		{[]string{"-c", "an"}, "man\nmoon\nvan\n", 0, "2\n"},
+		{[]string{"-c", "xyz"}, "man\n", 1, "0\n"},
+		{[]string{"-q", "an"}, "man\n", 0, ""},
+		{[]string{"-x", "ma*n"}, "man\nmaan\nmane\n", 0, "man\nmaan\n"},
+		{[]string{"-i", "BAN"}, "banana\n", 0, "banana\n"},
+		{[]string{"-E", "^(ap|ch)"}, "apple\ncherry\nfig\n", 0, "apple\ncherry\n"},
+		{[]string{"a|b"}, "a|b\na\n", 0, "a|b\n"},
+		{[]string{`\(.\)\1`}, "apple\nfig\n", 0, "apple\n"},
+		{[]string{"-e", "fig\ncher"}, "apple\ncherry\nfig\n", 0, "cherry\nfig\n"},
+		{[]string{"-F", "-e", "a.", "-e", "g"}, "a.b\nab\nfig\n", 0, "a.b\nfig\n"},
+		{[]string{"-Fix", "AB"}, "ab\nabc\n", 0, "ab\n"},
+		{[]string{"-e", ""}, "x\n\n", 0, "x\n\n"},
+		{[]string{"-n", "an", a, b}, "", 0, a + ":2:Banana\n"},
+		{[]string{"-i", "-f", pats, a, b}, "", 0, a + ":Banana\n" + b + ":fig\n"},
+		{[]string{"-l", "e", a, "-", b}, "yes\n", 0, a + "\n(standard input)\n" + b + "\n"},
+		{[]string{"-c", "e", a, b}, "", 0, a + ":2\n" + b + ":2\n"},
+		{[]string{"-c", "-v", "e", b}, "", 0, "1\n"},
+		{[]string{"fig", missing, b}, "", 2, b + ":fig\n"},
+		{[]string{"-s", "fig", missing}, "", 2, ""},
+		{[]string{"-q", "fig", missing, b}, "", 0, ""},
+		{[]string{"-q", "fig", b, missing}, "", 0, ""},
+		{[]string{"-q", "xyz", missing, b}, "", 2, ""},
+		{[]string{"-E", "a{2,1}"}, "", 2, ""},
+		{[]string{"-f", missing}, "", 2, ""},
+	}
+	for _, tt := range tests {
+		s, err := parseSettings(tt.args)
+		if err != nil {
+			t.Errorf("%q: %s", tt.args, err)
+			continue
+		}
+		var stdout, stderr bytes.Buffer
+		u := &util.Utility{Name: "grep", Stdin: strings.NewReader(tt.stdin), Stdout: &stdout, Stderr: &stderr}
+		status := run(u, s)
+		if status != tt.status || stdout.String() != tt.stdout {
+			t.Errorf("%q: got status %d and %q, expected %d and %q", tt.args, status, stdout.String(), tt.status, tt.stdout)
+		}
+		// The status grep works out is the one it exits with
+		if u.Status() != 0 {
+			t.Errorf("%q: diagnostics set the status to %d", tt.args, u.Status())
+		}
+		if s.noMessages && stderr.Len() > 0 {
+			t.Errorf("%q: unexpected diagnostics %q", tt.args, stderr.String())
+		}
+		if tt.status == 2 && !s.noMessages && stderr.Len() == 0 {
+			t.Errorf("%q: expected a diagnostic", tt.args)
+		}
+	}
+}
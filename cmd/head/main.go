@@ -2,85 +2,163 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"os"
 	"strconv"
+
+	"github.com/fwip/posix-utils/pkg/flag"
+	"github.com/fwip/posix-utils/pkg/util"
 )
 
 type settings struct {
-	numLines  int
+	numLines  int64
+	numBytes  int64 // When non-negative, copy this many bytes instead of lines
 	filenames []string
 }
 
+func parseCount(s string) (int64, error) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid count: %s", s)
+	}
+	return n, nil
+}
+
+// count is the -n or -c option. As the last one given wins, -n undoes -c.
+type count struct {
+	s     *settings
+	bytes bool
+}
+
+func (c count) Set(value string) error {
+	n, err := parseCount(value)
+	if err != nil {
+		return err
+	}
+	if c.bytes {
+		c.s.numBytes = n
+	} else {
+		c.s.numLines = n
+		c.s.numBytes = -1
+	}
+	return nil
+}
+
+func (c count) String() string { return "" }
+
+// obsolescent is the -N form, which is the same as -n N
+type obsolescent struct {
+	count
+}
+
+func (o obsolescent) Set(value string) error {
+	if value[0] != '-' {
+		return fmt.Errorf("invalid option: %s", value)
+	}
+	return o.count.Set(value[1:])
+}
+
 func parseSettings(args []string) (settings, error) {
 	s := settings{
 		numLines: 10,
-		//filenames: []string{"-"},
+		numBytes: -1,
 	}
-
-	if len(args) == 0 {
-		return s, nil
-		//return settings{}, fmt.Errorf("No arguments")
+	p := flag.Parser{Input: args}
+	p.Var(count{&s, false}, 'n', "copy the first `number` lines")
+	p.Var(count{&s, true}, 'c', "copy the first `number` bytes")
+	p.NumberVar(obsolescent{count{&s, false}}, "copy the first `number` lines")
+	operands, err := p.Parse()
+	if err != nil {
+		return s, err
 	}
+	s.filenames = util.Operands(operands)
+	return s, nil
+}
 
-	for i := 0; i < len(args); i++ {
-		if args[i] == "-n" {
-			i++
-			n, err := strconv.Atoi(args[i])
-			if err != nil {
-				return s, err
+// headLines copies the first n lines of r to w, byte for byte
+func headLines(r io.Reader, w io.Writer, n int64) error {
+	buf := make([]byte, 32*1024)
+	for n > 0 {
+		m, err := r.Read(buf)
+		b := buf[:m]
+		end := 0
+		for n > 0 && end < len(b) {
+			i := bytes.IndexByte(b[end:], '\n')
+			if i < 0 {
+				end = len(b)
+				break
 			}
-			s.numLines = n
-		} else {
-			s.filenames = append(s.filenames, args[i])
+			end += i + 1
+			n--
+		}
+		if _, werr := w.Write(b[:end]); werr != nil {
+			return werr
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	if len(s.filenames) == 0 {
-		s.filenames = []string{"-"}
+// headBytes copies the first n bytes of r to w
+func headBytes(r io.Reader, w io.Writer, n int64) error {
+	_, err := io.CopyN(w, r, n)
+	if err == io.EOF {
+		return nil
 	}
+	return err
+}
 
-	return s, nil
+func head(s settings, r io.Reader, w io.Writer) error {
+	if s.numBytes >= 0 {
+		return headBytes(r, w, s.numBytes)
+	}
+	return headLines(r, w, s.numLines)
 }
 
-func process(s settings) {
+// process writes the head of each file, carrying on past any that can't be
+// read
+func process(u *util.Utility, s settings) {
+	out := bufio.NewWriter(u.Stdout)
+	defer out.Flush()
 
-	for i, filename := range s.filenames {
-		if len(s.filenames) > 1 {
-			if i > 0 {
-				fmt.Print("\n")
-			}
-			fmt.Printf("==> %s <==\n", filename)
-		}
-		var f io.ReadCloser
-		var err error
-		if filename == "-" {
-			f = os.Stdin
-		} else {
-			f, err = os.Open(filename)
-			if err != nil {
-				panic(err)
-			}
+	wroteHeader := false
+	for _, filename := range s.filenames {
+		f, err := u.Open(filename)
+		if err != nil {
+			out.Flush()
+			u.Warn(err)
+			continue
 		}
 
-		scan := bufio.NewScanner(f)
-		j := 0
-		for scan.Scan() && j < s.numLines {
-			fmt.Println(scan.Text())
-			j++
+		if len(s.filenames) > 1 {
+			if wroteHeader {
+				fmt.Fprint(out, "\n")
+			}
+			fmt.Fprintf(out, "==> %s <==\n", filename)
+			wroteHeader = true
 		}
-		if scan.Err() != nil {
-			panic(scan.Err())
+		err = head(s, f, out)
+		f.Close()
+		if err != nil {
+			out.Flush()
+			u.Warn(err)
 		}
 	}
-
 }
 
 func main() {
+	u := util.New("head", "usage: head [-n number | -c number] [file...]")
 	s, err := parseSettings(os.Args[1:])
 	if err != nil {
-		panic(err)
+		u.UsageError(err, util.UsageStatus)
 	}
-	process(s)
+	process(u, s)
+	u.Exit(0)
 }
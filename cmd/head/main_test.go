@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/fwip/posix-utils/pkg/util"
+)
+
+func TestParseSettings(t *testing.T) {
+	tests := []struct {
+		args string
+		want settings
+	}{
+		{"", settings{10, -1, []string{"-"}}},
+		{"-n 3 a b", settings{3, -1, []string{"a", "b"}}},
+		{"-c5", settings{10, 5, []string{"-"}}},
+		{"-c 5 -n 2", settings{2, -1, []string{"-"}}},
+		{"-7 -- -f", settings{7, -1, []string{"-f"}}},
+	}
+	for _, tt := range tests {
+		s, err := parseSettings(strings.Fields(tt.args))
+		if err != nil || !reflect.DeepEqual(s, tt.want) {
+			t.Errorf("%q: got %+v, %v", tt.args, s, err)
+		}
+	}
+	for _, args := range []string{"-x", "-n", "-n -1", "-c x", "+3"} {
+		if _, err := parseSettings(strings.Fields(args)); err == nil {
+			t.Errorf("%q: expected an error", args)
+		}
+	}
+}
+
+func TestHead(t *testing.T) {
+	raw := "\xff\x00a\r\nb\xc3\n\x80\n"
+	long := strings.Repeat("x", 40*1024) + "\n"
+	tests := []struct {
+		s     settings
+		input string
+		want  string
+	}{
+		{settings{numLines: 2, numBytes: -1}, raw, "\xff\x00a\r\nb\xc3\n"},
+		{settings{numLines: 10, numBytes: -1}, raw, raw},
+		{settings{numLines: 0, numBytes: -1}, raw, ""},
+		{settings{numLines: 2, numBytes: -1}, "a\nb", "a\nb"},
+		{settings{numLines: 3, numBytes: -1}, "a\nb", "a\nb"},
+		{settings{numLines: 1, numBytes: -1}, "no newline", "no newline"},
+		{settings{numLines: 1, numBytes: -1}, long + "y\n", long},
+		{settings{numLines: 1, numBytes: -1}, "", ""},
+		{settings{numBytes: 4}, raw, "\xff\x00a\r"},
+		{settings{numBytes: 6}, raw, "\xff\x00a\r\nb"},
+		{settings{numBytes: 100}, raw, raw},
+		{settings{numBytes: 100}, "a\nb", "a\nb"},
+		{settings{numBytes: 0}, raw, ""},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if err := head(tt.s, strings.NewReader(tt.input), &out); err != nil || out.String() != tt.want {
+			t.Errorf("%+v %q: got %q, %v, expected %q", tt.s, tt.input, out.String(), err, tt.want)
+		}
+		// Lines may be split across reads
+		out.Reset()
+		if err := head(tt.s, iotest.OneByteReader(strings.NewReader(tt.input)), &out); err != nil || out.String() != tt.want {
+			t.Errorf("%+v %q, a byte at a time: got %q, %v, expected %q", tt.s, tt.input, out.String(), err, tt.want)
+		}
+	}
+}
+
+func TestProcess(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	u := &util.Utility{Name: "head", Stdin: strings.NewReader("a\nb"), Stdout: &stdout, Stderr: &stderr}
+	process(u, settings{numLines: 1, numBytes: -1, filenames: []string{"/nonexistent", "-"}})
+	if want := "==> - <==\na\n"; stdout.String() != want || u.Status() != 1 {
+		t.Errorf("got %q, status %d, expected %q", stdout.String(), u.Status(), want)
+	}
+	if !strings.Contains(stderr.String(), "/nonexistent") {
+		t.Errorf("expected a diagnostic, got %q", stderr.String())
+	}
+}
@@ -1,77 +1,160 @@
 package main
 
-// TODO: This functionality appears bugged on Darwin. Check out in non-OSX platform
-
 import (
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
+	"syscall"
+
+	"github.com/fwip/posix-utils/pkg/flag"
+	"github.com/fwip/posix-utils/pkg/util"
 )
 
-var signalMap = map[string]int{
-	"0":    0,
-	"HUP":  1,
-	"INT":  2,
-	"QUIT": 3,
-	"ABRT": 6,
-	"KILL": 9,
-	"ALRM": 14,
-	"TERM": 15,
+const usage = "usage: kill -s signal_name pid...\n" +
+	"       kill -l [exit_status]\n" +
+	"       kill [-signal_name | -signal_number] pid..."
+
+// parseName returns the signal for a name, given with or without the SIG
+// prefix in any case
+func parseName(name string) (syscall.Signal, error) {
+	upper := strings.ToUpper(name)
+	if sig, ok := lookupName(strings.TrimPrefix(upper, "SIG")); ok {
+		return sig, nil
+	}
+	if sig, ok := lookupName(upper); ok {
+		return sig, nil
+	}
+	return 0, fmt.Errorf("%s: invalid signal name", name)
 }
 
-func errorMsg(format string, args ...interface{}) {
-	fmt.Fprintf(os.Stderr, format, args...)
-	os.Exit(2)
+// parseSignal returns the signal for a name or number
+func parseSignal(s string) (syscall.Signal, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		if _, ok := lookupSignal(syscall.Signal(n)); !ok && n != 0 {
+			return 0, fmt.Errorf("%s: invalid signal number", s)
+		}
+		return syscall.Signal(n), nil
+	}
+	return parseName(s)
 }
 
-func main() {
+// list writes the signal names, or the name for each exit status or signal
+// number given
+func list(u *util.Utility, args []string) {
+	if len(args) == 0 {
+		for _, s := range sortedSignals() {
+			fmt.Fprintln(u.Stdout, s.name)
+		}
+		return
+	}
 
-	listSignals := false
-	signal := 15
-	var pid int
-	var err error
-	var ok bool
-	for i := 1; i < len(os.Args); i++ {
-		a := os.Args[i]
-		switch a {
-		case "-l":
-			listSignals = true
-		case "-s":
-			i++
-			signalName := strings.ToUpper(os.Args[i])
-			signal, ok = signalMap[signalName]
-			if !ok {
-				errorMsg("%s is not a valid signal name - check kill -l for help\n", a)
-			}
-		default:
-			if a[0] == '-' {
-				signal, err = strconv.Atoi(a[1:])
-				if err != nil {
-					errorMsg("%s isn't a number, bro...", a)
-				}
-			} else {
-				pid, err = strconv.Atoi(a)
-				if err != nil {
-					errorMsg("expected a PID here..., got %s", a)
-				}
+	for _, a := range args {
+		n, err := strconv.Atoi(a)
+		if err != nil {
+			// Be forgiving, and translate names to numbers
+			sig, err := parseName(a)
+			if err != nil {
+				u.Warn(err)
+				continue
 			}
+			fmt.Fprintln(u.Stdout, int(sig))
+			continue
+		}
+		// An exit status above 128 indicates the process was killed by a signal
+		if n > 128 {
+			n -= 128
+		}
+		name, ok := lookupSignal(syscall.Signal(n))
+		if !ok {
+			u.Warnf("%s: invalid exit status", a)
+			continue
 		}
+		fmt.Fprintln(u.Stdout, name)
 	}
+}
 
-	if listSignals {
+// send delivers sig to each pid, reporting failures individually. A
+// negative pid signals the process group.
+func send(u *util.Utility, sig syscall.Signal, pids []string) {
+	for _, p := range pids {
+		pid, err := strconv.Atoi(p)
+		if err != nil {
+			u.Warnf("%s: arguments must be process IDs", p)
+			continue
+		}
+		if err = syscall.Kill(pid, sig); err != nil {
+			u.Warnf("%s: %s", p, err)
+		}
+	}
+}
 
+// isSignalOption reports whether a is the -signal_name or -signal_number
+// form, which can't be parsed as options. Only -s, alone or with a signal
+// name after it, and -l, alone or with an exit status after it, are
+// options, so that names such as -stop and -segv are signals.
+func isSignalOption(a string) bool {
+	if len(a) < 2 || a[0] != '-' || a == "--" {
+		return false
+	}
+	if strings.HasPrefix(a, "-s") {
+		if _, err := parseName(a[2:]); a == "-s" || err == nil {
+			return false
+		}
 	}
-	fmt.Println("sending", signal, "to", pid)
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		errorMsg("couldn't find process with PID of %d: %s\n", pid, err)
+	return !strings.HasPrefix(a, "-l") || !isDigits(a[2:])
+}
+
+// isDigits reports whether s is empty or all decimal digits
+func isDigits(s string) bool {
+	return strings.Trim(s, "0123456789") == ""
+}
+
+func main() {
+	u := util.New("kill", usage)
+	args := os.Args[1:]
+	sig := syscall.SIGTERM
+	var err error
+
+	if len(args) > 0 && isSignalOption(args[0]) {
+		if sig, err = parseSignal(args[0][1:]); err != nil {
+			u.Fatal(err, 2)
+		}
+		args = args[1:]
+		// Negative process IDs may follow a "--"
+		if len(args) > 0 && args[0] == "--" {
+			args = args[1:]
+		}
+	} else {
+		// The exit status may be attached to -l
+		if len(args) > 0 && len(args[0]) > 2 && strings.HasPrefix(args[0], "-l") {
+			args = append([]string{"-l", args[0][2:]}, args[1:]...)
+		}
+		var listNames bool
+		var name string
+		p := flag.Parser{Input: args}
+		p.BoolVar(&listNames, 'l', "list signal names")
+		p.StringVar(&name, 's', "send the signal `signal_name`")
+		if args, err = p.Parse(); err != nil {
+			u.UsageError(err, util.UsageStatus)
+		}
+		if listNames {
+			if name != "" {
+				u.UsageError(fmt.Errorf("-l and -s can't be used together"), util.UsageStatus)
+			}
+			list(u, args)
+			u.Exit(0)
+		}
+		if name != "" {
+			if sig, err = parseName(name); err != nil {
+				u.Fatal(err, 2)
+			}
+		}
 	}
-	//err = process.Signal(syscall.Signal(signal))
-	err = process.Kill()
-	if err != nil {
-		errorMsg("couldn't send signal %d to %d, %s", signal, pid, err)
+	if len(args) == 0 {
+		u.UsageError(fmt.Errorf("expected a process ID"), util.UsageStatus)
 	}
 
-	fmt.Println("vim-go")
+	send(u, sig, args)
+	u.Exit(0)
 }
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/fwip/posix-utils/pkg/util"
+)
+
+func TestParseSignal(t *testing.T) {
+	tests := []struct {
+		in   string
+		want syscall.Signal
+	}{
+		{"9", syscall.SIGKILL},
+		{"0", 0},
+		{"KILL", syscall.SIGKILL},
+		{"kill", syscall.SIGKILL},
+		{"SIGTERM", syscall.SIGTERM},
+		{"sigsegv", syscall.SIGSEGV},
+		{"stop", syscall.SIGSTOP},
+	}
+	for _, tt := range tests {
+		if got, err := parseSignal(tt.in); err != nil || got != tt.want {
+			t.Errorf("%q: got %v, %v, expected %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "NOSUCH", "SIG", "-1", "1000"} {
+		if _, err := parseSignal(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+func TestIsSignalOption(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"-9", true},
+		{"-KILL", true},
+		{"-segv", true},
+		{"-stop", true},
+		{"-sys", true},
+		{"-l", false},
+		{"-l137", false},
+		{"-s", false},
+		{"-sKILL", false},
+		{"-ssegv", false},
+		{"-sSIGINT", false},
+		{"-s9", true},
+		{"--", false},
+		{"-", false},
+		{"123", false},
+	}
+	for _, tt := range tests {
+		if got := isSignalOption(tt.in); got != tt.want {
+			t.Errorf("%q: got %v", tt.in, got)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	tests := []struct {
+		args   []string
+		want   string
+		status int
+	}{
+		{[]string{"9"}, "KILL\n", 0},
+		{[]string{"137"}, "KILL\n", 0},
+		{[]string{"143", "15"}, "TERM\nTERM\n", 0},
+		{[]string{"TERM", "sigkill"}, "15\n9\n", 0},
+		{[]string{"1000", "HUP"}, "1\n", 1},
+		{[]string{"NOSUCH"}, "", 1},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		u := &util.Utility{Name: "kill", Stdout: &stdout, Stderr: &stderr}
+		list(u, tt.args)
+		if stdout.String() != tt.want || u.Status() != tt.status {
+			t.Errorf("%q: got %q, status %d, %q", tt.args, stdout.String(), u.Status(), stderr.String())
+		}
+	}
+
+	var stdout bytes.Buffer
+	u := &util.Utility{Name: "kill", Stdout: &stdout, Stderr: &stdout}
+	list(u, nil)
+	names := strings.Fields(stdout.String())
+	if len(names) != len(signals) || names[0] != "HUP" {
+		t.Errorf("got %q", names)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"sort"
+	"syscall"
+)
+
+// signalName pairs a signal with its name, without the SIG prefix
+type signalName struct {
+	name   string
+	signal syscall.Signal
+}
+
+// signals holds every signal this platform supports. It's extended by the
+// platform-specific files.
+var signals = []signalName{
+	{"HUP", syscall.SIGHUP},
+	{"INT", syscall.SIGINT},
+	{"QUIT", syscall.SIGQUIT},
+	{"ILL", syscall.SIGILL},
+	{"TRAP", syscall.SIGTRAP},
+	{"ABRT", syscall.SIGABRT},
+	{"BUS", syscall.SIGBUS},
+	{"FPE", syscall.SIGFPE},
+	{"KILL", syscall.SIGKILL},
+	{"USR1", syscall.SIGUSR1},
+	{"SEGV", syscall.SIGSEGV},
+	{"USR2", syscall.SIGUSR2},
+	{"PIPE", syscall.SIGPIPE},
+	{"ALRM", syscall.SIGALRM},
+	{"TERM", syscall.SIGTERM},
+	{"CHLD", syscall.SIGCHLD},
+	{"CONT", syscall.SIGCONT},
+	{"STOP", syscall.SIGSTOP},
+	{"TSTP", syscall.SIGTSTP},
+	{"TTIN", syscall.SIGTTIN},
+	{"TTOU", syscall.SIGTTOU},
+	{"URG", syscall.SIGURG},
+	{"XCPU", syscall.SIGXCPU},
+	{"XFSZ", syscall.SIGXFSZ},
+	{"VTALRM", syscall.SIGVTALRM},
+	{"PROF", syscall.SIGPROF},
+	{"WINCH", syscall.SIGWINCH},
+	{"IO", syscall.SIGIO},
+	{"SYS", syscall.SIGSYS},
+}
+
+// aliases are alternate names for signals that are accepted but never listed
+var aliases = map[string]syscall.Signal{}
+
+// sortedSignals returns the supported signals in numeric order
+func sortedSignals() []signalName {
+	sorted := append([]signalName(nil), signals...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].signal < sorted[j].signal
+	})
+	return sorted
+}
+
+// lookupName returns the signal with the given name
+func lookupName(name string) (syscall.Signal, bool) {
+	if name == "0" {
+		return 0, true
+	}
+	for _, s := range signals {
+		if s.name == name {
+			return s.signal, true
+		}
+	}
+	sig, ok := aliases[name]
+	return sig, ok
+}
+
+// lookupSignal returns the name of the given signal
+func lookupSignal(sig syscall.Signal) (string, bool) {
+	for _, s := range signals {
+		if s.signal == sig {
+			return s.name, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,13 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+// +build darwin dragonfly freebsd netbsd openbsd
+
+package main
+
+import "syscall"
+
+func init() {
+	signals = append(signals,
+		signalName{"EMT", syscall.SIGEMT},
+		signalName{"INFO", syscall.SIGINFO},
+	)
+}
@@ -0,0 +1,13 @@
+package main
+
+import "syscall"
+
+func init() {
+	signals = append(signals,
+		signalName{"STKFLT", syscall.SIGSTKFLT},
+		signalName{"PWR", syscall.SIGPWR},
+	)
+	aliases["IOT"] = syscall.SIGIOT
+	aliases["CLD"] = syscall.SIGCLD
+	aliases["POLL"] = syscall.SIGPOLL
+}
@@ -1,8 +1,109 @@
 package main
 
-import "fmt"
-import "github.com/fwip/posix-utils/pkg/locale"
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fwip/posix-utils/pkg/flag"
+	"github.com/fwip/posix-utils/pkg/locale"
+	"github.com/fwip/posix-utils/pkg/util"
+)
+
+type settings struct {
+	all      bool // -a: list the available locales
+	charmaps bool // -m: list the available charmaps
+	category bool // -c: name the category of each value
+	keyword  bool // -k: name each keyword with its value
+	names    []string
+}
+
+func parseSettings(args []string) (settings, error) {
+	var s settings
+	p := flag.Parser{Input: args}
+	p.BoolVar(&s.all, 'a', "list the available locales")
+	p.BoolVar(&s.charmaps, 'm', "list the available charmaps")
+	p.BoolVar(&s.category, 'c', "name the category of each value")
+	p.BoolVar(&s.keyword, 'k', "name each keyword with its value")
+	names, err := p.Parse()
+	if err != nil {
+		return s, err
+	}
+	s.names = names
+
+	if s.all || s.charmaps {
+		if s.all && s.charmaps || s.category || s.keyword || len(s.names) > 0 {
+			return s, fmt.Errorf("-a and -m must be used alone")
+		}
+	} else if (s.category || s.keyword) && len(s.names) == 0 {
+		return s, fmt.Errorf("expected a name to look up")
+	}
+	return s, nil
+}
+
+// run does what the settings ask, returning the exit status
+func run(s settings, stdout, stderr io.Writer) int {
+	w := bufio.NewWriter(stdout)
+	defer w.Flush()
+
+	switch {
+	case s.all:
+		for _, name := range locale.Available() {
+			fmt.Fprintln(w, name)
+		}
+		return 0
+	case s.charmaps:
+		for _, name := range locale.Charmaps() {
+			fmt.Fprintln(w, name)
+		}
+		return 0
+	case len(s.names) == 0:
+		fmt.Fprint(w, locale.FromEnv().String())
+		return 0
+	}
+
+	def, err := locale.FromEnv().Load()
+	if err != nil {
+		fmt.Fprintf(stderr, "locale: %s\n", err)
+	}
+	status := 0
+	show := func(k locale.Keyword) {
+		if s.keyword {
+			fmt.Fprintln(w, k.Assignment())
+		} else {
+			fmt.Fprintln(w, k.String())
+		}
+	}
+	for _, name := range s.names {
+		if locale.IsCategory(name) {
+			if s.category {
+				fmt.Fprintln(w, name)
+			}
+			for _, k := range def.Keywords(name) {
+				show(k)
+			}
+			continue
+		}
+		k, ok := def.Keyword(name)
+		if !ok {
+			fmt.Fprintf(stderr, "locale: unknown name %q\n", name)
+			status = 1
+			continue
+		}
+		if s.category {
+			fmt.Fprintln(w, k.Category)
+		}
+		show(k)
+	}
+	return status
+}
 
 func main() {
-	fmt.Printf(locale.FromEnv().String())
+	u := util.New("locale", "usage: locale [-a | -m]\n       locale [-ck] name...")
+	s, err := parseSettings(os.Args[1:])
+	if err != nil {
+		u.UsageError(err, util.UsageStatus)
+	}
+	u.Exit(run(s, u.Stdout, u.Stderr))
 }
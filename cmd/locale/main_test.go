@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseSettings(t *testing.T) {
+	s, err := parseSettings([]string{"-ck", "LC_NUMERIC", "-x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := settings{category: true, keyword: true, names: []string{"LC_NUMERIC", "-x"}}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("got %+v, expected %+v", s, want)
+	}
+	for _, args := range [][]string{{"-x"}, {"-a", "-m"}, {"-a", "name"}, {"-mk"}, {"-c"}} {
+		if _, err := parseSettings(args); err == nil {
+			t.Errorf("expected an error for %q", args)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	for _, v := range []string{"LANG", "LC_ALL", "LC_CTYPE", "LC_COLLATE", "LC_MONETARY", "LC_NUMERIC", "LC_TIME", "LC_MESSAGES"} {
+		if old, ok := os.LookupEnv(v); ok {
+			defer os.Setenv(v, old)
+		} else {
+			defer os.Unsetenv(v)
+		}
+		os.Unsetenv(v)
+	}
+	os.Setenv("LC_TIME", "C")
+
+	tests := []struct {
+		s      settings
+		out    string
+		status int
+	}{
+		{settings{}, "LANG=\nLC_CTYPE=\"POSIX\"\nLC_COLLATE=\"POSIX\"\nLC_MONETARY=\"POSIX\"\n" +
+			"LC_NUMERIC=\"POSIX\"\nLC_TIME=C\nLC_MESSAGES=\"POSIX\"\nLC_ALL=\n", 0},
+		{settings{names: []string{"decimal_point", "abday"}}, ".\nSun;Mon;Tue;Wed;Thu;Fri;Sat\n", 0},
+		{settings{category: true, keyword: true, names: []string{"LC_NUMERIC"}},
+			"LC_NUMERIC\ndecimal_point=\".\"\nthousands_sep=\"\"\ngrouping=-1\n", 0},
+		{settings{keyword: true, names: []string{"frac_digits", "nonsense"}}, "frac_digits=-1\n", 1},
+		{settings{category: true, names: []string{"noexpr"}}, "LC_MESSAGES\n^[nN]\n", 0},
+		{settings{all: true}, "C\nPOSIX\n", 0},
+	}
+	os.Setenv("LOCPATH", os.DevNull)
+	defer os.Unsetenv("LOCPATH")
+	for _, test := range tests {
+		var stdout, stderr bytes.Buffer
+		status := run(test.s, &stdout, &stderr)
+		if status != test.status || stdout.String() != test.out {
+			t.Errorf("%+v: exit status %d, printed\n%s\nexpected %d and\n%s", test.s, status, stdout.String(), test.status, test.out)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fwip/posix-utils/pkg/flag"
+	"github.com/fwip/posix-utils/pkg/locale"
+	"github.com/fwip/posix-utils/pkg/util"
+)
+
+// Exit statuses, as given by POSIX
+const (
+	exitOK       = 0 // The locale was created
+	exitWarnings = 1 // There were warnings, but the locale was created anyway
+	exitLimits   = 2 // The codeset or an implementation limit isn't supported
+	exitErrors   = 4 // There were warnings or errors, and nothing was created
+)
+
+type settings struct {
+	force   bool   // -c: create the locale even if there are warnings
+	charmap string // -f
+	input   string // -i, or "" for stdin
+	codeset string // -u
+	name    string
+}
+
+func parseSettings(args []string) (settings, error) {
+	var s settings
+	p := flag.Parser{Input: args}
+	p.BoolVar(&s.force, 'c', "create the locale even if there are warnings")
+	p.StringVar(&s.charmap, 'f', "use the `charmap`")
+	p.StringVar(&s.input, 'i', "read the `sourcefile` rather than standard input")
+	p.StringVar(&s.codeset, 'u', "use the `code_set_name`")
+	operands, err := p.Parse()
+	if err != nil {
+		return s, err
+	}
+	if len(operands) != 1 {
+		return s, fmt.Errorf("expected one locale name")
+	}
+	s.name = operands[0]
+	return s, nil
+}
+
+// exitError is an error that should end localedef with a particular status
+type exitError struct {
+	status int
+	err    error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func fail(status int, format string, args ...interface{}) error {
+	return &exitError{status, fmt.Errorf(format, args...)}
+}
+
+// readCharmap returns the charmap to resolve characters with, or nil for the
+// default. The built-in UTF-8 charmap can be named without a file.
+func readCharmap(s settings) (*locale.Charmap, error) {
+	var cm *locale.Charmap
+	if s.charmap != "" && !(isUTF8(s.charmap) && !strings.Contains(s.charmap, "/")) {
+		path, err := locale.FindCharmap(s.charmap)
+		if err != nil {
+			return nil, fail(exitErrors, "%s", err)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fail(exitErrors, "%s", err)
+		}
+		defer f.Close()
+		if cm, err = locale.ParseCharmap(f); err != nil {
+			return nil, fail(exitErrors, "%s: %s", s.charmap, err)
+		}
+	}
+
+	// Names like <U00E9> give ISO 10646 code points, which can only be
+	// mapped when the target codeset is UTF-8
+	if s.codeset != "" {
+		target := s.codeset
+		if cm != nil {
+			target = cm.CodeSetName
+		}
+		if !isUTF8(s.codeset) || !isUTF8(target) {
+			return nil, fail(exitLimits, "cannot map ISO 10646 values to codeset %s", target)
+		}
+	}
+	return cm, nil
+}
+
+func isUTF8(name string) bool {
+	name = strings.ToUpper(strings.Replace(name, "-", "", -1))
+	return name == "UTF8"
+}
+
+// resolveCopies replaces each category defined with the copy keyword by the
+// same category from the locale it names
+func resolveCopies(def *locale.Def) error {
+	for category, name := range def.Copies {
+		src, err := locale.LookupDef(name)
+		if err != nil {
+			return fail(exitErrors, "cannot copy %s: %s", category, err)
+		}
+		def.SetCategory(category, src)
+	}
+	def.Copies = nil
+	return nil
+}
+
+// write replaces the file at path, so that a reader never sees it half-written
+func write(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(dir, ".localedef")
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err == nil {
+		err = f.Chmod(0644)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// run compiles the locale, returning the exit status
+func run(s settings, stdin io.Reader, stderr io.Writer) int {
+	report := func(err error) int {
+		fmt.Fprintf(stderr, "localedef: %s\n", err)
+		if e, ok := err.(*exitError); ok {
+			return e.status
+		}
+		return exitErrors
+	}
+
+	cm, err := readCharmap(s)
+	if err != nil {
+		return report(err)
+	}
+
+	input, inputName := stdin, "stdin"
+	if s.input != "" {
+		f, err := os.Open(s.input)
+		if err != nil {
+			return report(err)
+		}
+		defer f.Close()
+		input, inputName = f, s.input
+	}
+	var def locale.Def
+	if cm != nil {
+		def, err = locale.ParseDefCharmap(input, cm)
+	} else {
+		def, err = locale.ParseDef(input)
+	}
+	if err != nil {
+		return report(fmt.Errorf("%s: %s", inputName, err))
+	}
+	if err := resolveCopies(&def); err != nil {
+		return report(err)
+	}
+
+	warnings := def.Validate()
+	for _, w := range warnings {
+		fmt.Fprintf(stderr, "localedef: warning: %s\n", w)
+	}
+	if len(warnings) > 0 && !s.force {
+		fmt.Fprintf(stderr, "localedef: not creating %s because of warnings\n", s.name)
+		return exitErrors
+	}
+
+	data, err := def.MarshalBinary()
+	if err == nil {
+		err = write(locale.Path(s.name), data)
+	}
+	if err != nil {
+		return report(err)
+	}
+	if len(warnings) > 0 {
+		return exitWarnings
+	}
+	return exitOK
+}
+
+func main() {
+	u := util.New("localedef", "usage: localedef [-c] [-f charmap] [-i sourcefile] [-u code_set_name] name")
+	s, err := parseSettings(os.Args[1:])
+	if err != nil {
+		u.UsageError(err, exitErrors)
+	}
+	u.Exit(run(s, u.Stdin, u.Stderr))
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fwip/posix-utils/pkg/locale"
+)
+
+func TestParseSettings(t *testing.T) {
+	s, err := parseSettings([]string{"-c", "-fmap", "-i", "src", "-u", "UTF-8", "name"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := settings{force: true, charmap: "map", input: "src", codeset: "UTF-8", name: "name"}
+	if s != want {
+		t.Errorf("got %+v, expected %+v", s, want)
+	}
+	for _, args := range [][]string{{}, {"-x", "name"}, {"-f"}, {"a", "b"}} {
+		if _, err := parseSettings(args); err == nil {
+			t.Errorf("expected an error for %q", args)
+		}
+	}
+}
+
+const numeric = `LC_NUMERIC
+decimal_point "<comma>"
+thousands_sep "<period>"
+grouping 3
+END LC_NUMERIC
+`
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "localedef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	os.Setenv("LOCPATH", dir)
+	defer os.Unsetenv("LOCPATH")
+
+	var stderr bytes.Buffer
+	if status := run(settings{name: "de"}, strings.NewReader(numeric), &stderr); status != exitOK {
+		t.Fatalf("exit status %d: %s", status, stderr.String())
+	}
+
+	// A category can be copied from the locale just written
+	copied := "LC_NUMERIC\ncopy \"de\"\nEND LC_NUMERIC\n"
+	out := filepath.Join(dir, "sub", "copy")
+	if status := run(settings{name: out}, strings.NewReader(copied), &stderr); status != exitOK {
+		t.Fatalf("exit status %d: %s", status, stderr.String())
+	}
+	de, err := locale.LoadDef(filepath.Join(dir, "de"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	copy, err := locale.LoadDef(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(de.Numeric, copy.Numeric) || copy.Copies != nil {
+		t.Errorf("LC_NUMERIC wasn't copied: %+v", copy)
+	}
+}
+
+func TestRunFailures(t *testing.T) {
+	dir, err := ioutil.TempDir("", "localedef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "out")
+
+	warning := "LC_MONETARY\np_sign_posn 9\nEND LC_MONETARY\n"
+	tests := []struct {
+		s      settings
+		input  string
+		status int
+	}{
+		{settings{name: out}, warning, exitErrors},
+		{settings{name: out, force: true}, warning, exitWarnings},
+		{settings{name: out, codeset: "ISO-8859-1"}, numeric, exitLimits},
+		{settings{name: out}, "LC_NUMERIC\ncopy \"nowhere/at/all\"\nEND LC_NUMERIC\n", exitErrors},
+		{settings{name: out}, "LC_NUMERIC\n", exitErrors},
+	}
+	for _, test := range tests {
+		os.Remove(out)
+		var stderr bytes.Buffer
+		status := run(test.s, strings.NewReader(test.input), &stderr)
+		if status != test.status {
+			t.Errorf("%+v: exit status %d, expected %d (%s)", test.s, status, test.status, stderr.String())
+		}
+		_, err := os.Stat(out)
+		if created := err == nil; created != (status < exitLimits) {
+			t.Errorf("%+v: exit status %d, but created is %v", test.s, status, created)
+		}
+	}
+}
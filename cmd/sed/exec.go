@@ -0,0 +1,391 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fwip/posix-utils/pkg/ed"
+	"github.com/fwip/posix-utils/pkg/regexes"
+	"github.com/fwip/posix-utils/pkg/util"
+)
+
+var errNoPrevious = errors.New("no previous regular expression")
+
+// input reads the lines of the input files as one stream, a line ahead,
+// so that it knows which line is the last
+type input struct {
+	u     *util.Utility
+	names []string
+	r     *bufio.Reader
+	f     io.Closer
+
+	next    string
+	hasNext bool
+}
+
+// fill reads the next line, from the next file that has one
+func (in *input) fill() {
+	for !in.hasNext {
+		if in.r == nil {
+			if len(in.names) == 0 {
+				return
+			}
+			f, err := in.u.Open(in.names[0])
+			in.names = in.names[1:]
+			if err != nil {
+				in.u.Warn(err)
+				continue
+			}
+			in.r, in.f = bufio.NewReader(f), f
+		}
+		line, err := in.r.ReadString('\n')
+		if line != "" {
+			in.next, in.hasNext = strings.TrimSuffix(line, "\n"), true
+		}
+		if err != nil {
+			if err != io.EOF {
+				in.u.Warn(err)
+			}
+			in.f.Close()
+			in.r, in.f = nil, nil
+		}
+	}
+}
+
+// read returns the next line, if there is one
+func (in *input) read() (string, bool) {
+	in.fill()
+	line, ok := in.next, in.hasNext
+	in.next, in.hasNext = "", false
+	return line, ok
+}
+
+// last reports whether there are no more lines
+func (in *input) last() bool {
+	in.fill()
+	return !in.hasNext
+}
+
+// queued is text for the end of the cycle, from a or r
+type queued struct {
+	text string
+	file bool // Whether text names a file to copy
+}
+
+// editor runs a script over its input
+type editor struct {
+	u      *util.Utility
+	quiet  bool
+	script *script
+	in     *input
+	out    *bufio.Writer
+	wfiles map[string]*bufio.Writer
+
+	space    string // The pattern space
+	hold     string // The hold space
+	lineno   int
+	lastRe   *regexes.Regexp
+	appends  []queued
+	replaced bool // Whether s has replaced anything since the last line read or t
+	quit     bool
+}
+
+// openFiles creates the files that the script writes to
+func (e *editor) openFiles() ([]*os.File, error) {
+	var files []*os.File
+	e.wfiles = make(map[string]*bufio.Writer)
+	for _, name := range e.script.wfiles {
+		if _, ok := e.wfiles[name]; ok {
+			continue
+		}
+		switch name {
+		case "/dev/stdout":
+			e.wfiles[name] = e.out
+			continue
+		case "/dev/stderr":
+			e.wfiles[name] = bufio.NewWriter(e.u.Stderr)
+			continue
+		}
+		f, err := os.Create(name)
+		if err != nil {
+			return files, err
+		}
+		files = append(files, f)
+		e.wfiles[name] = bufio.NewWriter(f)
+	}
+	return files, nil
+}
+
+// readLine reads the next line of input into the pattern space
+func (e *editor) readLine() bool {
+	line, ok := e.in.read()
+	if ok {
+		e.space = line
+		e.lineno++
+		e.replaced = false
+	}
+	return ok
+}
+
+// run runs the script over every line of input
+func (e *editor) run() error {
+	restart := false
+	for !e.quit {
+		if !restart && !e.readLine() {
+			break
+		}
+		var err error
+		if restart, err = e.execute(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// endCycle writes the pattern space, unless print is false or -n was given,
+// and then the text queued by a and r
+func (e *editor) endCycle(print bool) {
+	if print && !e.quiet {
+		e.out.WriteString(e.space + "\n")
+	}
+	for _, q := range e.appends {
+		if !q.file {
+			e.out.WriteString(q.text + "\n")
+			continue
+		}
+		// A file that can't be read is treated as empty
+		if f, err := os.Open(q.text); err == nil {
+			io.Copy(e.out, f)
+			f.Close()
+		}
+	}
+	e.appends = e.appends[:0]
+}
+
+// execute runs the script once over the pattern space. It reports whether
+// D started the next cycle without reading a new line.
+func (e *editor) execute() (bool, error) {
+	cmds := e.script.cmds
+	for pc := 0; pc < len(cmds); pc++ {
+		cmd := cmds[pc]
+		selected, err := e.selects(cmd)
+		if err != nil {
+			return false, err
+		}
+		if !selected {
+			if cmd.name == '{' {
+				pc = cmd.block
+			}
+			continue
+		}
+		switch cmd.name {
+		case '{', '}', ':':
+		case '=':
+			fmt.Fprintf(e.out, "%d\n", e.lineno)
+		case 'a':
+			e.appends = append(e.appends, queued{text: cmd.text})
+		case 'b':
+			pc = e.jump(cmd)
+		case 'c':
+			// A range is changed to one copy of the text, at its end
+			if len(cmd.addrs) < 2 || cmd.negate || !cmd.active {
+				e.out.WriteString(cmd.text + "\n")
+			}
+			e.endCycle(false)
+			return false, nil
+		case 'd':
+			e.endCycle(false)
+			return false, nil
+		case 'D':
+			i := strings.IndexByte(e.space, '\n')
+			e.endCycle(false)
+			if i < 0 {
+				return false, nil
+			}
+			e.space = e.space[i+1:]
+			return true, nil
+		case 'g':
+			e.space = e.hold
+		case 'G':
+			e.space += "\n" + e.hold
+		case 'h':
+			e.hold = e.space
+		case 'H':
+			e.hold += "\n" + e.space
+		case 'i':
+			e.out.WriteString(cmd.text + "\n")
+		case 'l':
+			e.out.WriteString(ed.List(e.space, ed.ListWidth) + "\n")
+		case 'n':
+			if e.in.last() {
+				e.quit = true
+				e.endCycle(true)
+				return false, nil
+			}
+			e.endCycle(true)
+			e.readLine()
+		case 'N':
+			// POSIX has N quit without writing the pattern space when
+			// there's no next line
+			if e.in.last() {
+				e.quit = true
+				e.endCycle(false)
+				return false, nil
+			}
+			space := e.space
+			e.endCycle(false)
+			e.readLine()
+			e.space = space + "\n" + e.space
+		case 'p':
+			e.out.WriteString(e.space + "\n")
+		case 'P':
+			first := e.space
+			if i := strings.IndexByte(first, '\n'); i >= 0 {
+				first = first[:i]
+			}
+			e.out.WriteString(first + "\n")
+		case 'q':
+			e.quit = true
+			e.endCycle(true)
+			return false, nil
+		case 'r':
+			e.appends = append(e.appends, queued{text: cmd.text, file: true})
+		case 's':
+			if err := e.substitute(cmd); err != nil {
+				return false, err
+			}
+		case 't':
+			if e.replaced {
+				e.replaced = false
+				pc = e.jump(cmd)
+			}
+		case 'w':
+			e.wfiles[cmd.text].WriteString(e.space + "\n")
+		case 'x':
+			e.space, e.hold = e.hold, e.space
+		case 'y':
+			e.space = strings.Map(func(r rune) rune {
+				if to, ok := cmd.trans[r]; ok {
+					return to
+				}
+				return r
+			}, e.space)
+		}
+	}
+	e.endCycle(true)
+	return false, nil
+}
+
+// jump returns where b or t goes: to just before its label, so that the
+// label is next, or to the end of the script
+func (e *editor) jump(cmd *command) int {
+	if cmd.block < 0 {
+		return len(e.script.cmds)
+	}
+	return cmd.block - 1
+}
+
+// regexp returns re, or the last regular expression used if it's nil
+func (e *editor) regexp(re *regexes.Regexp) (*regexes.Regexp, error) {
+	if re == nil {
+		re = e.lastRe
+		if re == nil {
+			return nil, errNoPrevious
+		}
+	}
+	e.lastRe = re
+	return re, nil
+}
+
+// selects reports whether a command applies to the pattern space
+func (e *editor) selects(cmd *command) (bool, error) {
+	m, err := e.matches(cmd)
+	return m != cmd.negate, err
+}
+
+func (e *editor) matches(cmd *command) (bool, error) {
+	switch len(cmd.addrs) {
+	case 0:
+		return true, nil
+	case 1:
+		return e.matchAddr(cmd.addrs[0])
+	}
+	end := cmd.addrs[1]
+	if !cmd.active {
+		m, err := e.matchAddr(cmd.addrs[0])
+		if !m || err != nil {
+			return false, err
+		}
+		// A range whose end is a line already reached is just one line
+		switch end.typ {
+		case aLine:
+			cmd.active = end.line > e.lineno
+		case aLast:
+			cmd.active = !e.in.last()
+		default:
+			cmd.active = true
+		}
+		return true, nil
+	}
+	switch end.typ {
+	case aLine:
+		// Lines may have been skipped past the end by n or N
+		if e.lineno > end.line {
+			cmd.active = false
+			return false, nil
+		}
+		cmd.active = e.lineno < end.line
+	default:
+		m, err := e.matchAddr(end)
+		if err != nil {
+			return false, err
+		}
+		cmd.active = !m
+	}
+	return true, nil
+}
+
+func (e *editor) matchAddr(a address) (bool, error) {
+	switch a.typ {
+	case aLine:
+		return e.lineno == a.line, nil
+	case aLast:
+		return e.in.last(), nil
+	}
+	re, err := e.regexp(a.re)
+	if err != nil {
+		return false, err
+	}
+	return re.MatchString(e.space), nil
+}
+
+func (e *editor) substitute(cmd *command) error {
+	sub := *cmd.sub
+	re, err := e.regexp(sub.Regexp)
+	if err != nil {
+		return err
+	}
+	if sub.Regexp == nil {
+		sub.Regexp = re
+		if err := sub.Check(); err != nil {
+			return err
+		}
+	}
+	space, ok := sub.Apply(e.space)
+	if !ok {
+		return nil
+	}
+	e.space = space
+	e.replaced = true
+	if cmd.print {
+		e.out.WriteString(e.space + "\n")
+	}
+	if cmd.wfile != "" {
+		e.wfiles[cmd.wfile].WriteString(e.space + "\n")
+	}
+	return nil
+}
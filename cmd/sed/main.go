@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/fwip/posix-utils/pkg/flag"
+	"github.com/fwip/posix-utils/pkg/util"
+)
+
+const usage = "usage: sed [-n] script [file...]\n" +
+	"       sed [-n] -e script [-e script]... [-f script_file]... [file...]\n" +
+	"       sed [-n] [-e script]... -f script_file [-f script_file]... [file...]"
+
+// scriptPart is a piece of the script, from -e or -f
+type scriptPart struct {
+	text string
+	file bool // Whether text names a script file
+}
+
+type settings struct {
+	quiet     bool
+	scripts   []scriptPart
+	filenames []string
+}
+
+// scriptFlag is the -e or -f option. The pieces of the script join in the
+// order they're given, whichever option gives them.
+type scriptFlag struct {
+	s    *settings
+	file bool
+}
+
+func (f scriptFlag) Set(value string) error {
+	f.s.scripts = append(f.s.scripts, scriptPart{value, f.file})
+	return nil
+}
+
+func (f scriptFlag) String() string { return "" }
+
+func parseSettings(args []string) (settings, error) {
+	var s settings
+	p := flag.Parser{Input: args}
+	p.BoolVar(&s.quiet, 'n', "don't write the pattern space at the end of each cycle")
+	p.Var(scriptFlag{&s, false}, 'e', "add `script` to the script")
+	p.Var(scriptFlag{&s, true}, 'f', "add the contents of `script_file` to the script")
+	operands, err := p.Parse()
+	if err != nil {
+		return s, err
+	}
+	if len(s.scripts) == 0 {
+		if len(operands) == 0 {
+			return s, errors.New("expected a script")
+		}
+		s.scripts = []scriptPart{{text: operands[0]}}
+		operands = operands[1:]
+	}
+	s.filenames = util.Operands(operands)
+	return s, nil
+}
+
+// loadScript joins the pieces of the script, each on its own lines
+func loadScript(u *util.Utility, parts []scriptPart) (string, error) {
+	texts := make([]string, len(parts))
+	for i, part := range parts {
+		if !part.file {
+			texts[i] = part.text
+			continue
+		}
+		f, err := u.Open(part.text)
+		if err != nil {
+			return "", err
+		}
+		b, err := ioutil.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+		texts[i] = strings.TrimSuffix(string(b), "\n")
+	}
+	return strings.Join(texts, "\n"), nil
+}
+
+// run edits the input with the script, and returns the exit status
+func run(u *util.Utility, s settings) int {
+	text, err := loadScript(u, s.scripts)
+	if err != nil {
+		u.Warn(err)
+		return 1
+	}
+	sc, err := parseScript(text)
+	if err != nil {
+		u.Warn(err)
+		return 1
+	}
+	// A script starting with #n is like -n
+	if text == "#n" || strings.HasPrefix(text, "#n\n") {
+		s.quiet = true
+	}
+
+	e := &editor{
+		u:      u,
+		quiet:  s.quiet,
+		script: sc,
+		in:     &input{u: u, names: s.filenames},
+		out:    bufio.NewWriter(u.Stdout),
+	}
+	files, err := e.openFiles()
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+	if err != nil {
+		u.Warn(err)
+		return 1
+	}
+	if err := e.run(); err != nil {
+		u.Warn(err)
+	}
+	for _, w := range e.wfiles {
+		w.Flush()
+	}
+	if err := e.out.Flush(); err != nil {
+		u.Warn(err)
+	}
+	return u.Status()
+}
+
+func main() {
+	u := util.New("sed", usage)
+	s, err := parseSettings(os.Args[1:])
+	if err != nil {
+		u.UsageError(err, util.UsageStatus)
+	}
+	u.Exit(run(u, s))
+}
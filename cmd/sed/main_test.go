@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fwip/posix-utils/pkg/ed"
+	"github.com/fwip/posix-utils/pkg/util"
+)
+
+func TestParseSettings(t *testing.T) {
+	tests := []struct {
+		args []string
+		want settings
+	}{
+		{[]string{"p"}, settings{false, []scriptPart{{"p", false}}, []string{"-"}}},
+		{[]string{"-n", "p", "a", "b"}, settings{true, []scriptPart{{"p", false}}, []string{"a", "b"}}},
+		{[]string{"-e", "p", "-f", "s", "-ed", "a"}, settings{false, []scriptPart{{"p", false}, {"s", true}, {"d", false}}, []string{"a"}}},
+		{[]string{"--", "-n"}, settings{false, []scriptPart{{"-n", false}}, []string{"-"}}},
+	}
+	for _, tt := range tests {
+		s, err := parseSettings(tt.args)
+		if err != nil || !reflect.DeepEqual(s, tt.want) {
+			t.Errorf("%q: got %+v, %v", tt.args, s, err)
+		}
+	}
+	for _, args := range [][]string{{}, {"-n"}, {"-e"}, {"-x", "p"}} {
+		if _, err := parseSettings(args); err == nil {
+			t.Errorf("%q: expected an error", args)
+		}
+	}
+}
+
+func TestParseScriptErrors(t *testing.T) {
+	for _, script := range []string{
+		"k", "p x", "{p", "}", "1,2=", "1,2q", "1:a", ":", ":a\n:a", "b a",
+		"s/a/b", "s/a/\\1/", "s/a/b/0", "s/a/b/w", "y/ab/c/", "y/aa/bc/", "y/a\\b/c/",
+		"0p", "1,p", "/a", "r", "w", "\\\np",
+	} {
+		if _, err := parseScript(script); err == nil {
+			t.Errorf("%q: expected an error", script)
+		}
+	}
+}
+
+var lines = "one\ntwo\nthree\nfour\n"
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	rfile := filepath.Join(dir, "r")
+	if err := ioutil.WriteFile(rfile, []byte("read\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wfile := filepath.Join(dir, "w")
+
+	tests := []struct {
+		quiet  bool
+		script string
+		input  string
+		want   string
+	}{
+		{false, "", lines, lines},
+		{true, "2,3p", lines, "two\nthree\n"},
+		{true, "$=", lines, "4\n"},
+		{false, "s/o/0/g;2d", lines, "0ne\nthree\nf0ur\n"},
+		{false, "s/o/0/2", "foo\n", "fo0\n"},
+		{false, `s/\(.\)\(.\)/\2\1/`, "abc\n", "bac\n"},
+		{false, `s/o/\n/`, "xoy\n", "x\ny\n"},
+		{false, "s/o/\\\n/", "xoy\n", "x\ny\n"},
+		{false, "s|/|\\||g", "a/b/c\n", "a|b|c\n"},
+		{false, "s/b*/X/g", "abc\n", "XaXcX\n"},
+		{true, "s/e/E/p", lines, "onE\nthrEe\n"},
+		{true, "/two/,/three/p", lines, "two\nthree\n"},
+		{true, "/t/,/t/p", lines, "two\nthree\n"},
+		{true, "2,1p", lines, "two\n"},
+		{true, "3,$p", lines, "three\nfour\n"},
+		{true, "/o/!p", lines, "three\n"},
+		{true, "/t/,2!p", lines, "one\nfour\n"},
+		{true, "/e/{/n/p;}", lines, "one\n"},
+		{true, "1,3{2!{p}}", lines, "one\nthree\n"},
+		{true, "/t/p;//p", lines, "two\ntwo\nthree\nthree\n"},
+		{false, "1!G;h;$!d", lines, "four\nthree\ntwo\none\n"},
+		{false, "$!N;s/\\n/+/", lines, "one+two\nthree+four\n"},
+		{false, "$!N;P;D", lines, lines},
+		{false, "N;N;s/\\n/,/g", "a\nb\nc\nd\n", "a,b,c\n"},
+		{false, "N", "a\nb\nc\n", "a\nb\n"},
+		{false, "1a\\\nafter\nN", "a\n", "after\n"},
+		{true, "n;p", lines, "two\nfour\n"},
+		{false, "n;d", lines, "one\nthree\n"},
+		{false, "x;$!d;G", "a\nb\n", "a\nb\n"},
+		{false, "H;$!d;x", "a\nb\n", "\na\nb\n"},
+		{false, "g", "a\n", "\n"},
+		{false, "2q", lines, "one\ntwo\n"},
+		{false, "2a\\\nafter\n3i\\\nbefore\\\nlines", lines, "one\ntwo\nafter\nbefore\nlines\nthree\nfour\n"},
+		{false, "1a\\\n  indented\\\\", "x\n", "x\n  indented\\\n"},
+		{false, "2,3c\\\nchanged", lines, "one\nchanged\nfour\n"},
+		{false, "2,3!c\\\nchanged", lines, "changed\ntwo\nthree\nchanged\n"},
+		{false, "$c\\\nend", lines, "one\ntwo\nthree\nend\n"},
+		{false, "1a\\\nafter\n1q", lines, "one\nafter\n"},
+		{false, "1r " + rfile + "\n1r nonexistent", "a\nb\n", "a\nread\nb\n"},
+		{false, "y/abc/xyz/", "aabbcc\n", "xxyyzz\n"},
+		{false, "y/a\\/\\n/\\nb\\\\/", "a/\n", "\nb\n"},
+		{false, ":a\ns/^.\\{1,3\\}$/ &/\nta", "ab\n", "  ab\n"},
+		{false, "s/x/y/;tend\ns/$/!/\n:end", "x\na\n", "y\na!\n"},
+		{false, "b\np", "a\n", "a\n"},
+		{false, "2b end;s/^/-/;:end", "a\nb\nc\n", "-a\nb\n-c\n"},
+		{true, "l", "a\tb\\\x01\n", "a\\tb\\\\\\001$\n"},
+		{true, "$!N;l", "a\nb\n", "a\\nb$\n"},
+		{true, "l", strings.Repeat("a", 80) + "\n", ed.List(strings.Repeat("a", 80), ed.ListWidth) + "\n"},
+		{true, "l", strings.Repeat("a", 80) + "\n", strings.Repeat("a", 71) + "\\\n" + strings.Repeat("a", 9) + "$\n"},
+		{true, "=", "a\nb\n", "1\n2\n"},
+		{false, "P;d", "a\n", "a\n"},
+		{false, "#n\np", "a\n", "a\n"},
+		{false, "# comment\np # trailing", "a\n", "a\na\n"},
+		{false, "w " + wfile + "\ns/a/b/w " + wfile, "a\nc\n", "b\nc\n"},
+		{false, `/\(a\)\1/d`, "aa\nab\n", "ab\n"},
+		{false, `\,x,d`, "x\ny\n", "y\n"},
+		{false, "s/[/]/x/", "a/b\n", "axb\n"},
+		{false, `s.a\.b.x.g`, "axb a.b\n", "axb x\n"},
+		{false, `\.a\..d`, "axb\na.b\n", "axb\n"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		u := &util.Utility{Name: "sed", Stdin: strings.NewReader(tt.input), Stdout: &stdout, Stderr: &stderr}
+		s := settings{tt.quiet, []scriptPart{{tt.script, false}}, []string{"-"}}
+		if status := run(u, s); status != 0 || stdout.String() != tt.want {
+			t.Errorf("%q: got %q, status %d, %s, expected %q", tt.script, stdout.String(), status, stderr.String(), tt.want)
+		}
+	}
+
+	got, err := ioutil.ReadFile(wfile)
+	if err != nil || string(got) != "a\nb\nc\n" {
+		t.Errorf("w file: got %q, %v", got, err)
+	}
+}
+
+func TestRunFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	a := filepath.Join(dir, "a")
+	script := filepath.Join(dir, "script")
+	if err := ioutil.WriteFile(a, []byte("one\ntwo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(script, []byte("s/o/0/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The files are one stream, and one that can't be read is skipped
+	var stdout, stderr bytes.Buffer
+	u := &util.Utility{Name: "sed", Stdin: strings.NewReader("three\n"), Stdout: &stdout, Stderr: &stderr}
+	s := settings{false, []scriptPart{{"2p", false}, {script, true}, {"$=", false}}, []string{a, filepath.Join(dir, "missing"), "-"}}
+	status := run(u, s)
+	if want := "0ne\ntwo\ntw0\n3\nthree\n"; status != 1 || stdout.String() != want {
+		t.Errorf("got %q, status %d, expected %q", stdout.String(), status, want)
+	}
+	if !strings.Contains(stderr.String(), "missing") {
+		t.Errorf("expected a diagnostic, got %q", stderr.String())
+	}
+}
@@ -0,0 +1,436 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fwip/posix-utils/pkg/ed"
+	"github.com/fwip/posix-utils/pkg/regexes"
+)
+
+type addressType byte
+
+const (
+	aLine addressType = iota
+	aLast
+	aRegexp
+)
+
+type address struct {
+	typ  addressType
+	line int
+	re   *regexes.Regexp // nil for the last regular expression used
+}
+
+// command is one command of a script
+type command struct {
+	addrs  []address
+	negate bool
+	name   byte
+	active bool // Whether a range of lines is being selected
+
+	text  string // Text for a, c and i; a label for b, t and :; a file for r and w
+	block int    // For {, the index of its }; for b and t, of the label
+
+	sub   *ed.Substitution
+	print bool   // The s command's p flag
+	wfile string // The s command's w flag
+
+	trans map[rune]rune // For y
+}
+
+// script is a parsed script
+type script struct {
+	cmds   []*command
+	wfiles []string // Files that w commands and flags write to
+}
+
+// scriptParser holds the state of parsing a script
+type scriptParser struct {
+	s      string
+	pos    int
+	line   int
+	blocks []int // Indexes of the unclosed {s
+	labels map[string]int
+	script
+}
+
+func (p *scriptParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("script line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *scriptParser) more() bool {
+	return p.pos < len(p.s)
+}
+
+func (p *scriptParser) peek() byte {
+	if !p.more() {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+// skipBlanks skips spaces and tabs
+func (p *scriptParser) skipBlanks() {
+	for p.peek() == ' ' || p.peek() == '\t' {
+		p.pos++
+	}
+}
+
+// restOfLine returns the text up to the next newline, and skips the newline
+func (p *scriptParser) restOfLine() string {
+	end := strings.IndexByte(p.s[p.pos:], '\n')
+	if end < 0 {
+		end = len(p.s) - p.pos
+	}
+	text := p.s[p.pos : p.pos+end]
+	p.pos += end
+	return text
+}
+
+// parseScript parses the text of a script
+func parseScript(s string) (*script, error) {
+	p := &scriptParser{s: s, line: 1, labels: make(map[string]int)}
+	for {
+		// Skip the separators and comments between commands
+		for p.more() && strings.IndexByte(" \t\n;", p.peek()) >= 0 {
+			if p.peek() == '\n' {
+				p.line++
+			}
+			p.pos++
+		}
+		if p.peek() == '#' {
+			p.restOfLine()
+			continue
+		}
+		if !p.more() {
+			break
+		}
+		if err := p.command(); err != nil {
+			return nil, err
+		}
+	}
+	if len(p.blocks) > 0 {
+		return nil, p.errorf("unmatched {")
+	}
+	for _, cmd := range p.cmds {
+		if (cmd.name == 'b' || cmd.name == 't') && cmd.text != "" {
+			i, ok := p.labels[cmd.text]
+			if !ok {
+				return nil, fmt.Errorf("can't find label for jump to %s", cmd.text)
+			}
+			cmd.block = i
+		}
+	}
+	return &p.script, nil
+}
+
+// command parses one command, with its addresses
+func (p *scriptParser) command() error {
+	cmd := &command{block: -1}
+	for len(cmd.addrs) < 2 {
+		if len(cmd.addrs) == 1 {
+			if p.peek() != ',' {
+				break
+			}
+			p.pos++
+			p.skipBlanks()
+		}
+		a, ok, err := p.address()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if len(cmd.addrs) == 1 {
+				return p.errorf("expected an address after ,")
+			}
+			break
+		}
+		cmd.addrs = append(cmd.addrs, a)
+	}
+	p.skipBlanks()
+	for p.peek() == '!' {
+		cmd.negate = true
+		p.pos++
+		p.skipBlanks()
+	}
+	if !p.more() {
+		return p.errorf("missing command")
+	}
+	cmd.name = p.s[p.pos]
+	p.pos++
+
+	maxAddrs := 2
+	switch cmd.name {
+	case ':', '}':
+		maxAddrs = 0
+	case '=', 'q':
+		maxAddrs = 1
+	}
+	if len(cmd.addrs) > maxAddrs || (maxAddrs == 0 && cmd.negate) {
+		return p.errorf("%c doesn't take that many addresses", cmd.name)
+	}
+
+	switch cmd.name {
+	case '{':
+		p.blocks = append(p.blocks, len(p.cmds))
+		p.cmds = append(p.cmds, cmd)
+		return nil
+	case '}':
+		if len(p.blocks) == 0 {
+			return p.errorf("unexpected }")
+		}
+		p.cmds[p.blocks[len(p.blocks)-1]].block = len(p.cmds)
+		p.blocks = p.blocks[:len(p.blocks)-1]
+	case 'a', 'i', 'c':
+		cmd.text = p.text()
+	case ':':
+		p.skipBlanks()
+		label := p.label()
+		if label == "" {
+			return p.errorf(": needs a label")
+		}
+		if _, ok := p.labels[label]; ok {
+			return p.errorf("duplicate label %s", label)
+		}
+		p.labels[label] = len(p.cmds)
+	case 'b', 't':
+		p.skipBlanks()
+		cmd.text = p.label()
+	case 'r', 'w':
+		p.skipBlanks()
+		cmd.text = p.restOfLine()
+		if cmd.text == "" {
+			return p.errorf("%c needs a file", cmd.name)
+		}
+		if cmd.name == 'w' {
+			p.wfiles = append(p.wfiles, cmd.text)
+		}
+	case 's':
+		if err := p.substitute(cmd); err != nil {
+			return err
+		}
+	case 'y':
+		if err := p.translate(cmd); err != nil {
+			return err
+		}
+	case '=', 'd', 'D', 'g', 'G', 'h', 'H', 'l', 'n', 'N', 'p', 'P', 'q', 'x':
+	default:
+		return p.errorf("unknown command: %c", cmd.name)
+	}
+	p.cmds = append(p.cmds, cmd)
+	return p.end()
+}
+
+// end checks that nothing but a separator follows a command
+func (p *scriptParser) end() error {
+	p.skipBlanks()
+	switch p.peek() {
+	case 0, '\n', ';', '}', '#':
+		return nil
+	}
+	return p.errorf("extra characters after command")
+}
+
+// label reads a label, which ends at a newline or semicolon
+func (p *scriptParser) label() string {
+	start := p.pos
+	for p.more() && p.peek() != '\n' && p.peek() != ';' {
+		p.pos++
+	}
+	return strings.TrimRight(p.s[start:p.pos], " \t")
+}
+
+// address reads an address, if there is one
+func (p *scriptParser) address() (address, bool, error) {
+	switch c := p.peek(); {
+	case c >= '0' && c <= '9':
+		start := p.pos
+		for p.peek() >= '0' && p.peek() <= '9' {
+			p.pos++
+		}
+		n, err := strconv.Atoi(p.s[start:p.pos])
+		if err != nil || n == 0 {
+			return address{}, false, p.errorf("invalid line number %s", p.s[start:p.pos])
+		}
+		return address{typ: aLine, line: n}, true, nil
+	case c == '$':
+		p.pos++
+		return address{typ: aLast}, true, nil
+	case c == '/' || c == '\\':
+		if c == '\\' {
+			p.pos++
+		}
+		delim, size := utf8.DecodeRuneInString(p.s[p.pos:])
+		if delim == '\n' || delim == '\\' || delim == utf8.RuneError {
+			return address{}, false, p.errorf("invalid delimiter")
+		}
+		pattern, rest, err := ed.ReadDelimited(p.s[p.pos+size:], delim, true)
+		if err != nil {
+			return address{}, false, p.errorf("%s", err)
+		}
+		p.pos = len(p.s) - len(rest)
+		re, err := compile(pattern)
+		if err != nil {
+			return address{}, false, err
+		}
+		return address{typ: aRegexp, re: re}, true, nil
+	}
+	return address{}, false, nil
+}
+
+// compile compiles a regular expression, which may contain \n for a
+// newline. An empty expression stands for the last one used, so is nil.
+func compile(pattern string) (*regexes.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	return regexes.Compile(unescapeNewlines(pattern), 0)
+}
+
+// unescapeNewlines replaces each \n in s with a newline. Other escapes are
+// left alone.
+func unescapeNewlines(s string) string {
+	if !strings.Contains(s, `\n`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			if s[i+1] == 'n' {
+				b.WriteByte('\n')
+			} else {
+				b.WriteString(s[i : i+2])
+			}
+			i++
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// text reads the text argument of a, c or i. It follows a \ and a newline,
+// or, as an extension, is on the same line. A \ before a newline continues
+// the text onto the next line; before anything else, it's removed.
+func (p *scriptParser) text() string {
+	p.skipBlanks()
+	if strings.HasPrefix(p.s[p.pos:], "\\\n") {
+		p.pos += 2
+		p.line++
+	} else if p.peek() == '\\' {
+		p.pos++
+	}
+	var b strings.Builder
+	for p.more() && p.peek() != '\n' {
+		c := p.s[p.pos]
+		p.pos++
+		if c == '\\' && p.more() {
+			c = p.s[p.pos]
+			p.pos++
+			if c == '\n' {
+				p.line++
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// substitute reads the arguments and flags of an s command
+func (p *scriptParser) substitute(cmd *command) error {
+	pattern, replacement, rest, err := ed.ReadSubstitution(p.s[p.pos:])
+	if err != nil {
+		return p.errorf("s: %s", err)
+	}
+	p.line += strings.Count(p.s[p.pos:len(p.s)-len(rest)], "\n")
+	p.pos = len(p.s) - len(rest)
+	cmd.sub = &ed.Substitution{Replacement: unescapeNewlines(replacement)}
+	if cmd.sub.Regexp, err = compile(pattern); err != nil {
+		return err
+	}
+	if cmd.sub.Regexp != nil {
+		if err := cmd.sub.Check(); err != nil {
+			return p.errorf("%s", err)
+		}
+	}
+	for p.more() {
+		switch c := p.peek(); {
+		case c == 'g':
+			cmd.sub.Global = true
+			p.pos++
+		case c == 'p':
+			cmd.print = true
+			p.pos++
+		case c >= '0' && c <= '9':
+			start := p.pos
+			for p.peek() >= '0' && p.peek() <= '9' {
+				p.pos++
+			}
+			n, err := strconv.Atoi(p.s[start:p.pos])
+			if err != nil || n == 0 {
+				return p.errorf("s: invalid occurrence %s", p.s[start:p.pos])
+			}
+			cmd.sub.Occurrence = n
+		case c == 'w':
+			p.pos++
+			p.skipBlanks()
+			cmd.wfile = p.restOfLine()
+			if cmd.wfile == "" {
+				return p.errorf("s: w needs a file")
+			}
+			p.wfiles = append(p.wfiles, cmd.wfile)
+			return nil
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// translate reads the arguments of a y command
+func (p *scriptParser) translate(cmd *command) error {
+	delim, size := utf8.DecodeRuneInString(p.s[p.pos:])
+	if !p.more() || delim == '\\' || delim == '\n' {
+		return p.errorf("y: invalid delimiter")
+	}
+	p.pos += size
+	var sides [2][]rune
+	for i := range sides {
+		for {
+			if !p.more() || p.peek() == '\n' {
+				return p.errorf("y: unterminated string")
+			}
+			r, size := utf8.DecodeRuneInString(p.s[p.pos:])
+			p.pos += size
+			if r == delim {
+				break
+			}
+			if r == '\\' {
+				r, size = utf8.DecodeRuneInString(p.s[p.pos:])
+				p.pos += size
+				switch r {
+				case 'n':
+					r = '\n'
+				case '\\', delim:
+				default:
+					return p.errorf("y: invalid escape \\%c", r)
+				}
+			}
+			sides[i] = append(sides[i], r)
+		}
+	}
+	if len(sides[0]) != len(sides[1]) {
+		return p.errorf("y: strings are different lengths")
+	}
+	cmd.trans = make(map[rune]rune)
+	for i, r := range sides[0] {
+		if _, ok := cmd.trans[r]; ok {
+			return p.errorf("y: %c is repeated", r)
+		}
+		cmd.trans[r] = sides[1][i]
+	}
+	return nil
+}
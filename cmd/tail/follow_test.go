@@ -0,0 +1,128 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package main
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/fwip/posix-utils/pkg/util"
+)
+
+// writeFIFO opens the FIFO name for writing, which waits for a reader, and
+// writes s to it
+func writeFIFO(t *testing.T, name, s string) {
+	f, err := os.OpenFile(name, os.O_WRONLY, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err := f.WriteString(s); err != nil {
+		t.Error(err)
+	}
+	f.Close()
+}
+
+// startFollow runs tail with s in the background, and returns a function
+// that waits for the next line it writes. tail never returns while it's
+// following, so it's left blocked when the test ends.
+func startFollow(t *testing.T, u *util.Utility, s settings) (expect func(want string)) {
+	pr, pw := io.Pipe()
+	u.Stdout = pw
+	go func() {
+		err := tail(u, s, bufio.NewWriter(pw))
+		pw.CloseWithError(err)
+	}()
+
+	lines := make(chan string)
+	go func() {
+		r := bufio.NewReader(pr)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				close(lines)
+				return
+			}
+			lines <- line
+		}
+	}()
+	return func(want string) {
+		t.Helper()
+		select {
+		case line, ok := <-lines:
+			if !ok || line != want {
+				t.Fatalf("got %q, %v, expected %q", line, ok, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %q", want)
+		}
+	}
+}
+
+func TestFollowFIFO(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fifo := filepath.Join(dir, "fifo")
+	if err := syscall.Mkfifo(fifo, 0600); err != nil {
+		t.Fatal(err)
+	}
+	defer func(d time.Duration) { pollInterval = d }(pollInterval)
+	pollInterval = 10 * time.Millisecond
+
+	u := &util.Utility{Name: "tail", Stdin: strings.NewReader(""), Stderr: ioutil.Discard}
+	expect := startFollow(t, u, settings{filename: fifo, numBytes: -1, numLines: 1, follow: true})
+
+	writeFIFO(t, fifo, "a\nb\n")
+	expect("b\n")
+	// Data from a writer that opens the FIFO after the first one closed it
+	// is still copied
+	writeFIFO(t, fifo, "c\n")
+	expect("c\n")
+}
+
+func TestFollowTruncated(t *testing.T) {
+	f, err := ioutil.TempFile("", "tail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	if _, err := f.WriteString("a\nb\n"); err != nil {
+		t.Fatal(err)
+	}
+	defer func(d time.Duration) { pollInterval = d }(pollInterval)
+	pollInterval = 10 * time.Millisecond
+
+	var stderr strings.Builder
+	u := &util.Utility{Name: "tail", Stdin: strings.NewReader(""), Stderr: &stderr}
+	expect := startFollow(t, u, settings{filename: f.Name(), numBytes: -1, numLines: 1, follow: true})
+	expect("b\n")
+
+	// The file is shorter than tail has read, so it's reread from the start
+	if err := f.Truncate(0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteAt([]byte("c\n"), 0); err != nil {
+		t.Fatal(err)
+	}
+	expect("c\n")
+
+	if !strings.Contains(stderr.String(), "file truncated") {
+		t.Errorf("stderr: got %q, expected a notice", stderr.String())
+	}
+	// Truncation isn't an error
+	if u.Status() != 0 {
+		t.Errorf("status: got %d, wanted 0", u.Status())
+	}
+}
@@ -1,40 +1,351 @@
 package main
 
-import "fmt"
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/fwip/posix-utils/pkg/flag"
+	"github.com/fwip/posix-utils/pkg/util"
+)
+
+// pollInterval is how often a followed file is checked for new data
+var pollInterval = time.Second
 
 type settings struct {
 	filename  string
-	numBytes  int
-	numLines  int
-	fromStart bool
+	numBytes  int64 // When non-negative, count bytes instead of lines
+	numLines  int64
+	fromStart bool // Count from the beginning of the file (+N) rather than the end
 	follow    bool
 }
 
+// parseCount parses a -c or -n value, which may be given as +N, -N or N
+func parseCount(s string) (n int64, fromStart bool, err error) {
+	value := s
+	if len(value) > 0 && (value[0] == '+' || value[0] == '-') {
+		fromStart = value[0] == '+'
+		value = value[1:]
+	}
+	n, err = strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 || value == "" || value[0] < '0' || value[0] > '9' {
+		return 0, false, fmt.Errorf("invalid count: %s", s)
+	}
+	return n, fromStart, nil
+}
+
+// count is the -n or -c option. As the last one given wins, -n undoes -c.
+type count struct {
+	s     *settings
+	bytes bool
+}
+
+func (c count) Set(value string) error {
+	n, fromStart, err := parseCount(value)
+	if err != nil {
+		return err
+	}
+	c.s.fromStart = fromStart
+	if c.bytes {
+		c.s.numBytes = n
+	} else {
+		c.s.numBytes = -1
+		c.s.numLines = n
+	}
+	return nil
+}
+
+func (c count) String() string { return "" }
+
 func parseSettings(args []string) (settings, error) {
 	s := settings{
 		filename: "-",
+		numBytes: -1,
 		numLines: 10,
 	}
-	for i := 0; i < len(args); i++ {
-		a := args[i]
-		switch a {
-		case "-c":
-			s.writeAll = true
-		case "-s":
-			s.silent = true
-		default:
-			if s.file1 == "" {
-				s.file1 = a
-			} else if s.file2 == "" {
-				s.file2 = a
+	p := flag.Parser{Input: args}
+	p.BoolVar(&s.follow, 'f', "keep copying data appended to the file")
+	p.Var(count{&s, true}, 'c', "copy the last `number` bytes, or from byte +number")
+	p.Var(count{&s, false}, 'n', "copy the last `number` lines, or from line +number")
+	operands, err := p.Parse()
+	if err != nil {
+		return s, err
+	}
+
+	if len(operands) > 1 {
+		return s, fmt.Errorf("too many files: %s", operands[1])
+	}
+	if len(operands) == 1 {
+		s.filename = operands[0]
+	}
+	return s, nil
+}
+
+// skipLines discards the first n lines of r
+func skipLines(r *bufio.Reader, n int64) error {
+	for n > 0 {
+		_, err := r.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		n--
+	}
+	return nil
+}
+
+// skipBytes discards the first n bytes of r, seeking past them when possible
+func skipBytes(r io.Reader, n int64) error {
+	if seeker, ok := r.(io.Seeker); ok {
+		if _, err := seeker.Seek(n, io.SeekCurrent); err == nil {
+			return nil
+		}
+	}
+	_, err := io.CopyN(ioutil.Discard, r, n)
+	return err
+}
+
+// fromStart copies r to w, starting at the n-th line or byte (counting from one)
+func fromStart(s settings, r io.Reader, w io.Writer) error {
+	var err error
+	if s.numBytes >= 0 {
+		if s.numBytes > 1 {
+			err = skipBytes(r, s.numBytes-1)
+		}
+	} else {
+		br := bufio.NewReader(r)
+		if s.numLines > 1 {
+			err = skipLines(br, s.numLines-1)
+		}
+		r = br
+	}
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
+
+// lastLinesOffset finds where the last n lines of f begin by reading
+// backwards from its end, so the cost doesn't depend on the file's size.
+func lastLinesOffset(f *os.File, size, n int64) (int64, error) {
+	if n == 0 {
+		return size, nil
+	}
+	buf := make([]byte, 32*1024)
+	pos := size
+	var count int64
+	for pos > 0 {
+		chunk := int64(len(buf))
+		if pos < chunk {
+			chunk = pos
+		}
+		pos -= chunk
+		b := buf[:chunk]
+		if _, err := f.ReadAt(b, pos); err != nil && err != io.EOF {
+			return 0, err
+		}
+		for i := len(b) - 1; i >= 0; i-- {
+			if b[i] != '\n' || pos+int64(i) == size-1 {
+				// A trailing newline ends the last line rather than starting one
+				continue
+			}
+			count++
+			if count == n {
+				return pos + int64(i) + 1, nil
+			}
+		}
+	}
+	return 0, nil
+}
+
+// fromEndSeekable copies the end of the regular file f to w
+func fromEndSeekable(s settings, f *os.File, w io.Writer) error {
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	var start int64
+	if s.numBytes >= 0 {
+		start = size - s.numBytes
+		if start < 0 {
+			start = 0
+		}
+	} else {
+		start, err = lastLinesOffset(f, size, s.numLines)
+		if err != nil {
+			return err
+		}
+	}
+	if _, err = f.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// fromEndStream copies the end of r to w, holding only what it must in memory
+func fromEndStream(s settings, r io.Reader, w io.Writer) error {
+	if s.numBytes >= 0 {
+		var kept []byte
+		buf := make([]byte, 32*1024)
+		for {
+			m, err := r.Read(buf)
+			kept = append(kept, buf[:m]...)
+			if int64(len(kept)) > 2*s.numBytes+int64(len(buf)) {
+				kept = append(kept[:0], kept[int64(len(kept))-s.numBytes:]...)
+			}
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if int64(len(kept)) > s.numBytes {
+			kept = kept[int64(len(kept))-s.numBytes:]
+		}
+		_, err := w.Write(kept)
+		return err
+	}
+
+	if s.numLines == 0 {
+		_, err := io.Copy(ioutil.Discard, r)
+		return err
+	}
+	// ring holds the most recent lines, with next being the oldest once it's full
+	var ring [][]byte
+	next := 0
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			if int64(len(ring)) < s.numLines {
+				ring = append(ring, line)
 			} else {
-				return s, fmt.Errorf("too many files: %s", a)
+				ring[next] = line
+				next = (next + 1) % len(ring)
 			}
 		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
 	}
-	return s, nil
+	for i := range ring {
+		if _, err := w.Write(ring[(next+i)%len(ring)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// follow copies data appended to f to w until the process is killed.
+// If a regular file shrinks, it's assumed to have been truncated and is
+// reread from the beginning.
+func follow(u *util.Utility, f *os.File, w *bufio.Writer, regular bool) error {
+	var offset int64
+	if regular {
+		var err error
+		if offset, err = f.Seek(0, io.SeekCurrent); err != nil {
+			return err
+		}
+	}
+	for {
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		time.Sleep(pollInterval)
+
+		if regular {
+			fi, err := f.Stat()
+			if err != nil {
+				return err
+			}
+			if fi.Size() < offset {
+				// A notice, not a failure
+				u.Report(fmt.Errorf("%s: file truncated", f.Name()))
+				if offset, err = f.Seek(0, io.SeekStart); err != nil {
+					return err
+				}
+			}
+		}
+		n, err := io.Copy(w, f)
+		offset += n
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func tail(u *util.Utility, s settings, w *bufio.Writer) error {
+	// The standard input is used as it is, rather than through u.Open, so
+	// that it can be seeked and followed when it's a file
+	r := u.Stdin
+	if s.filename != "-" {
+		rc, err := u.Open(s.filename)
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		r = rc
+	}
+
+	f, _ := r.(*os.File)
+	var mode os.FileMode
+	if f != nil {
+		fi, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		mode = fi.Mode()
+	}
+	seekable := f != nil && mode.IsRegular()
+
+	var err error
+	switch {
+	case s.fromStart:
+		err = fromStart(s, r, w)
+	case seekable:
+		err = fromEndSeekable(s, f, w)
+	default:
+		err = fromEndStream(s, r, w)
+	}
+	if err != nil {
+		return err
+	}
+
+	// A FIFO operand is followed for whatever later writers send, but as
+	// POSIX says, -f is ignored for a pipe on the standard input
+	fifo := mode&os.ModeNamedPipe != 0 && s.filename != "-"
+	if s.follow && (seekable || fifo) {
+		return follow(u, f, w, seekable)
+	}
+	return nil
 }
 
 func main() {
-	fmt.Println("vim-go")
+	u := util.New("tail", "usage: tail [-f] [-c number | -n number] [file]")
+	s, err := parseSettings(os.Args[1:])
+	if err != nil {
+		u.UsageError(err, util.UsageStatus)
+	}
+
+	out := bufio.NewWriter(u.Stdout)
+	err = tail(u, s, out)
+	out.Flush()
+	if err != nil {
+		u.Fatal(err, 1)
+	}
 }
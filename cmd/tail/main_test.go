@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fwip/posix-utils/pkg/util"
+)
+
+var tailTests = []struct {
+	args  string
+	input string
+	want  string
+}{
+	{"", "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n", "3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"},
+	{"-n 2", "a\nb\nc\n", "b\nc\n"},
+	{"-n 2", "a\nb\nc", "b\nc"},
+	{"-n -2", "a\nb\nc\n", "b\nc\n"},
+	{"-n 0", "a\nb\nc\n", ""},
+	{"-n 5", "a\nb\n", "a\nb\n"},
+	{"-n +2", "a\nb\nc\n", "b\nc\n"},
+	{"-n +0", "a\nb\n", "a\nb\n"},
+	{"-n +5", "a\nb\n", ""},
+	{"-c 3", "abcdef", "def"},
+	{"-c +3", "abcdef", "cdef"},
+	{"-c 10", "abc", "abc"},
+	{"-n 1", "\n\n\n", "\n"},
+	{"-n 2", strings.Repeat("x", 100000) + "\n" + strings.Repeat("y", 70000) + "\nz\n", strings.Repeat("y", 70000) + "\nz\n"},
+}
+
+// runTail runs tail on input, once through a regular file and once through a pipe
+func runTail(t *testing.T, args, input string) (fromFile, fromPipe string) {
+	s, err := parseSettings(strings.Fields(args))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := ioutil.TempFile("", "tailtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err = f.WriteString(input); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	var out strings.Builder
+	s.filename = f.Name()
+	w := bufio.NewWriter(&out)
+	u := &util.Utility{Name: "tail", Stdout: &out, Stderr: ioutil.Discard}
+	if err = tail(u, s, w); err != nil {
+		t.Fatal(err)
+	}
+	w.Flush()
+	fromFile = out.String()
+
+	out.Reset()
+	if s.fromStart {
+		err = fromStart(s, strings.NewReader(input), &out)
+	} else {
+		err = fromEndStream(s, strings.NewReader(input), &out)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fromFile, out.String()
+}
+
+func TestTail(t *testing.T) {
+	for _, test := range tailTests {
+		fromFile, fromPipe := runTail(t, test.args, test.input)
+		if fromFile != test.want {
+			t.Errorf("tail %s (file) on %.20q: got %.20q, wanted %.20q", test.args, test.input, fromFile, test.want)
+		}
+		if fromPipe != test.want {
+			t.Errorf("tail %s (pipe) on %.20q: got %.20q, wanted %.20q", test.args, test.input, fromPipe, test.want)
+		}
+	}
+}
+
+func TestParseSettings(t *testing.T) {
+	bad := []string{"-n", "-n x", "-c 1 a b", "-z", "-n +-3"}
+	for _, args := range bad {
+		if _, err := parseSettings(strings.Fields(args)); err == nil {
+			t.Errorf("%q: expected an error", args)
+		}
+	}
+}
+
+func TestTailStdin(t *testing.T) {
+	var out strings.Builder
+	u := &util.Utility{Name: "tail", Stdin: strings.NewReader("a\nb\nc\n"), Stdout: &out, Stderr: ioutil.Discard}
+	s, err := parseSettings([]string{"-n", "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := bufio.NewWriter(&out)
+	if err := tail(u, s, w); err != nil {
+		t.Fatal(err)
+	}
+	w.Flush()
+	if out.String() != "b\nc\n" {
+		t.Errorf("got %q", out.String())
+	}
+}
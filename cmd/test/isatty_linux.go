@@ -0,0 +1,12 @@
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+func isatty(fd int) bool {
+	var termios syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), syscall.TCGETS, uintptr(unsafe.Pointer(&termios)))
+	return errno == 0
+}
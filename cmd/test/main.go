@@ -3,48 +3,199 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+
+	"github.com/fwip/posix-utils/pkg/util"
 )
 
+// test takes no options, as "-" begins so many of its operands, so its
+// arguments aren't given to pkg/flag
 func main() {
-	ok, err := parse(os.Args[1:])
+	u := util.New("test", "")
+	args := os.Args[1:]
+
+	// When invoked as "[", the expression must be closed by a "]"
+	if filepath.Base(os.Args[0]) == "[" {
+		u.Name = "["
+		if len(args) == 0 || args[len(args)-1] != "]" {
+			u.Fatal(fmt.Errorf("missing ]"), 2)
+		}
+		args = args[:len(args)-1]
+	}
+
+	ok, err := parse(args)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
-		os.Exit(2)
+		u.Fatal(err, 2)
 	}
 	if !ok {
-		os.Exit(1)
+		u.Exit(1)
+	}
+}
+
+func isUnary(arg string) bool {
+	if len(arg) != 2 || arg[0] != '-' {
+		return false
 	}
+	_, ok := flagMap[arg[1]]
+	return ok
+}
+
+// isBinary reports whether arg is a binary primary, including the XSI
+// -a and -o operators
+func isBinary(arg string) bool {
+	_, ok := infixMap[arg]
+	return ok || arg == "-a" || arg == "-o"
+}
+
+func unary(op, arg string) (bool, error) {
+	return flagMap[op[1]](arg)
+}
+
+func binary(a, op, b string) (bool, error) {
+	switch op {
+	case "-a":
+		return a != "" && b != "", nil
+	case "-o":
+		return a != "" || b != "", nil
+	}
+	return infixMap[op](a, b)
 }
 
 // Parse returns true if the expression is true, false if not true.
-// err is non-nil if it couldn't parse
+// err is non-nil if it couldn't parse.
+//
+// Up to four arguments are disambiguated by the rules in POSIX, which look
+// at how many arguments there are before deciding what each one means.
+// Longer expressions use the XSI grammar.
 func parse(args []string) (bool, error) {
-	if len(args) == 0 {
+	switch len(args) {
+	case 0:
 		return false, nil
-	}
-	if len(args) == 1 {
+	case 1:
 		return args[0] != "", nil
+	case 2:
+		if args[0] == "!" {
+			ok, err := parse(args[1:])
+			return !ok, err
+		}
+		if isUnary(args[0]) {
+			return unary(args[0], args[1])
+		}
+		return false, fmt.Errorf("%s: unary operator expected", args[0])
+	case 3:
+		if isBinary(args[1]) {
+			return binary(args[0], args[1], args[2])
+		}
+		if args[0] == "!" {
+			ok, err := parse(args[1:])
+			return !ok, err
+		}
+		if args[0] == "(" && args[2] == ")" {
+			return parse(args[1:2])
+		}
+		return false, fmt.Errorf("%s: binary operator expected", args[1])
+	case 4:
+		if args[0] == "!" {
+			ok, err := parse(args[1:])
+			return !ok, err
+		}
+		if args[0] == "(" && args[3] == ")" {
+			return parse(args[1:3])
+		}
 	}
 
-	arg := args[0]
-	if arg == "!" {
-		ok, err := parse(args[1:])
-		return !ok, err
+	p := &exprParser{args: args}
+	ok, err := p.or()
+	if err == nil && p.pos < len(p.args) {
+		err = fmt.Errorf("%s: unexpected argument", p.args[p.pos])
 	}
-	if arg == "" {
-		return false, nil
+	return ok, err
+}
+
+// exprParser evaluates the XSI expression grammar, in which "!" binds
+// tighter than "-a", which binds tighter than "-o".
+type exprParser struct {
+	args []string
+	pos  int
+}
+
+func (p *exprParser) peek(offset int) (string, bool) {
+	if p.pos+offset >= len(p.args) {
+		return "", false
 	}
-	if len(arg) == 2 && arg[0] == '-' {
-		if f, ok := flagMap[arg[1]]; ok {
-			return f(args[1])
+	return p.args[p.pos+offset], true
+}
+
+func (p *exprParser) or() (bool, error) {
+	ok, err := p.and()
+	for err == nil {
+		if arg, _ := p.peek(0); arg != "-o" {
+			break
+		}
+		p.pos++
+		var right bool
+		right, err = p.and()
+		ok = ok || right
+	}
+	return ok, err
+}
+
+func (p *exprParser) and() (bool, error) {
+	ok, err := p.not()
+	for err == nil {
+		if arg, _ := p.peek(0); arg != "-a" {
+			break
 		}
+		p.pos++
+		var right bool
+		right, err = p.not()
+		ok = ok && right
 	}
+	return ok, err
+}
 
-	if len(args) == 3 {
-		if f, ok := infixMap[args[1]]; ok {
-			return f(args[0], args[2])
+func (p *exprParser) not() (bool, error) {
+	arg, more := p.peek(0)
+	if arg == "!" {
+		// A lone "!" is just a non-empty string
+		if _, ok := p.peek(1); ok {
+			p.pos++
+			ok, err := p.not()
+			return !ok, err
 		}
 	}
+	if !more {
+		return false, fmt.Errorf("argument expected")
+	}
+	return p.primary()
+}
+
+func (p *exprParser) primary() (bool, error) {
+	arg, _ := p.peek(0)
+	op, hasOp := p.peek(1)
+	_, hasOperand := p.peek(2)
 
-	return false, fmt.Errorf("idk what to do about this")
+	if hasOp && hasOperand && isBinary(op) && op != "-a" && op != "-o" {
+		b, _ := p.peek(2)
+		p.pos += 3
+		return binary(arg, op, b)
+	}
+	if arg == "(" && hasOp {
+		p.pos++
+		ok, err := p.or()
+		if err != nil {
+			return ok, err
+		}
+		if closing, _ := p.peek(0); closing != ")" {
+			return false, fmt.Errorf("')' expected")
+		}
+		p.pos++
+		return ok, nil
+	}
+	if isUnary(arg) && hasOp {
+		p.pos += 2
+		return unary(arg, op)
+	}
+	p.pos++
+	return arg != "", nil
 }
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var exprTests = []struct {
+	args string
+	want bool
+}{
+	{"", false},
+	{"x", true},
+	{"-n", true},
+	{"!", true},
+	{"! x", false},
+	{"-z x", false},
+	{"-n x", true},
+	{"! -z", false},
+	{"x = x", true},
+	{"x != x", false},
+	{"! = x", false},
+	{"-n = -n", true},
+	{"( x )", true},
+	{"! -z x", true},
+	{"x -a y", true},
+	{"x -o -z", true},
+	{"! x = x", false},
+	{"( -z x )", false},
+	{"3 -lt 10", true},
+	{"-3 -gt -10", true},
+	{"x -a -z x -o y", true},
+	{"x -a ( -z x -o -z y )", false},
+	{"! x = y -a 1 -eq 1", true},
+	{"-n x -o ! -n x -a -z x", true},
+	{"( ( x = x ) )", true},
+	{"a < b", true},
+	{"B < a", true},
+	{"ab > a", true},
+	{"a > a", false},
+}
+
+func TestParse(t *testing.T) {
+	for _, test := range exprTests {
+		args := strings.Fields(test.args)
+		got, err := parse(args)
+		if err != nil {
+			t.Errorf("test %s: %s", test.args, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("test %s: got %t, wanted %t", test.args, got, test.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	bad := []string{"x y", "1 -eq a", "a b c", "( x", "x -a", "x y z w v"}
+	for _, args := range bad {
+		if _, err := parse(strings.Fields(args)); err == nil {
+			t.Errorf("test %s: expected an error", args)
+		}
+	}
+}
+
+func TestFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "file")
+	if err = ioutil.WriteFile(file, []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link")
+	if err = os.Symlink(file, link); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing")
+
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"-f", file}, true},
+		{[]string{"-d", dir}, true},
+		{[]string{"-e", missing}, false},
+		{[]string{"-h", link}, true},
+		{[]string{"-s", file}, true},
+		{[]string{"-r", file}, true},
+		{[]string{"-w", file}, true},
+		{[]string{"-r", missing}, false},
+		{[]string{"-x", dir}, true},
+		{[]string{file, "-ef", link}, true},
+		{[]string{file, "-nt", missing}, true},
+		{[]string{missing, "-ot", file}, true},
+	}
+	for _, test := range tests {
+		got, err := parse(test.args)
+		if err != nil {
+			t.Errorf("test %v: %s", test.args, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("test %v: got %t, wanted %t", test.args, got, test.want)
+		}
+	}
+}
@@ -4,6 +4,10 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/fwip/posix-utils/pkg/locale"
 )
 
 type prefixTest func(in string) (bool, error)
@@ -34,9 +38,14 @@ func noerr(test func(in string) bool) prefixTest {
 	}
 }
 
-func nyi(in string) (bool, error) {
-	return false, fmt.Errorf("NYI")
+// access checks permissions the way the kernel would for this process, so
+// that ACLs, read-only mounts and the superuser are all accounted for.
+func access(mode uint32) prefixTest {
+	return func(in string) (bool, error) {
+		return syscall.Access(in, mode) == nil, nil
+	}
 }
+
 func isLink(in string) (bool, error) {
 	i, err := os.Lstat(in)
 	if err != nil {
@@ -45,36 +54,65 @@ func isLink(in string) (bool, error) {
 	return i.Mode()&os.ModeSymlink == os.ModeSymlink, nil
 }
 
+func isTerminal(in string) (bool, error) {
+	fd, err := parseInt(in)
+	if err != nil {
+		return false, err
+	}
+	if fd < 0 || fd > int64(^uint32(0)>>1) {
+		return false, nil
+	}
+	return isatty(int(fd)), nil
+}
+
+// Access modes for access(2)
+const (
+	rOK = 0x4
+	wOK = 0x2
+	xOK = 0x1
+)
+
 var flagMap = map[byte]prefixTest{
-	'a': modeIs(os.ModeDevice),
+	'a': info(func(_ os.FileInfo) bool { return true }), // Historical synonym for -e
+	'b': info(func(i os.FileInfo) bool { return i.Mode()&os.ModeDevice != 0 && i.Mode()&os.ModeCharDevice == 0 }),
 	'c': modeIs(os.ModeCharDevice),
 	'd': info(func(i os.FileInfo) bool { return i.Mode().IsDir() }),
 	'e': info(func(_ os.FileInfo) bool { return true }),
 	'f': info(func(i os.FileInfo) bool { return i.Mode().IsRegular() }),
 	'g': modeIs(os.ModeSetgid),
 	'h': isLink,
+	'k': modeIs(os.ModeSticky),
 	'L': isLink,
 	'n': noerr(func(in string) bool { return in != "" }),
 	'p': modeIs(os.ModeNamedPipe),
-	'r': nyi,
+	'r': access(rOK),
 	'S': modeIs(os.ModeSocket),
 	's': info(func(i os.FileInfo) bool { return i.Size() > 0 }),
-	't': nyi, // ????????
+	't': isTerminal,
 	'u': modeIs(os.ModeSetuid),
-	'w': nyi,
-	'x': nyi,
+	'w': access(wOK),
+	'x': access(xOK),
 	'z': noerr(func(in string) bool { return in == "" }),
 }
 
 type infixTest func(a, b string) (bool, error)
 
-func numcmp(cmp func(a, b int) bool) infixTest {
+// parseInt parses an integer operand, which may have leading and trailing blanks
+func parseInt(s string) (int64, error) {
+	n, err := strconv.ParseInt(strings.Trim(s, " \t"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: integer expression expected", s)
+	}
+	return n, nil
+}
+
+func numcmp(cmp func(a, b int64) bool) infixTest {
 	return func(a, b string) (bool, error) {
-		an, err := strconv.Atoi(a)
+		an, err := parseInt(a)
 		if err != nil {
 			return false, err
 		}
-		bn, err := strconv.Atoi(b)
+		bn, err := parseInt(b)
 		if err != nil {
 			return false, err
 		}
@@ -82,13 +120,51 @@ func numcmp(cmp func(a, b int) bool) infixTest {
 	}
 }
 
+// newer reports whether file a was modified more recently than b. A file
+// that exists is newer than one that doesn't.
+func newer(a, b string) (bool, error) {
+	ai, err := os.Stat(a)
+	if err != nil {
+		return false, nil
+	}
+	bi, err := os.Stat(b)
+	if err != nil {
+		return true, nil
+	}
+	return ai.ModTime().After(bi.ModTime()), nil
+}
+
+func sameFile(a, b string) (bool, error) {
+	ai, err := os.Stat(a)
+	if err != nil {
+		return false, nil
+	}
+	bi, err := os.Stat(b)
+	if err != nil {
+		return false, nil
+	}
+	return os.SameFile(ai, bi), nil
+}
+
+// collates compares strings in the collation order of the current locale
+func collates(cmp func(int) bool) infixTest {
+	return func(a, b string) (bool, error) {
+		return cmp(locale.Current().Collate.Collator().Compare(a, b)), nil
+	}
+}
+
 var infixMap = map[string]infixTest{
 	"=":   func(a, b string) (bool, error) { return a == b, nil },
 	"!=":  func(a, b string) (bool, error) { return a != b, nil },
-	"-eq": numcmp(func(a, b int) bool { return a == b }),
-	"-ne": numcmp(func(a, b int) bool { return a != b }),
-	"-gt": numcmp(func(a, b int) bool { return a > b }),
-	"-ge": numcmp(func(a, b int) bool { return a >= b }),
-	"-lt": numcmp(func(a, b int) bool { return a < b }),
-	"-le": numcmp(func(a, b int) bool { return a <= b }),
+	"-eq": numcmp(func(a, b int64) bool { return a == b }),
+	"-ne": numcmp(func(a, b int64) bool { return a != b }),
+	"-gt": numcmp(func(a, b int64) bool { return a > b }),
+	"-ge": numcmp(func(a, b int64) bool { return a >= b }),
+	"-lt": numcmp(func(a, b int64) bool { return a < b }),
+	"-le": numcmp(func(a, b int64) bool { return a <= b }),
+	"<":   collates(func(c int) bool { return c < 0 }),
+	">":   collates(func(c int) bool { return c > 0 }),
+	"-nt": newer,
+	"-ot": func(a, b string) (bool, error) { return newer(b, a) },
+	"-ef": sameFile,
 }
@@ -5,16 +5,64 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
+	"github.com/fwip/posix-utils/pkg/flag"
 	"github.com/fwip/posix-utils/pkg/tsort"
+	"github.com/fwip/posix-utils/pkg/util"
 )
 
-func die(msg string, args ...interface{}) {
-	fmt.Fprintf(os.Stderr, "ahh jeez, "+msg+"\n", args)
-	os.Exit(1)
+// outputMode selects what is written about the graph
+type outputMode uint8
+
+const (
+	order      outputMode = iota // The POSIX behaviour: one node per line
+	levels                       // -l: nodes that may run in parallel share a line
+	components                   // -s: strongly connected components, one per line
+	dot                          // -d: the graph in Graphviz DOT format
+)
+
+type settings struct {
+	mode     outputMode
+	filename string
+}
+
+// modeFlag is one of -d, -l and -s, which select the output mode. The last
+// one given wins.
+type modeFlag struct {
+	dest *outputMode
+	mode outputMode
+}
+
+func (m modeFlag) Set(string) error {
+	*m.dest = m.mode
+	return nil
+}
+
+func (m modeFlag) String() string { return "" }
+
+func (m modeFlag) IsBoolFlag() bool { return true }
+
+func parseSettings(args []string) (settings, error) {
+	s := settings{filename: "-"}
+	p := flag.Parser{Input: args}
+	p.Var(modeFlag{&s.mode, dot}, 'd', "write the graph in DOT format")
+	p.Var(modeFlag{&s.mode, levels}, 'l', "write nodes that may be run in parallel on one line")
+	p.Var(modeFlag{&s.mode, components}, 's', "write the strongly connected components")
+	operands, err := p.Parse()
+	if err != nil {
+		return s, err
+	}
+	if len(operands) > 1 {
+		return s, fmt.Errorf("extra operand: %s", operands[1])
+	}
+	if len(operands) == 1 {
+		s.filename = operands[0]
+	}
+	return s, nil
 }
 
-func run(input io.Reader, output io.Writer) {
+func read(input io.Reader) (tsort.Sorter, error) {
 	var sorter tsort.Sorter
 	scanner := bufio.NewScanner(input)
 	scanner.Split(bufio.ScanWords)
@@ -23,7 +71,7 @@ func run(input io.Reader, output io.Writer) {
 		w1 := scanner.Text()
 
 		if !scanner.Scan() {
-			die("unmatched word! %s", w1)
+			return sorter, fmt.Errorf("odd number of tokens: %s", w1)
 		}
 		w2 := scanner.Text()
 		if w1 == w2 {
@@ -32,37 +80,77 @@ func run(input io.Reader, output io.Writer) {
 			sorter.Add([]string{w1, w2})
 		}
 	}
-	if scanner.Err() != nil {
-		die("scan error! %s", scanner.Err())
+	return sorter, scanner.Err()
+}
+
+// reportCycles writes each loop as a diagnostic, as the traditional tsort
+// does
+func reportCycles(u *util.Utility, err error) {
+	cerr, ok := err.(*tsort.CycleError)
+	if !ok {
+		u.Warn(err)
+		return
+	}
+	for _, cycle := range cerr.Cycles {
+		u.Warnf("input contains a loop:")
+		for _, item := range cycle {
+			u.Warnf("%s", item)
+		}
 	}
+}
 
-	out, err := sorter.Order()
+// run writes the sorted input. A cycle is reported, and sets the exit
+// status, but what can be sorted is still written.
+func run(u *util.Utility, s settings) {
+	input, err := u.Open(s.filename)
 	if err != nil {
-		die("sort error! %s", err)
+		u.Fatal(err, 1)
+		return
 	}
-	for _, item := range out {
-		fmt.Fprintln(output, item)
+	sorter, err := read(input)
+	input.Close()
+	if err != nil {
+		u.Fatal(err, 1)
+		return
 	}
+	w := bufio.NewWriter(u.Stdout)
 
-	if closer, ok := output.(io.Closer); ok {
-		closer.Close()
+	switch s.mode {
+	case order:
+		out, err := sorter.Order()
+		if err != nil {
+			reportCycles(u, err)
+		}
+		for _, item := range out {
+			fmt.Fprintln(w, item)
+		}
+	case levels:
+		out, err := sorter.Levels()
+		if err != nil {
+			reportCycles(u, err)
+		}
+		for _, level := range out {
+			fmt.Fprintln(w, strings.Join(level, " "))
+		}
+	case components:
+		for _, c := range sorter.Components() {
+			fmt.Fprintln(w, strings.Join(c, " "))
+		}
+	case dot:
+		sorter.WriteDOT(w)
 	}
 
+	if err := w.Flush(); err != nil {
+		u.Fatal(err, 1)
+	}
 }
 
 func main() {
-	var input io.Reader = os.Stdin
-	if len(os.Args) > 1 {
-		fname := os.Args[1]
-		if fname != "-" {
-			f, err := os.Open(fname)
-			if err != nil {
-				die("can't open %s", fname)
-			}
-			defer f.Close()
-			input = f
-		}
+	u := util.New("tsort", "usage: tsort [-d | -l | -s] [file]")
+	s, err := parseSettings(os.Args[1:])
+	if err != nil {
+		u.UsageError(err, util.UsageStatus)
 	}
-
-	run(input, os.Stdout)
+	run(u, s)
+	u.Exit(0)
 }
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fwip/posix-utils/pkg/util"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		mode   outputMode
+		input  string
+		stdout string
+		stderr string
+		status int
+	}{
+		{order, "a b b c", "a\nb\nc\n", "", 0},
+		{levels, "a b a c", "a\nb c\n", "", 0},
+		{order, "a b b a", "a\nb\n", "tsort: input contains a loop:\ntsort: a\ntsort: b\n", 1},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		u := &util.Utility{Name: "tsort", Stdin: strings.NewReader(tt.input), Stdout: &stdout, Stderr: &stderr}
+		run(u, settings{tt.mode, "-"})
+		if stdout.String() != tt.stdout || stderr.String() != tt.stderr || u.Status() != tt.status {
+			t.Errorf("%q: got %q, %q, status %d", tt.input, stdout.String(), stderr.String(), u.Status())
+		}
+	}
+}
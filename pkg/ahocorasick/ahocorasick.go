@@ -0,0 +1,96 @@
+// Package ahocorasick finds any of a set of fixed strings in text, in a
+// single pass, with the Aho-Corasick algorithm
+package ahocorasick
+
+// Matcher is an automaton built from a set of patterns. It works on bytes,
+// so UTF-8 patterns match UTF-8 text as they should.
+type Matcher struct {
+	states []state
+}
+
+// state is a node of the trie of patterns
+type state struct {
+	next  map[byte]int // The trie's edges
+	fail  int          // The state for the longest proper suffix in the trie
+	match bool         // Whether a pattern ends here, or at a suffix of here
+}
+
+// New builds a Matcher for patterns. An empty pattern matches anywhere.
+func New(patterns []string) *Matcher {
+	m := &Matcher{states: []state{{next: make(map[byte]int)}}}
+	for _, p := range patterns {
+		m.add(p)
+	}
+	m.link()
+	return m
+}
+
+// add inserts a pattern into the trie
+func (m *Matcher) add(pattern string) {
+	s := 0
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		t, ok := m.states[s].next[c]
+		if !ok {
+			t = len(m.states)
+			m.states = append(m.states, state{next: make(map[byte]int)})
+			m.states[s].next[c] = t
+		}
+		s = t
+	}
+	m.states[s].match = true
+}
+
+// link sets the failure links, breadth first, so that each state's link
+// is set before those of its children
+func (m *Matcher) link() {
+	queue := []int{}
+	for _, t := range m.states[0].next {
+		queue = append(queue, t)
+	}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		for c, t := range m.states[s].next {
+			f := m.states[s].fail
+			for f != 0 {
+				if _, ok := m.states[f].next[c]; ok {
+					break
+				}
+				f = m.states[f].fail
+			}
+			if g, ok := m.states[f].next[c]; ok && g != t {
+				m.states[t].fail = g
+			}
+			if m.states[m.states[t].fail].match {
+				m.states[t].match = true
+			}
+			queue = append(queue, t)
+		}
+	}
+}
+
+// Contains reports whether s contains any of the patterns
+func (m *Matcher) Contains(s string) bool {
+	if m.states[0].match {
+		return true
+	}
+	state := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		for {
+			if t, ok := m.states[state].next[c]; ok {
+				state = t
+				break
+			}
+			if state == 0 {
+				break
+			}
+			state = m.states[state].fail
+		}
+		if m.states[state].match {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package ahocorasick
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContains(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		s        string
+		want     bool
+	}{
+		{[]string{"he", "she", "his", "hers"}, "ushers", true},
+		{[]string{"he", "she", "his", "hers"}, "ahishe", true},
+		{[]string{"he", "she", "his", "hers"}, "shih", false},
+		{[]string{"abcd", "bc"}, "abce", true},
+		{[]string{"abcd", "cde"}, "abcde", true},
+		{[]string{"aab"}, "aaab", true},
+		{[]string{"aab"}, "abab", false},
+		{[]string{"é"}, "café", true},
+		{[]string{}, "anything", false},
+		{[]string{""}, "", true},
+		{[]string{"x", ""}, "abc", true},
+	}
+	for _, tt := range tests {
+		if got := New(tt.patterns).Contains(tt.s); got != tt.want {
+			t.Errorf("%q in %q: got %v, expected %v", tt.patterns, tt.s, got, tt.want)
+		}
+	}
+}
+
+// TestContainsAgrees checks the automaton against strings.Contains on
+// patterns that share many prefixes and suffixes
+func TestContainsAgrees(t *testing.T) {
+	patterns := []string{"abab", "bab", "baa", "aaab", "bbb"}
+	m := New(patterns)
+	for n := 0; n < 1<<10; n++ {
+		var b strings.Builder
+		for i := 0; i < 10; i++ {
+			b.WriteByte("ab"[n>>uint(i)&1])
+		}
+		s := b.String()
+		want := false
+		for _, p := range patterns {
+			want = want || strings.Contains(s, p)
+		}
+		if got := m.Contains(s); got != want {
+			t.Errorf("%q: got %v, expected %v", s, got, want)
+		}
+	}
+}
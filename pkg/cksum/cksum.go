@@ -0,0 +1,80 @@
+// Package cksum implements the CRC used by the POSIX cksum utility.
+//
+// The checksum is a 32-bit CRC of the input followed by the input's length,
+// written least significant byte first with no trailing zero bytes. The
+// result is complemented.
+package cksum
+
+import "hash"
+
+// Size of a cksum checksum in bytes
+const Size = 4
+
+type digest struct {
+	crc uint32
+	n   uint64
+}
+
+// New returns a hash.Hash32 computing the POSIX cksum CRC
+func New() hash.Hash32 {
+	return &digest{}
+}
+
+func (d *digest) Size() int { return Size }
+
+func (d *digest) BlockSize() int { return 8 }
+
+func (d *digest) Reset() {
+	d.crc = 0
+	d.n = 0
+}
+
+func (d *digest) Write(p []byte) (int, error) {
+	d.crc = Update(d.crc, p)
+	d.n += uint64(len(p))
+	return len(p), nil
+}
+
+func (d *digest) Sum32() uint32 {
+	return Finish(d.crc, d.n)
+}
+
+func (d *digest) Sum(in []byte) []byte {
+	s := d.Sum32()
+	return append(in, byte(s>>24), byte(s>>16), byte(s>>8), byte(s))
+}
+
+// Update returns the result of adding the bytes in p to the crc. The crc is
+// the raw running value, before Finish is applied.
+func Update(crc uint32, p []byte) uint32 {
+	for len(p) >= 8 {
+		crc ^= uint32(p[0])<<24 | uint32(p[1])<<16 | uint32(p[2])<<8 | uint32(p[3])
+		crc = slicing[7][crc>>24] ^
+			slicing[6][byte(crc>>16)] ^
+			slicing[5][byte(crc>>8)] ^
+			slicing[4][byte(crc)] ^
+			slicing[3][p[4]] ^
+			slicing[2][p[5]] ^
+			slicing[1][p[6]] ^
+			slicing[0][p[7]]
+		p = p[8:]
+	}
+	for _, c := range p {
+		crc = (crc << 8) ^ crctab[byte(crc>>24)^c]
+	}
+	return crc
+}
+
+// Finish extends crc with the length n and returns the final checksum
+func Finish(crc uint32, n uint64) uint32 {
+	for n != 0 {
+		crc = (crc << 8) ^ crctab[byte(crc>>24)^byte(n)]
+		n >>= 8
+	}
+	return ^crc
+}
+
+// Checksum returns the cksum CRC of data
+func Checksum(data []byte) uint32 {
+	return Finish(Update(0, data), uint64(len(data)))
+}
@@ -0,0 +1,101 @@
+package cksum
+
+import (
+	"bytes"
+	"hash"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+var checksumTests = []struct {
+	in   string
+	want uint32
+}{
+	{"", 4294967295},
+	{"hello\n", 3015617425},
+	{"The quick brown fox jumps over the lazy dog", 2074844392},
+	{strings.Repeat("\x00", 100000), 1260869142},
+}
+
+func TestChecksum(t *testing.T) {
+	for _, test := range checksumTests {
+		if got := Checksum([]byte(test.in)); got != test.want {
+			t.Errorf("Checksum(%.20q): got %d, wanted %d", test.in, got, test.want)
+		}
+	}
+}
+
+// bytewise is the straightforward table-driven CRC, without slicing
+func bytewise(data []byte) uint32 {
+	var s uint32
+	for _, c := range data {
+		s = (s << 8) ^ crctab[byte(s>>24)^c]
+	}
+	return Finish(s, uint64(len(data)))
+}
+
+func TestSlicingMatchesBytewise(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for size := 0; size < 300; size++ {
+		data := make([]byte, size)
+		r.Read(data)
+		if got, want := Checksum(data), bytewise(data); got != want {
+			t.Errorf("size %d: got %d, wanted %d", size, got, want)
+		}
+	}
+}
+
+func TestStreaming(t *testing.T) {
+	data := make([]byte, 1<<20+13)
+	rand.New(rand.NewSource(2)).Read(data)
+	want := Checksum(data)
+
+	h := New()
+	// Odd-sized writes exercise the unaligned tail handling
+	if _, err := io.CopyBuffer(h, bytes.NewReader(data), make([]byte, 4093)); err != nil {
+		t.Fatal(err)
+	}
+	if got := h.Sum32(); got != want {
+		t.Errorf("streamed: got %d, wanted %d", got, want)
+	}
+
+	sum := h.Sum(nil)
+	if len(sum) != Size || uint32(sum[0])<<24|uint32(sum[1])<<16|uint32(sum[2])<<8|uint32(sum[3]) != want {
+		t.Errorf("Sum: got %x, wanted %08x", sum, want)
+	}
+
+	h.Reset()
+	if got := h.Sum32(); got != 4294967295 {
+		t.Errorf("after Reset: got %d", got)
+	}
+}
+
+func BenchmarkChecksum(b *testing.B) {
+	data := make([]byte, 1<<20)
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		Checksum(data)
+	}
+}
+
+func TestHistoricalSums(t *testing.T) {
+	tests := []struct {
+		name string
+		h    hash.Hash32
+		in   string
+		want uint32
+	}{
+		{"bsd", NewBSD(), "", 0},
+		{"bsd", NewBSD(), "hello\n", 36979},
+		{"sysv", NewSysV(), "", 0},
+		{"sysv", NewSysV(), "hello\n", 542},
+	}
+	for _, test := range tests {
+		test.h.Write([]byte(test.in))
+		if got := test.h.Sum32(); got != test.want {
+			t.Errorf("%s(%q): got %d, wanted %d", test.name, test.in, got, test.want)
+		}
+	}
+}
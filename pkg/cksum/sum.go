@@ -0,0 +1,74 @@
+package cksum
+
+import "hash"
+
+// The historical sum utilities produced 16-bit checksums. They're kept here
+// for compatibility with old release manifests.
+
+type bsdDigest struct {
+	sum uint16
+}
+
+// NewBSD returns a hash.Hash32 computing the historical BSD sum checksum,
+// a 16-bit rotating checksum.
+func NewBSD() hash.Hash32 {
+	return &bsdDigest{}
+}
+
+func (d *bsdDigest) Size() int { return Size }
+
+func (d *bsdDigest) BlockSize() int { return 1 }
+
+func (d *bsdDigest) Reset() { d.sum = 0 }
+
+func (d *bsdDigest) Write(p []byte) (int, error) {
+	s := d.sum
+	for _, c := range p {
+		s = (s >> 1) | (s << 15)
+		s += uint16(c)
+	}
+	d.sum = s
+	return len(p), nil
+}
+
+func (d *bsdDigest) Sum32() uint32 { return uint32(d.sum) }
+
+func (d *bsdDigest) Sum(in []byte) []byte {
+	s := d.Sum32()
+	return append(in, byte(s>>24), byte(s>>16), byte(s>>8), byte(s))
+}
+
+type sysvDigest struct {
+	sum uint32
+}
+
+// NewSysV returns a hash.Hash32 computing the historical System V sum
+// checksum, the sum of all bytes folded into 16 bits.
+func NewSysV() hash.Hash32 {
+	return &sysvDigest{}
+}
+
+func (d *sysvDigest) Size() int { return Size }
+
+func (d *sysvDigest) BlockSize() int { return 1 }
+
+func (d *sysvDigest) Reset() { d.sum = 0 }
+
+func (d *sysvDigest) Write(p []byte) (int, error) {
+	s := d.sum
+	for _, c := range p {
+		s += uint32(c)
+	}
+	d.sum = s
+	return len(p), nil
+}
+
+func (d *sysvDigest) Sum32() uint32 {
+	r := (d.sum & 0xffff) + (d.sum >> 16)
+	return (r & 0xffff) + (r >> 16)
+}
+
+func (d *sysvDigest) Sum(in []byte) []byte {
+	s := d.Sum32()
+	return append(in, byte(s>>24), byte(s>>16), byte(s>>8), byte(s))
+}
@@ -1,6 +1,6 @@
-package main
+package cksum
 
-// based on the polynomial used for CRC error checking in the ISO/IEC 8802-3:1996 standard (Ethernet).
+// The CRC is based on the polynomial used for CRC error checking in the ISO/IEC 8802-3:1996 standard (Ethernet).
 // G(x)=x^32+x^26+x^23+x^22+x^16+x^12+x^11+x^10+x^8+x^7+x^5+x^4+x^2+x+1
 
 var crctab = [...]uint32{
@@ -58,22 +58,16 @@ var crctab = [...]uint32{
 	0xa2f33668, 0xbcb4666d, 0xb8757bda, 0xb5365d03, 0xb1f740b4,
 }
 
-func memcrc(bytes []byte) uint32 {
-	var s uint32
-	var c byte
-	n := uint64(len(bytes))
+// slicing holds the tables for slicing-by-8. slicing[0] is crctab, and
+// slicing[k][i] is the CRC of the byte i followed by k zero bytes.
+var slicing [8][256]uint32
 
-	// Checksum bytes
-	for _, c := range bytes {
-		s = (s << 8) ^ crctab[byte(s>>24)^c]
+func init() {
+	slicing[0] = crctab
+	for i := 0; i < 256; i++ {
+		for k := 1; k < 8; k++ {
+			prev := slicing[k-1][i]
+			slicing[k][i] = (prev << 8) ^ crctab[byte(prev>>24)]
+		}
 	}
-
-	// Extend with the length of the string
-	for n != 0 {
-		c = byte(n) & 0377
-		n >>= 8
-		s = (s << 8) ^ crctab[byte(s>>24)^c]
-	}
-
-	return uint32(^s)
 }
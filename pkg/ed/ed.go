@@ -1,5 +1,7 @@
 package ed
 
+//go:generate peg ed.peg
+
 import (
 	"bufio"
 	"bytes"
@@ -11,6 +13,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/fwip/posix-utils/pkg/regexes"
 	"github.com/fwip/posix-utils/pkg/txt"
 )
 
@@ -27,6 +30,24 @@ type Itor struct {
 	filename    string
 	currentLine int
 	modified    bool
+	lastRegexp  *regexes.Regexp // The last substitution's, for an empty pattern
+	prompt      string          // Written before each command when prompting
+	prompting   bool
+}
+
+// defaultPrompt is the prompt the P command turns on, when -p gave none
+const defaultPrompt = "*"
+
+// SetPrompt turns on prompting for commands, with prompt
+func (ed *Itor) SetPrompt(prompt string) {
+	ed.prompt = prompt
+	ed.prompting = true
+}
+
+func (ed *Itor) writePrompt(w io.Writer) {
+	if ed.prompting {
+		w.Write([]byte(ed.prompt))
+	}
 }
 
 // NewEditor creates a new editor that reads and writes to the supplied writer
@@ -107,6 +128,16 @@ func (ed *Itor) Print(start, end int) string {
 	return strings.Join(lines[start-1:end], "\n")
 }
 
+// list prints some lines unambiguously
+func (ed *Itor) list(start, end int) string {
+	lines := ed.getLines()
+	listed := make([]string, 0, end-start+1)
+	for _, line := range lines[start-1 : end] {
+		listed = append(listed, List(line, ListWidth))
+	}
+	return strings.Join(listed, "\n")
+}
+
 func (ed *Itor) number(start, end int) string {
 	lines := ed.getLines()
 	out := ""
@@ -244,7 +275,12 @@ func (ed *Itor) ProcessCommands(r io.Reader, w io.Writer) {
 		close(cmds)
 	}()
 
-	for cmd := range cmds {
+	for {
+		ed.writePrompt(w)
+		cmd, ok := <-cmds
+		if !ok {
+			break
+		}
 		fmt.Println("cmd", cmd)
 		// Special-case quit command for now
 		if cmd.typ == ctquit {
@@ -311,6 +347,16 @@ func (ed *Itor) processCommand(cmd Command) string {
 	case ctprint:
 		return ed.Print(ed.addrLine(cmd.start), ed.addrLine(cmd.end))
 
+	case ctlist:
+		return ed.list(ed.addrLine(cmd.start), ed.addrLine(cmd.end))
+
+	case ctsubstitute:
+		out, err := ed.substitute(ed.addrLine(cmd.start), ed.addrLine(cmd.end), cmd.text)
+		if err != nil {
+			return "?" + err.Error()
+		}
+		return out
+
 	case ctnumber:
 		return ed.number(ed.addrLine(cmd.start), ed.addrLine(cmd.end))
 	case ctnull:
@@ -350,8 +396,87 @@ func (ed *Itor) processCommand(cmd Command) string {
 	case ctlineNumber:
 		return strconv.Itoa(ed.addrLine(cmd.start))
 
+	case ctprompt:
+		if ed.prompt == "" {
+			ed.prompt = defaultPrompt
+		}
+		ed.prompting = !ed.prompting
+
 	default:
 		return "? (NYI)"
 	}
 	return ""
 }
+
+// substitute runs an s command, whose arguments are args, on the lines from
+// start to end. It returns what its p, l or n flag prints.
+func (ed *Itor) substitute(start, end int, args string) (string, error) {
+	pattern, replacement, flags, err := ReadSubstitution(args)
+	if err != nil {
+		return "", err
+	}
+	sub := &Substitution{Replacement: replacement}
+	print := ctnull
+	for i := 0; i < len(flags); i++ {
+		switch c := flags[i]; {
+		case c == 'g':
+			sub.Global = true
+		case c >= '0' && c <= '9':
+			n := 0
+			for ; i < len(flags) && flags[i] >= '0' && flags[i] <= '9'; i++ {
+				n = 10*n + int(flags[i]-'0')
+			}
+			i--
+			sub.Occurrence = n
+		case c == 'p':
+			print = ctprint
+		case c == 'l':
+			print = ctlist
+		case c == 'n':
+			print = ctnumber
+		default:
+			return "", fmt.Errorf("unknown flag %c", c)
+		}
+	}
+
+	if pattern == "" {
+		if ed.lastRegexp == nil {
+			return "", fmt.Errorf("no previous regular expression")
+		}
+		sub.Regexp = ed.lastRegexp
+	} else if sub.Regexp, err = regexes.Compile(pattern, 0); err != nil {
+		return "", err
+	}
+	ed.lastRegexp = sub.Regexp
+	if err := sub.Check(); err != nil {
+		return "", err
+	}
+
+	lines := ed.getLines()
+	last := 0
+	shift := 0 // Lines added by splitting lines so far
+	for i := start; i <= end; i++ {
+		replaced, ok := sub.Apply(lines[i-1])
+		if !ok {
+			continue
+		}
+		ed.Delete(i+shift, i+shift)
+		ed.insertBeforeLine(i+shift, replaced)
+		// A replacement may split the line
+		shift += strings.Count(replaced, "\n")
+		last = i + shift
+	}
+	if last == 0 {
+		return "", fmt.Errorf("no match")
+	}
+	ed.currentLine = last
+	switch print {
+	case ctprint:
+		return ed.Print(last, last), nil
+	case ctlist:
+		return ed.list(last, last), nil
+	case ctnumber:
+		return strings.TrimSuffix(ed.number(last, last), "\n"), nil
+	}
+	return "", nil
+}
@@ -1,14 +1,14 @@
 package ed
 
-//go:generate peg ed.peg
+// Code generated by peg ed.peg DO NOT EDIT.
 
 import (
 	"fmt"
 	"io"
-	"math"
 	"os"
 	"sort"
 	"strconv"
+	"strings"
 )
 
 const endSymbol rune = 1114112
@@ -27,6 +27,8 @@ const (
 	ruledestCmd
 	rulereadCmd
 	rulewriteCmd
+	rulesubstituteCmd
+	rulesubstArgs
 	ruleshellCmd
 	rulenullCmd
 	ruletext
@@ -104,6 +106,7 @@ const (
 	ruleAction44
 	ruleAction45
 	ruleAction46
+	ruleAction47
 )
 
 var rul3s = [...]string{
@@ -117,6 +120,8 @@ var rul3s = [...]string{
 	"destCmd",
 	"readCmd",
 	"writeCmd",
+	"substituteCmd",
+	"substArgs",
 	"shellCmd",
 	"nullCmd",
 	"text",
@@ -194,6 +199,7 @@ var rul3s = [...]string{
 	"Action44",
 	"Action45",
 	"Action46",
+	"Action47",
 }
 
 type token32 struct {
@@ -222,7 +228,7 @@ func (node *node32) print(w io.Writer, pretty bool, buffer string) {
 			if !pretty {
 				fmt.Fprintf(w, "%v %v\n", rule, quote)
 			} else {
-				fmt.Fprintf(w, "\x1B[34m%v\x1B[m %v\n", rule, quote)
+				fmt.Fprintf(w, "\x1B[36m%v\x1B[m %v\n", rule, quote)
 			}
 			if node.up != nil {
 				print(node.up, depth+1)
@@ -293,16 +299,12 @@ func (t *tokens32) PrettyPrintSyntaxTree(buffer string) {
 }
 
 func (t *tokens32) Add(rule pegRule, begin, end, index uint32) {
-	if tree := t.tree; int(index) >= len(tree) {
-		expanded := make([]token32, 2*len(tree))
-		copy(expanded, tree)
-		t.tree = expanded
-	}
-	t.tree[index] = token32{
-		pegRule: rule,
-		begin:   begin,
-		end:     end,
+	tree, i := t.tree, int(index)
+	if i >= len(tree) {
+		t.tree = append(tree, token32{pegRule: rule, begin: begin, end: end})
+		return
 	}
+	tree[i] = token32{pegRule: rule, begin: begin, end: end}
 }
 
 func (t *tokens32) Tokens() []token32 {
@@ -316,7 +318,7 @@ type Parser struct {
 
 	Buffer string
 	buffer []rune
-	rules  [87]func() bool
+	rules  [90]func() bool
 	parse  func(rule ...int) error
 	reset  func()
 	Pretty bool
@@ -403,6 +405,12 @@ func (p *Parser) WriteSyntaxTree(w io.Writer) {
 	p.tokens32.WriteSyntaxTree(w, p.Buffer)
 }
 
+func (p *Parser) SprintSyntaxTree() string {
+	var bldr strings.Builder
+	p.WriteSyntaxTree(&bldr)
+	return bldr.String()
+}
+
 func (p *Parser) Execute() {
 	buffer, _buffer, text, begin, end := p.Buffer, p.buffer, "", 0, 0
 	for _, token := range p.Tokens() {
@@ -443,91 +451,96 @@ func (p *Parser) Execute() {
 
 		case ruleAction7:
 
-			p.curCmd.typ = ctshell
+			p.curCmd.typ = ctsubstitute
 			p.curCmd.text = buffer[begin:end]
 
 		case ruleAction8:
+
+			p.curCmd.typ = ctshell
 			p.curCmd.text = buffer[begin:end]
-			fmt.Println("t", p.curCmd.text)
+
 		case ruleAction9:
-			p.curCmd.end = p.curCmd.start
+			p.curCmd.text = buffer[begin:end]
+			fmt.Println("t", p.curCmd.text)
 		case ruleAction10:
-			p.curCmd.start = aFirst
-		case ruleAction11:
 			p.curCmd.end = p.curCmd.start
+		case ruleAction11:
+			p.curCmd.start = aFirst
 		case ruleAction12:
-			p.curCmd.start = aCur
-		case ruleAction13:
 			p.curCmd.end = p.curCmd.start
+		case ruleAction13:
+			p.curCmd.start = aCur
 		case ruleAction14:
+			p.curCmd.end = p.curCmd.start
+		case ruleAction15:
 			p.curCmd.start = aFirst
 			p.curCmd.end = aLast
-		case ruleAction15:
+		case ruleAction16:
 			p.curCmd.start = aCur
 			p.curCmd.end = aLast
-		case ruleAction16:
-			p.curCmd.start.text = buffer[begin:end]
 		case ruleAction17:
+			p.curCmd.start.text = buffer[begin:end]
+		case ruleAction18:
 			p.curCmd.start = p.curAddr
 			p.curAddr = address{}
-		case ruleAction18:
+		case ruleAction19:
 			p.curCmd.end = p.curAddr
 			p.curAddr = address{}
-		case ruleAction19:
-			p.curAddr.text = buffer[begin:end]
 		case ruleAction20:
-			p.curAddr.typ = lCurrent
+			p.curAddr.text = buffer[begin:end]
 		case ruleAction21:
-			p.curAddr.typ = lLast
+			p.curAddr.typ = lCurrent
 		case ruleAction22:
-			p.curAddr.typ = lNum
+			p.curAddr.typ = lLast
 		case ruleAction23:
-			p.curAddr.typ = lMark
+			p.curAddr.typ = lNum
 		case ruleAction24:
-			p.curAddr.typ = lRegex
+			p.curAddr.typ = lMark
 		case ruleAction25:
-			p.curAddr.typ = lRegexReverse
+			p.curAddr.typ = lRegex
 		case ruleAction26:
-			p.curCmd.typ = cthelp
+			p.curAddr.typ = lRegexReverse
 		case ruleAction27:
-			p.curCmd.typ = cthelpMode
+			p.curCmd.typ = cthelp
 		case ruleAction28:
-			p.curCmd.typ = ctprompt
+			p.curCmd.typ = cthelpMode
 		case ruleAction29:
-			p.curCmd.typ = ctquit
+			p.curCmd.typ = ctprompt
 		case ruleAction30:
-			p.curCmd.typ = ctquitForce
+			p.curCmd.typ = ctquit
 		case ruleAction31:
-			p.curCmd.typ = ctundo
+			p.curCmd.typ = ctquitForce
 		case ruleAction32:
-			p.curCmd.params = []string{buffer[begin:end]}
+			p.curCmd.typ = ctundo
 		case ruleAction33:
-			p.curCmd.typ = ctedit
+			p.curCmd.params = []string{buffer[begin:end]}
 		case ruleAction34:
-			p.curCmd.typ = cteditForce
+			p.curCmd.typ = ctedit
 		case ruleAction35:
-			p.curCmd.typ = ctfilename
+			p.curCmd.typ = cteditForce
 		case ruleAction36:
-			p.curCmd.typ = ctlineNumber
+			p.curCmd.typ = ctfilename
 		case ruleAction37:
-			p.curCmd.typ = ctchange
+			p.curCmd.typ = ctlineNumber
 		case ruleAction38:
-			p.curCmd.typ = ctappend
+			p.curCmd.typ = ctchange
 		case ruleAction39:
-			p.curCmd.typ = ctinsert
+			p.curCmd.typ = ctappend
 		case ruleAction40:
-			p.curCmd.typ = ctdelete
+			p.curCmd.typ = ctinsert
 		case ruleAction41:
-			p.curCmd.typ = ctjoin
+			p.curCmd.typ = ctdelete
 		case ruleAction42:
-			p.curCmd.typ = ctlist
+			p.curCmd.typ = ctjoin
 		case ruleAction43:
-			p.curCmd.typ = ctnumber
+			p.curCmd.typ = ctlist
 		case ruleAction44:
-			p.curCmd.typ = ctprint
+			p.curCmd.typ = ctnumber
 		case ruleAction45:
-			p.curCmd.typ = ctmove
+			p.curCmd.typ = ctprint
 		case ruleAction46:
+			p.curCmd.typ = ctmove
+		case ruleAction47:
 			p.curCmd.typ = ctcopy
 
 		}
@@ -535,12 +548,31 @@ func (p *Parser) Execute() {
 	_, _, _, _, _ = buffer, _buffer, text, begin, end
 }
 
-func (p *Parser) Init() {
+func Pretty(pretty bool) func(*Parser) error {
+	return func(p *Parser) error {
+		p.Pretty = pretty
+		return nil
+	}
+}
+
+func Size(size int) func(*Parser) error {
+	return func(p *Parser) error {
+		p.tokens32 = tokens32{tree: make([]token32, 0, size)}
+		return nil
+	}
+}
+func (p *Parser) Init(options ...func(*Parser) error) error {
 	var (
 		max                  token32
 		position, tokenIndex uint32
 		buffer               []rune
 	)
+	for _, option := range options {
+		err := option(p)
+		if err != nil {
+			return err
+		}
+	}
 	p.reset = func() {
 		max = token32{}
 		position, tokenIndex = 0, 0
@@ -554,7 +586,7 @@ func (p *Parser) Init() {
 	p.reset()
 
 	_rules := p.rules
-	tree := tokens32{tree: make([]token32, math.MaxInt16)}
+	tree := p.tokens32
 	p.parse = func(rule ...int) error {
 		r := 1
 		if len(rule) > 0 {
@@ -678,7 +710,7 @@ func (p *Parser) Init() {
 			position, tokenIndex = position5, tokenIndex5
 			return false
 		},
-		/* 2 cmd <- <(bareCmd / paramCmd / rangeCmd / addrCmd / changeTextCmd / addTextCmd / markCmd / destCmd / readCmd / writeCmd / shellCmd / nullCmd)> */
+		/* 2 cmd <- <(bareCmd / paramCmd / rangeCmd / substituteCmd / addrCmd / changeTextCmd / addTextCmd / markCmd / destCmd / readCmd / writeCmd / shellCmd / nullCmd)> */
 		func() bool {
 			position11, tokenIndex11 := position, tokenIndex
 			{
@@ -703,53 +735,59 @@ func (p *Parser) Init() {
 					goto l13
 				l16:
 					position, tokenIndex = position13, tokenIndex13
-					if !_rules[ruleaddrCmd]() {
+					if !_rules[rulesubstituteCmd]() {
 						goto l17
 					}
 					goto l13
 				l17:
 					position, tokenIndex = position13, tokenIndex13
-					if !_rules[rulechangeTextCmd]() {
+					if !_rules[ruleaddrCmd]() {
 						goto l18
 					}
 					goto l13
 				l18:
 					position, tokenIndex = position13, tokenIndex13
-					if !_rules[ruleaddTextCmd]() {
+					if !_rules[rulechangeTextCmd]() {
 						goto l19
 					}
 					goto l13
 				l19:
 					position, tokenIndex = position13, tokenIndex13
-					if !_rules[rulemarkCmd]() {
+					if !_rules[ruleaddTextCmd]() {
 						goto l20
 					}
 					goto l13
 				l20:
 					position, tokenIndex = position13, tokenIndex13
-					if !_rules[ruledestCmd]() {
+					if !_rules[rulemarkCmd]() {
 						goto l21
 					}
 					goto l13
 				l21:
 					position, tokenIndex = position13, tokenIndex13
-					if !_rules[rulereadCmd]() {
+					if !_rules[ruledestCmd]() {
 						goto l22
 					}
 					goto l13
 				l22:
 					position, tokenIndex = position13, tokenIndex13
-					if !_rules[rulewriteCmd]() {
+					if !_rules[rulereadCmd]() {
 						goto l23
 					}
 					goto l13
 				l23:
 					position, tokenIndex = position13, tokenIndex13
-					if !_rules[ruleshellCmd]() {
+					if !_rules[rulewriteCmd]() {
 						goto l24
 					}
 					goto l13
 				l24:
+					position, tokenIndex = position13, tokenIndex13
+					if !_rules[ruleshellCmd]() {
+						goto l25
+					}
+					goto l13
+				l25:
 					position, tokenIndex = position13, tokenIndex13
 					if !_rules[rulenullCmd]() {
 						goto l11
@@ -765,1480 +803,1662 @@ func (p *Parser) Init() {
 		},
 		/* 3 changeTextCmd <- <(range? changeTextC newLine text)> */
 		func() bool {
-			position25, tokenIndex25 := position, tokenIndex
+			position26, tokenIndex26 := position, tokenIndex
 			{
-				position26 := position
+				position27 := position
 				{
-					position27, tokenIndex27 := position, tokenIndex
+					position28, tokenIndex28 := position, tokenIndex
 					if !_rules[rulerange]() {
-						goto l27
+						goto l28
 					}
-					goto l28
-				l27:
-					position, tokenIndex = position27, tokenIndex27
+					goto l29
+				l28:
+					position, tokenIndex = position28, tokenIndex28
 				}
-			l28:
+			l29:
 				if !_rules[rulechangeTextC]() {
-					goto l25
+					goto l26
 				}
 				if !_rules[rulenewLine]() {
-					goto l25
+					goto l26
 				}
 				if !_rules[ruletext]() {
-					goto l25
+					goto l26
 				}
-				add(rulechangeTextCmd, position26)
+				add(rulechangeTextCmd, position27)
 			}
 			return true
-		l25:
-			position, tokenIndex = position25, tokenIndex25
+		l26:
+			position, tokenIndex = position26, tokenIndex26
 			return false
 		},
 		/* 4 addTextCmd <- <(startAddr? addTextC newLine text)> */
 		func() bool {
-			position29, tokenIndex29 := position, tokenIndex
+			position30, tokenIndex30 := position, tokenIndex
 			{
-				position30 := position
+				position31 := position
 				{
-					position31, tokenIndex31 := position, tokenIndex
+					position32, tokenIndex32 := position, tokenIndex
 					if !_rules[rulestartAddr]() {
-						goto l31
+						goto l32
 					}
-					goto l32
-				l31:
-					position, tokenIndex = position31, tokenIndex31
+					goto l33
+				l32:
+					position, tokenIndex = position32, tokenIndex32
 				}
-			l32:
+			l33:
 				if !_rules[ruleaddTextC]() {
-					goto l29
+					goto l30
 				}
 				if !_rules[rulenewLine]() {
-					goto l29
+					goto l30
 				}
 				if !_rules[ruletext]() {
-					goto l29
+					goto l30
 				}
-				add(ruleaddTextCmd, position30)
+				add(ruleaddTextCmd, position31)
 			}
 			return true
-		l29:
-			position, tokenIndex = position29, tokenIndex29
+		l30:
+			position, tokenIndex = position30, tokenIndex30
 			return false
 		},
 		/* 5 markCmd <- <(startAddr? 'k' <[a-z]> Action2)> */
 		func() bool {
-			position33, tokenIndex33 := position, tokenIndex
+			position34, tokenIndex34 := position, tokenIndex
 			{
-				position34 := position
+				position35 := position
 				{
-					position35, tokenIndex35 := position, tokenIndex
+					position36, tokenIndex36 := position, tokenIndex
 					if !_rules[rulestartAddr]() {
-						goto l35
+						goto l36
 					}
-					goto l36
-				l35:
-					position, tokenIndex = position35, tokenIndex35
+					goto l37
+				l36:
+					position, tokenIndex = position36, tokenIndex36
 				}
-			l36:
+			l37:
 				if buffer[position] != rune('k') {
-					goto l33
+					goto l34
 				}
 				position++
 				{
-					position37 := position
+					position38 := position
 					if c := buffer[position]; c < rune('a') || c > rune('z') {
-						goto l33
+						goto l34
 					}
 					position++
-					add(rulePegText, position37)
+					add(rulePegText, position38)
 				}
 				if !_rules[ruleAction2]() {
-					goto l33
+					goto l34
 				}
-				add(rulemarkCmd, position34)
+				add(rulemarkCmd, position35)
 			}
 			return true
-		l33:
-			position, tokenIndex = position33, tokenIndex33
+		l34:
+			position, tokenIndex = position34, tokenIndex34
 			return false
 		},
 		/* 6 destCmd <- <(range? destC <addrO> Action3)> */
 		func() bool {
-			position38, tokenIndex38 := position, tokenIndex
+			position39, tokenIndex39 := position, tokenIndex
 			{
-				position39 := position
+				position40 := position
 				{
-					position40, tokenIndex40 := position, tokenIndex
+					position41, tokenIndex41 := position, tokenIndex
 					if !_rules[rulerange]() {
-						goto l40
+						goto l41
 					}
-					goto l41
-				l40:
-					position, tokenIndex = position40, tokenIndex40
+					goto l42
+				l41:
+					position, tokenIndex = position41, tokenIndex41
 				}
-			l41:
+			l42:
 				if !_rules[ruledestC]() {
-					goto l38
+					goto l39
 				}
 				{
-					position42 := position
+					position43 := position
 					if !_rules[ruleaddrO]() {
-						goto l38
+						goto l39
 					}
-					add(rulePegText, position42)
+					add(rulePegText, position43)
 				}
 				if !_rules[ruleAction3]() {
-					goto l38
+					goto l39
 				}
-				add(ruledestCmd, position39)
+				add(ruledestCmd, position40)
 			}
 			return true
-		l38:
-			position, tokenIndex = position38, tokenIndex38
+		l39:
+			position, tokenIndex = position39, tokenIndex39
 			return false
 		},
 		/* 7 readCmd <- <(startAddr? 'r' sp <param> Action4)> */
 		func() bool {
-			position43, tokenIndex43 := position, tokenIndex
+			position44, tokenIndex44 := position, tokenIndex
 			{
-				position44 := position
+				position45 := position
 				{
-					position45, tokenIndex45 := position, tokenIndex
+					position46, tokenIndex46 := position, tokenIndex
 					if !_rules[rulestartAddr]() {
-						goto l45
+						goto l46
 					}
-					goto l46
-				l45:
-					position, tokenIndex = position45, tokenIndex45
+					goto l47
+				l46:
+					position, tokenIndex = position46, tokenIndex46
 				}
-			l46:
+			l47:
 				if buffer[position] != rune('r') {
-					goto l43
+					goto l44
 				}
 				position++
 				if !_rules[rulesp]() {
-					goto l43
+					goto l44
 				}
 				{
-					position47 := position
+					position48 := position
 					if !_rules[ruleparam]() {
-						goto l43
+						goto l44
 					}
-					add(rulePegText, position47)
+					add(rulePegText, position48)
 				}
 				if !_rules[ruleAction4]() {
-					goto l43
+					goto l44
 				}
-				add(rulereadCmd, position44)
+				add(rulereadCmd, position45)
 			}
 			return true
-		l43:
-			position, tokenIndex = position43, tokenIndex43
+		l44:
+			position, tokenIndex = position44, tokenIndex44
 			return false
 		},
 		/* 8 writeCmd <- <((range? 'w' sp <param> Action5) / (range? 'w' Action6))> */
 		func() bool {
-			position48, tokenIndex48 := position, tokenIndex
+			position49, tokenIndex49 := position, tokenIndex
 			{
-				position49 := position
+				position50 := position
 				{
-					position50, tokenIndex50 := position, tokenIndex
+					position51, tokenIndex51 := position, tokenIndex
 					{
-						position52, tokenIndex52 := position, tokenIndex
+						position53, tokenIndex53 := position, tokenIndex
 						if !_rules[rulerange]() {
-							goto l52
+							goto l53
 						}
-						goto l53
-					l52:
-						position, tokenIndex = position52, tokenIndex52
+						goto l54
+					l53:
+						position, tokenIndex = position53, tokenIndex53
 					}
-				l53:
+				l54:
 					if buffer[position] != rune('w') {
-						goto l51
+						goto l52
 					}
 					position++
 					if !_rules[rulesp]() {
-						goto l51
+						goto l52
 					}
 					{
-						position54 := position
+						position55 := position
 						if !_rules[ruleparam]() {
-							goto l51
+							goto l52
 						}
-						add(rulePegText, position54)
+						add(rulePegText, position55)
 					}
 					if !_rules[ruleAction5]() {
-						goto l51
+						goto l52
 					}
-					goto l50
-				l51:
-					position, tokenIndex = position50, tokenIndex50
+					goto l51
+				l52:
+					position, tokenIndex = position51, tokenIndex51
 					{
-						position55, tokenIndex55 := position, tokenIndex
+						position56, tokenIndex56 := position, tokenIndex
 						if !_rules[rulerange]() {
-							goto l55
+							goto l56
 						}
-						goto l56
-					l55:
-						position, tokenIndex = position55, tokenIndex55
+						goto l57
+					l56:
+						position, tokenIndex = position56, tokenIndex56
 					}
-				l56:
+				l57:
 					if buffer[position] != rune('w') {
-						goto l48
+						goto l49
 					}
 					position++
 					if !_rules[ruleAction6]() {
-						goto l48
+						goto l49
+					}
+				}
+			l51:
+				add(rulewriteCmd, position50)
+			}
+			return true
+		l49:
+			position, tokenIndex = position49, tokenIndex49
+			return false
+		},
+		/* 9 substituteCmd <- <(range? 's' <substArgs> Action7)> */
+		func() bool {
+			position58, tokenIndex58 := position, tokenIndex
+			{
+				position59 := position
+				{
+					position60, tokenIndex60 := position, tokenIndex
+					if !_rules[rulerange]() {
+						goto l60
+					}
+					goto l61
+				l60:
+					position, tokenIndex = position60, tokenIndex60
+				}
+			l61:
+				if buffer[position] != rune('s') {
+					goto l58
+				}
+				position++
+				{
+					position62 := position
+					if !_rules[rulesubstArgs]() {
+						goto l58
+					}
+					add(rulePegText, position62)
+				}
+				if !_rules[ruleAction7]() {
+					goto l58
+				}
+				add(rulesubstituteCmd, position59)
+			}
+			return true
+		l58:
+			position, tokenIndex = position58, tokenIndex58
+			return false
+		},
+		/* 10 substArgs <- <(!(' ' / '\n') . (!'\n' .)*)> */
+		func() bool {
+			position63, tokenIndex63 := position, tokenIndex
+			{
+				position64 := position
+				{
+					position65, tokenIndex65 := position, tokenIndex
+					{
+						position66, tokenIndex66 := position, tokenIndex
+						if buffer[position] != rune(' ') {
+							goto l67
+						}
+						position++
+						goto l66
+					l67:
+						position, tokenIndex = position66, tokenIndex66
+						if buffer[position] != rune('\n') {
+							goto l65
+						}
+						position++
 					}
+				l66:
+					goto l63
+				l65:
+					position, tokenIndex = position65, tokenIndex65
 				}
-			l50:
-				add(rulewriteCmd, position49)
+				if !matchDot() {
+					goto l63
+				}
+			l68:
+				{
+					position69, tokenIndex69 := position, tokenIndex
+					{
+						position70, tokenIndex70 := position, tokenIndex
+						if buffer[position] != rune('\n') {
+							goto l70
+						}
+						position++
+						goto l69
+					l70:
+						position, tokenIndex = position70, tokenIndex70
+					}
+					if !matchDot() {
+						goto l69
+					}
+					goto l68
+				l69:
+					position, tokenIndex = position69, tokenIndex69
+				}
+				add(rulesubstArgs, position64)
 			}
 			return true
-		l48:
-			position, tokenIndex = position48, tokenIndex48
+		l63:
+			position, tokenIndex = position63, tokenIndex63
 			return false
 		},
-		/* 9 shellCmd <- <('!' <param> Action7)> */
+		/* 11 shellCmd <- <('!' <param> Action8)> */
 		func() bool {
-			position57, tokenIndex57 := position, tokenIndex
+			position71, tokenIndex71 := position, tokenIndex
 			{
-				position58 := position
+				position72 := position
 				if buffer[position] != rune('!') {
-					goto l57
+					goto l71
 				}
 				position++
 				{
-					position59 := position
+					position73 := position
 					if !_rules[ruleparam]() {
-						goto l57
+						goto l71
 					}
-					add(rulePegText, position59)
+					add(rulePegText, position73)
 				}
-				if !_rules[ruleAction7]() {
-					goto l57
+				if !_rules[ruleAction8]() {
+					goto l71
 				}
-				add(ruleshellCmd, position58)
+				add(ruleshellCmd, position72)
 			}
 			return true
-		l57:
-			position, tokenIndex = position57, tokenIndex57
+		l71:
+			position, tokenIndex = position71, tokenIndex71
 			return false
 		},
-		/* 10 nullCmd <- <startAddr?> */
+		/* 12 nullCmd <- <startAddr?> */
 		func() bool {
 			{
-				position61 := position
+				position75 := position
 				{
-					position62, tokenIndex62 := position, tokenIndex
+					position76, tokenIndex76 := position, tokenIndex
 					if !_rules[rulestartAddr]() {
-						goto l62
+						goto l76
 					}
-					goto l63
-				l62:
-					position, tokenIndex = position62, tokenIndex62
+					goto l77
+				l76:
+					position, tokenIndex = position76, tokenIndex76
 				}
-			l63:
-				add(rulenullCmd, position61)
+			l77:
+				add(rulenullCmd, position75)
 			}
 			return true
 		},
-		/* 11 text <- <(<(!textTerm .)*> textTerm Action8)> */
+		/* 13 text <- <(<(!textTerm .)*> textTerm Action9)> */
 		func() bool {
-			position64, tokenIndex64 := position, tokenIndex
+			position78, tokenIndex78 := position, tokenIndex
 			{
-				position65 := position
+				position79 := position
 				{
-					position66 := position
-				l67:
+					position80 := position
+				l81:
 					{
-						position68, tokenIndex68 := position, tokenIndex
+						position82, tokenIndex82 := position, tokenIndex
 						{
-							position69, tokenIndex69 := position, tokenIndex
+							position83, tokenIndex83 := position, tokenIndex
 							if !_rules[ruletextTerm]() {
-								goto l69
+								goto l83
 							}
-							goto l68
-						l69:
-							position, tokenIndex = position69, tokenIndex69
+							goto l82
+						l83:
+							position, tokenIndex = position83, tokenIndex83
 						}
 						if !matchDot() {
-							goto l68
+							goto l82
 						}
-						goto l67
-					l68:
-						position, tokenIndex = position68, tokenIndex68
+						goto l81
+					l82:
+						position, tokenIndex = position82, tokenIndex82
 					}
-					add(rulePegText, position66)
+					add(rulePegText, position80)
 				}
 				if !_rules[ruletextTerm]() {
-					goto l64
+					goto l78
 				}
-				if !_rules[ruleAction8]() {
-					goto l64
+				if !_rules[ruleAction9]() {
+					goto l78
 				}
-				add(ruletext, position65)
+				add(ruletext, position79)
 			}
 			return true
-		l64:
-			position, tokenIndex = position64, tokenIndex64
+		l78:
+			position, tokenIndex = position78, tokenIndex78
 			return false
 		},
-		/* 12 textTerm <- <('\n' '.')> */
+		/* 14 textTerm <- <('\n' '.')> */
 		func() bool {
-			position70, tokenIndex70 := position, tokenIndex
+			position84, tokenIndex84 := position, tokenIndex
 			{
-				position71 := position
+				position85 := position
 				if buffer[position] != rune('\n') {
-					goto l70
+					goto l84
 				}
 				position++
 				if buffer[position] != rune('.') {
-					goto l70
+					goto l84
 				}
 				position++
-				add(ruletextTerm, position71)
+				add(ruletextTerm, position85)
 			}
 			return true
-		l70:
-			position, tokenIndex = position70, tokenIndex70
+		l84:
+			position, tokenIndex = position84, tokenIndex84
 			return false
 		},
-		/* 13 rangeCmd <- <(range? sp* rangeC)> */
+		/* 15 rangeCmd <- <(range? sp* rangeC)> */
 		func() bool {
-			position72, tokenIndex72 := position, tokenIndex
+			position86, tokenIndex86 := position, tokenIndex
 			{
-				position73 := position
+				position87 := position
 				{
-					position74, tokenIndex74 := position, tokenIndex
+					position88, tokenIndex88 := position, tokenIndex
 					if !_rules[rulerange]() {
-						goto l74
+						goto l88
 					}
-					goto l75
-				l74:
-					position, tokenIndex = position74, tokenIndex74
+					goto l89
+				l88:
+					position, tokenIndex = position88, tokenIndex88
 				}
-			l75:
-			l76:
+			l89:
+			l90:
 				{
-					position77, tokenIndex77 := position, tokenIndex
+					position91, tokenIndex91 := position, tokenIndex
 					if !_rules[rulesp]() {
-						goto l77
+						goto l91
 					}
-					goto l76
-				l77:
-					position, tokenIndex = position77, tokenIndex77
+					goto l90
+				l91:
+					position, tokenIndex = position91, tokenIndex91
 				}
 				if !_rules[rulerangeC]() {
-					goto l72
+					goto l86
 				}
-				add(rulerangeCmd, position73)
+				add(rulerangeCmd, position87)
 			}
 			return true
-		l72:
-			position, tokenIndex = position72, tokenIndex72
+		l86:
+			position, tokenIndex = position86, tokenIndex86
 			return false
 		},
-		/* 14 range <- <((startAddr ',' endAddr) / (startAddr ',' Action9) / (',' endAddr Action10) / (startAddr ';' endAddr) / (startAddr ';' Action11) / (';' endAddr Action12) / (startAddr Action13) / (',' Action14) / (';' Action15))> */
+		/* 16 range <- <((startAddr ',' endAddr) / (startAddr ',' sp* Action10) / (',' endAddr sp* Action11) / (startAddr ';' endAddr) / (startAddr ';' sp* Action12) / (';' endAddr sp* Action13) / (startAddr sp* Action14) / (sp* ',' sp* Action15) / (sp* ';' sp* Action16))> */
 		func() bool {
-			position78, tokenIndex78 := position, tokenIndex
+			position92, tokenIndex92 := position, tokenIndex
 			{
-				position79 := position
+				position93 := position
 				{
-					position80, tokenIndex80 := position, tokenIndex
+					position94, tokenIndex94 := position, tokenIndex
 					if !_rules[rulestartAddr]() {
-						goto l81
+						goto l95
 					}
 					if buffer[position] != rune(',') {
-						goto l81
+						goto l95
 					}
 					position++
 					if !_rules[ruleendAddr]() {
-						goto l81
+						goto l95
 					}
-					goto l80
-				l81:
-					position, tokenIndex = position80, tokenIndex80
+					goto l94
+				l95:
+					position, tokenIndex = position94, tokenIndex94
 					if !_rules[rulestartAddr]() {
-						goto l82
+						goto l96
 					}
 					if buffer[position] != rune(',') {
-						goto l82
+						goto l96
 					}
 					position++
-					if !_rules[ruleAction9]() {
-						goto l82
+				l97:
+					{
+						position98, tokenIndex98 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l98
+						}
+						goto l97
+					l98:
+						position, tokenIndex = position98, tokenIndex98
+					}
+					if !_rules[ruleAction10]() {
+						goto l96
 					}
-					goto l80
-				l82:
-					position, tokenIndex = position80, tokenIndex80
+					goto l94
+				l96:
+					position, tokenIndex = position94, tokenIndex94
 					if buffer[position] != rune(',') {
-						goto l83
+						goto l99
 					}
 					position++
 					if !_rules[ruleendAddr]() {
-						goto l83
+						goto l99
 					}
-					if !_rules[ruleAction10]() {
-						goto l83
+				l100:
+					{
+						position101, tokenIndex101 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l101
+						}
+						goto l100
+					l101:
+						position, tokenIndex = position101, tokenIndex101
 					}
-					goto l80
-				l83:
-					position, tokenIndex = position80, tokenIndex80
+					if !_rules[ruleAction11]() {
+						goto l99
+					}
+					goto l94
+				l99:
+					position, tokenIndex = position94, tokenIndex94
 					if !_rules[rulestartAddr]() {
-						goto l84
+						goto l102
 					}
 					if buffer[position] != rune(';') {
-						goto l84
+						goto l102
 					}
 					position++
 					if !_rules[ruleendAddr]() {
-						goto l84
+						goto l102
 					}
-					goto l80
-				l84:
-					position, tokenIndex = position80, tokenIndex80
+					goto l94
+				l102:
+					position, tokenIndex = position94, tokenIndex94
 					if !_rules[rulestartAddr]() {
-						goto l85
+						goto l103
 					}
 					if buffer[position] != rune(';') {
-						goto l85
+						goto l103
 					}
 					position++
-					if !_rules[ruleAction11]() {
-						goto l85
+				l104:
+					{
+						position105, tokenIndex105 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l105
+						}
+						goto l104
+					l105:
+						position, tokenIndex = position105, tokenIndex105
 					}
-					goto l80
-				l85:
-					position, tokenIndex = position80, tokenIndex80
+					if !_rules[ruleAction12]() {
+						goto l103
+					}
+					goto l94
+				l103:
+					position, tokenIndex = position94, tokenIndex94
 					if buffer[position] != rune(';') {
-						goto l86
+						goto l106
 					}
 					position++
 					if !_rules[ruleendAddr]() {
-						goto l86
+						goto l106
 					}
-					if !_rules[ruleAction12]() {
-						goto l86
+				l107:
+					{
+						position108, tokenIndex108 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l108
+						}
+						goto l107
+					l108:
+						position, tokenIndex = position108, tokenIndex108
 					}
-					goto l80
-				l86:
-					position, tokenIndex = position80, tokenIndex80
+					if !_rules[ruleAction13]() {
+						goto l106
+					}
+					goto l94
+				l106:
+					position, tokenIndex = position94, tokenIndex94
 					if !_rules[rulestartAddr]() {
-						goto l87
+						goto l109
 					}
-					if !_rules[ruleAction13]() {
-						goto l87
+				l110:
+					{
+						position111, tokenIndex111 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l111
+						}
+						goto l110
+					l111:
+						position, tokenIndex = position111, tokenIndex111
+					}
+					if !_rules[ruleAction14]() {
+						goto l109
+					}
+					goto l94
+				l109:
+					position, tokenIndex = position94, tokenIndex94
+				l113:
+					{
+						position114, tokenIndex114 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l114
+						}
+						goto l113
+					l114:
+						position, tokenIndex = position114, tokenIndex114
 					}
-					goto l80
-				l87:
-					position, tokenIndex = position80, tokenIndex80
 					if buffer[position] != rune(',') {
-						goto l88
+						goto l112
 					}
 					position++
-					if !_rules[ruleAction14]() {
-						goto l88
+				l115:
+					{
+						position116, tokenIndex116 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l116
+						}
+						goto l115
+					l116:
+						position, tokenIndex = position116, tokenIndex116
+					}
+					if !_rules[ruleAction15]() {
+						goto l112
+					}
+					goto l94
+				l112:
+					position, tokenIndex = position94, tokenIndex94
+				l117:
+					{
+						position118, tokenIndex118 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l118
+						}
+						goto l117
+					l118:
+						position, tokenIndex = position118, tokenIndex118
 					}
-					goto l80
-				l88:
-					position, tokenIndex = position80, tokenIndex80
 					if buffer[position] != rune(';') {
-						goto l78
+						goto l92
 					}
 					position++
-					if !_rules[ruleAction15]() {
-						goto l78
+				l119:
+					{
+						position120, tokenIndex120 := position, tokenIndex
+						if !_rules[rulesp]() {
+							goto l120
+						}
+						goto l119
+					l120:
+						position, tokenIndex = position120, tokenIndex120
+					}
+					if !_rules[ruleAction16]() {
+						goto l92
 					}
 				}
-			l80:
-				add(rulerange, position79)
+			l94:
+				add(rulerange, position93)
 			}
 			return true
-		l78:
-			position, tokenIndex = position78, tokenIndex78
+		l92:
+			position, tokenIndex = position92, tokenIndex92
 			return false
 		},
-		/* 15 addrCmd <- <((<startAddr> addrC Action16) / addrC)> */
+		/* 17 addrCmd <- <((<startAddr> addrC Action17) / addrC)> */
 		func() bool {
-			position89, tokenIndex89 := position, tokenIndex
+			position121, tokenIndex121 := position, tokenIndex
 			{
-				position90 := position
+				position122 := position
 				{
-					position91, tokenIndex91 := position, tokenIndex
+					position123, tokenIndex123 := position, tokenIndex
 					{
-						position93 := position
+						position125 := position
 						if !_rules[rulestartAddr]() {
-							goto l92
+							goto l124
 						}
-						add(rulePegText, position93)
+						add(rulePegText, position125)
 					}
 					if !_rules[ruleaddrC]() {
-						goto l92
+						goto l124
 					}
-					if !_rules[ruleAction16]() {
-						goto l92
+					if !_rules[ruleAction17]() {
+						goto l124
 					}
-					goto l91
-				l92:
-					position, tokenIndex = position91, tokenIndex91
+					goto l123
+				l124:
+					position, tokenIndex = position123, tokenIndex123
 					if !_rules[ruleaddrC]() {
-						goto l89
+						goto l121
 					}
 				}
-			l91:
-				add(ruleaddrCmd, position90)
+			l123:
+				add(ruleaddrCmd, position122)
 			}
 			return true
-		l89:
-			position, tokenIndex = position89, tokenIndex89
+		l121:
+			position, tokenIndex = position121, tokenIndex121
 			return false
 		},
-		/* 16 startAddr <- <(sp* addrO sp* Action17)> */
+		/* 18 startAddr <- <(sp* addrO sp* Action18)> */
 		func() bool {
-			position94, tokenIndex94 := position, tokenIndex
+			position126, tokenIndex126 := position, tokenIndex
 			{
-				position95 := position
-			l96:
+				position127 := position
+			l128:
 				{
-					position97, tokenIndex97 := position, tokenIndex
+					position129, tokenIndex129 := position, tokenIndex
 					if !_rules[rulesp]() {
-						goto l97
+						goto l129
 					}
-					goto l96
-				l97:
-					position, tokenIndex = position97, tokenIndex97
+					goto l128
+				l129:
+					position, tokenIndex = position129, tokenIndex129
 				}
 				if !_rules[ruleaddrO]() {
-					goto l94
+					goto l126
 				}
-			l98:
+			l130:
 				{
-					position99, tokenIndex99 := position, tokenIndex
+					position131, tokenIndex131 := position, tokenIndex
 					if !_rules[rulesp]() {
-						goto l99
+						goto l131
 					}
-					goto l98
-				l99:
-					position, tokenIndex = position99, tokenIndex99
+					goto l130
+				l131:
+					position, tokenIndex = position131, tokenIndex131
 				}
-				if !_rules[ruleAction17]() {
-					goto l94
+				if !_rules[ruleAction18]() {
+					goto l126
 				}
-				add(rulestartAddr, position95)
+				add(rulestartAddr, position127)
 			}
 			return true
-		l94:
-			position, tokenIndex = position94, tokenIndex94
+		l126:
+			position, tokenIndex = position126, tokenIndex126
 			return false
 		},
-		/* 17 endAddr <- <(sp* addrO sp* Action18)> */
+		/* 19 endAddr <- <(sp* addrO sp* Action19)> */
 		func() bool {
-			position100, tokenIndex100 := position, tokenIndex
+			position132, tokenIndex132 := position, tokenIndex
 			{
-				position101 := position
-			l102:
+				position133 := position
+			l134:
 				{
-					position103, tokenIndex103 := position, tokenIndex
+					position135, tokenIndex135 := position, tokenIndex
 					if !_rules[rulesp]() {
-						goto l103
+						goto l135
 					}
-					goto l102
-				l103:
-					position, tokenIndex = position103, tokenIndex103
+					goto l134
+				l135:
+					position, tokenIndex = position135, tokenIndex135
 				}
 				if !_rules[ruleaddrO]() {
-					goto l100
+					goto l132
 				}
-			l104:
+			l136:
 				{
-					position105, tokenIndex105 := position, tokenIndex
+					position137, tokenIndex137 := position, tokenIndex
 					if !_rules[rulesp]() {
-						goto l105
+						goto l137
 					}
-					goto l104
-				l105:
-					position, tokenIndex = position105, tokenIndex105
+					goto l136
+				l137:
+					position, tokenIndex = position137, tokenIndex137
 				}
-				if !_rules[ruleAction18]() {
-					goto l100
+				if !_rules[ruleAction19]() {
+					goto l132
 				}
-				add(ruleendAddr, position101)
+				add(ruleendAddr, position133)
 			}
 			return true
-		l100:
-			position, tokenIndex = position100, tokenIndex100
+		l132:
+			position, tokenIndex = position132, tokenIndex132
 			return false
 		},
-		/* 18 addrO <- <(<(addr offset?)> Action19)> */
+		/* 20 addrO <- <(<(addr offset?)> Action20)> */
 		func() bool {
-			position106, tokenIndex106 := position, tokenIndex
+			position138, tokenIndex138 := position, tokenIndex
 			{
-				position107 := position
+				position139 := position
 				{
-					position108 := position
+					position140 := position
 					if !_rules[ruleaddr]() {
-						goto l106
+						goto l138
 					}
 					{
-						position109, tokenIndex109 := position, tokenIndex
+						position141, tokenIndex141 := position, tokenIndex
 						if !_rules[ruleoffset]() {
-							goto l109
+							goto l141
 						}
-						goto l110
-					l109:
-						position, tokenIndex = position109, tokenIndex109
+						goto l142
+					l141:
+						position, tokenIndex = position141, tokenIndex141
 					}
-				l110:
-					add(rulePegText, position108)
+				l142:
+					add(rulePegText, position140)
 				}
-				if !_rules[ruleAction19]() {
-					goto l106
+				if !_rules[ruleAction20]() {
+					goto l138
 				}
-				add(ruleaddrO, position107)
+				add(ruleaddrO, position139)
 			}
 			return true
-		l106:
-			position, tokenIndex = position106, tokenIndex106
+		l138:
+			position, tokenIndex = position138, tokenIndex138
 			return false
 		},
-		/* 19 addr <- <(literalAddr / markAddr / regexAddr / regexReverseAddr / ('.' Action20) / ('$' Action21))> */
+		/* 21 addr <- <(literalAddr / markAddr / regexAddr / regexReverseAddr / ('.' Action21) / ('$' Action22))> */
 		func() bool {
-			position111, tokenIndex111 := position, tokenIndex
+			position143, tokenIndex143 := position, tokenIndex
 			{
-				position112 := position
+				position144 := position
 				{
-					position113, tokenIndex113 := position, tokenIndex
+					position145, tokenIndex145 := position, tokenIndex
 					if !_rules[ruleliteralAddr]() {
-						goto l114
+						goto l146
 					}
-					goto l113
-				l114:
-					position, tokenIndex = position113, tokenIndex113
+					goto l145
+				l146:
+					position, tokenIndex = position145, tokenIndex145
 					if !_rules[rulemarkAddr]() {
-						goto l115
+						goto l147
 					}
-					goto l113
-				l115:
-					position, tokenIndex = position113, tokenIndex113
+					goto l145
+				l147:
+					position, tokenIndex = position145, tokenIndex145
 					if !_rules[ruleregexAddr]() {
-						goto l116
+						goto l148
 					}
-					goto l113
-				l116:
-					position, tokenIndex = position113, tokenIndex113
+					goto l145
+				l148:
+					position, tokenIndex = position145, tokenIndex145
 					if !_rules[ruleregexReverseAddr]() {
-						goto l117
+						goto l149
 					}
-					goto l113
-				l117:
-					position, tokenIndex = position113, tokenIndex113
+					goto l145
+				l149:
+					position, tokenIndex = position145, tokenIndex145
 					if buffer[position] != rune('.') {
-						goto l118
+						goto l150
 					}
 					position++
-					if !_rules[ruleAction20]() {
-						goto l118
+					if !_rules[ruleAction21]() {
+						goto l150
 					}
-					goto l113
-				l118:
-					position, tokenIndex = position113, tokenIndex113
+					goto l145
+				l150:
+					position, tokenIndex = position145, tokenIndex145
 					if buffer[position] != rune('$') {
-						goto l111
+						goto l143
 					}
 					position++
-					if !_rules[ruleAction21]() {
-						goto l111
+					if !_rules[ruleAction22]() {
+						goto l143
 					}
 				}
-			l113:
-				add(ruleaddr, position112)
+			l145:
+				add(ruleaddr, position144)
 			}
 			return true
-		l111:
-			position, tokenIndex = position111, tokenIndex111
+		l143:
+			position, tokenIndex = position143, tokenIndex143
 			return false
 		},
-		/* 20 literalAddr <- <(<[0-9]+> Action22)> */
+		/* 22 literalAddr <- <(<[0-9]+> Action23)> */
 		func() bool {
-			position119, tokenIndex119 := position, tokenIndex
+			position151, tokenIndex151 := position, tokenIndex
 			{
-				position120 := position
+				position152 := position
 				{
-					position121 := position
+					position153 := position
 					if c := buffer[position]; c < rune('0') || c > rune('9') {
-						goto l119
+						goto l151
 					}
 					position++
-				l122:
+				l154:
 					{
-						position123, tokenIndex123 := position, tokenIndex
+						position155, tokenIndex155 := position, tokenIndex
 						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l123
+							goto l155
 						}
 						position++
-						goto l122
-					l123:
-						position, tokenIndex = position123, tokenIndex123
+						goto l154
+					l155:
+						position, tokenIndex = position155, tokenIndex155
 					}
-					add(rulePegText, position121)
+					add(rulePegText, position153)
 				}
-				if !_rules[ruleAction22]() {
-					goto l119
+				if !_rules[ruleAction23]() {
+					goto l151
 				}
-				add(ruleliteralAddr, position120)
+				add(ruleliteralAddr, position152)
 			}
 			return true
-		l119:
-			position, tokenIndex = position119, tokenIndex119
+		l151:
+			position, tokenIndex = position151, tokenIndex151
 			return false
 		},
-		/* 21 markAddr <- <('\'' [a-z] Action23)> */
+		/* 23 markAddr <- <('\'' [a-z] Action24)> */
 		func() bool {
-			position124, tokenIndex124 := position, tokenIndex
+			position156, tokenIndex156 := position, tokenIndex
 			{
-				position125 := position
+				position157 := position
 				if buffer[position] != rune('\'') {
-					goto l124
+					goto l156
 				}
 				position++
 				if c := buffer[position]; c < rune('a') || c > rune('z') {
-					goto l124
+					goto l156
 				}
 				position++
-				if !_rules[ruleAction23]() {
-					goto l124
+				if !_rules[ruleAction24]() {
+					goto l156
 				}
-				add(rulemarkAddr, position125)
+				add(rulemarkAddr, position157)
 			}
 			return true
-		l124:
-			position, tokenIndex = position124, tokenIndex124
+		l156:
+			position, tokenIndex = position156, tokenIndex156
 			return false
 		},
-		/* 22 regexAddr <- <('/' basic_regex '/' Action24)> */
+		/* 24 regexAddr <- <('/' basic_regex '/' Action25)> */
 		func() bool {
-			position126, tokenIndex126 := position, tokenIndex
+			position158, tokenIndex158 := position, tokenIndex
 			{
-				position127 := position
+				position159 := position
 				if buffer[position] != rune('/') {
-					goto l126
+					goto l158
 				}
 				position++
 				if !_rules[rulebasic_regex]() {
-					goto l126
+					goto l158
 				}
 				if buffer[position] != rune('/') {
-					goto l126
+					goto l158
 				}
 				position++
-				if !_rules[ruleAction24]() {
-					goto l126
+				if !_rules[ruleAction25]() {
+					goto l158
 				}
-				add(ruleregexAddr, position127)
+				add(ruleregexAddr, position159)
 			}
 			return true
-		l126:
-			position, tokenIndex = position126, tokenIndex126
+		l158:
+			position, tokenIndex = position158, tokenIndex158
 			return false
 		},
-		/* 23 regexReverseAddr <- <('?' back_regex '?' Action25)> */
+		/* 25 regexReverseAddr <- <('?' back_regex '?' Action26)> */
 		func() bool {
-			position128, tokenIndex128 := position, tokenIndex
+			position160, tokenIndex160 := position, tokenIndex
 			{
-				position129 := position
+				position161 := position
 				if buffer[position] != rune('?') {
-					goto l128
+					goto l160
 				}
 				position++
 				if !_rules[ruleback_regex]() {
-					goto l128
+					goto l160
 				}
 				if buffer[position] != rune('?') {
-					goto l128
+					goto l160
 				}
 				position++
-				if !_rules[ruleAction25]() {
-					goto l128
+				if !_rules[ruleAction26]() {
+					goto l160
 				}
-				add(ruleregexReverseAddr, position129)
+				add(ruleregexReverseAddr, position161)
 			}
 			return true
-		l128:
-			position, tokenIndex = position128, tokenIndex128
+		l160:
+			position, tokenIndex = position160, tokenIndex160
 			return false
 		},
-		/* 24 basic_regex <- <(('\\' '/') / (!('\n' / '/') .))+> */
+		/* 26 basic_regex <- <(('\\' '/') / (!('\n' / '/') .))+> */
 		func() bool {
-			position130, tokenIndex130 := position, tokenIndex
+			position162, tokenIndex162 := position, tokenIndex
 			{
-				position131 := position
+				position163 := position
 				{
-					position134, tokenIndex134 := position, tokenIndex
+					position166, tokenIndex166 := position, tokenIndex
 					if buffer[position] != rune('\\') {
-						goto l135
+						goto l167
 					}
 					position++
 					if buffer[position] != rune('/') {
-						goto l135
+						goto l167
 					}
 					position++
-					goto l134
-				l135:
-					position, tokenIndex = position134, tokenIndex134
+					goto l166
+				l167:
+					position, tokenIndex = position166, tokenIndex166
 					{
-						position136, tokenIndex136 := position, tokenIndex
+						position168, tokenIndex168 := position, tokenIndex
 						{
-							position137, tokenIndex137 := position, tokenIndex
+							position169, tokenIndex169 := position, tokenIndex
 							if buffer[position] != rune('\n') {
-								goto l138
+								goto l170
 							}
 							position++
-							goto l137
-						l138:
-							position, tokenIndex = position137, tokenIndex137
+							goto l169
+						l170:
+							position, tokenIndex = position169, tokenIndex169
 							if buffer[position] != rune('/') {
-								goto l136
+								goto l168
 							}
 							position++
 						}
-					l137:
-						goto l130
-					l136:
-						position, tokenIndex = position136, tokenIndex136
+					l169:
+						goto l162
+					l168:
+						position, tokenIndex = position168, tokenIndex168
 					}
 					if !matchDot() {
-						goto l130
+						goto l162
 					}
 				}
-			l134:
-			l132:
+			l166:
+			l164:
 				{
-					position133, tokenIndex133 := position, tokenIndex
+					position165, tokenIndex165 := position, tokenIndex
 					{
-						position139, tokenIndex139 := position, tokenIndex
+						position171, tokenIndex171 := position, tokenIndex
 						if buffer[position] != rune('\\') {
-							goto l140
+							goto l172
 						}
 						position++
 						if buffer[position] != rune('/') {
-							goto l140
+							goto l172
 						}
 						position++
-						goto l139
-					l140:
-						position, tokenIndex = position139, tokenIndex139
+						goto l171
+					l172:
+						position, tokenIndex = position171, tokenIndex171
 						{
-							position141, tokenIndex141 := position, tokenIndex
+							position173, tokenIndex173 := position, tokenIndex
 							{
-								position142, tokenIndex142 := position, tokenIndex
+								position174, tokenIndex174 := position, tokenIndex
 								if buffer[position] != rune('\n') {
-									goto l143
+									goto l175
 								}
 								position++
-								goto l142
-							l143:
-								position, tokenIndex = position142, tokenIndex142
+								goto l174
+							l175:
+								position, tokenIndex = position174, tokenIndex174
 								if buffer[position] != rune('/') {
-									goto l141
+									goto l173
 								}
 								position++
 							}
-						l142:
-							goto l133
-						l141:
-							position, tokenIndex = position141, tokenIndex141
+						l174:
+							goto l165
+						l173:
+							position, tokenIndex = position173, tokenIndex173
 						}
 						if !matchDot() {
-							goto l133
+							goto l165
 						}
 					}
-				l139:
-					goto l132
-				l133:
-					position, tokenIndex = position133, tokenIndex133
+				l171:
+					goto l164
+				l165:
+					position, tokenIndex = position165, tokenIndex165
 				}
-				add(rulebasic_regex, position131)
+				add(rulebasic_regex, position163)
 			}
 			return true
-		l130:
-			position, tokenIndex = position130, tokenIndex130
+		l162:
+			position, tokenIndex = position162, tokenIndex162
 			return false
 		},
-		/* 25 back_regex <- <(('\\' '?') / (!('\n' / '?') .))+> */
+		/* 27 back_regex <- <(('\\' '?') / (!('\n' / '?') .))+> */
 		func() bool {
-			position144, tokenIndex144 := position, tokenIndex
+			position176, tokenIndex176 := position, tokenIndex
 			{
-				position145 := position
+				position177 := position
 				{
-					position148, tokenIndex148 := position, tokenIndex
+					position180, tokenIndex180 := position, tokenIndex
 					if buffer[position] != rune('\\') {
-						goto l149
+						goto l181
 					}
 					position++
 					if buffer[position] != rune('?') {
-						goto l149
+						goto l181
 					}
 					position++
-					goto l148
-				l149:
-					position, tokenIndex = position148, tokenIndex148
+					goto l180
+				l181:
+					position, tokenIndex = position180, tokenIndex180
 					{
-						position150, tokenIndex150 := position, tokenIndex
+						position182, tokenIndex182 := position, tokenIndex
 						{
-							position151, tokenIndex151 := position, tokenIndex
+							position183, tokenIndex183 := position, tokenIndex
 							if buffer[position] != rune('\n') {
-								goto l152
+								goto l184
 							}
 							position++
-							goto l151
-						l152:
-							position, tokenIndex = position151, tokenIndex151
+							goto l183
+						l184:
+							position, tokenIndex = position183, tokenIndex183
 							if buffer[position] != rune('?') {
-								goto l150
+								goto l182
 							}
 							position++
 						}
-					l151:
-						goto l144
-					l150:
-						position, tokenIndex = position150, tokenIndex150
+					l183:
+						goto l176
+					l182:
+						position, tokenIndex = position182, tokenIndex182
 					}
 					if !matchDot() {
-						goto l144
+						goto l176
 					}
 				}
-			l148:
-			l146:
+			l180:
+			l178:
 				{
-					position147, tokenIndex147 := position, tokenIndex
+					position179, tokenIndex179 := position, tokenIndex
 					{
-						position153, tokenIndex153 := position, tokenIndex
+						position185, tokenIndex185 := position, tokenIndex
 						if buffer[position] != rune('\\') {
-							goto l154
+							goto l186
 						}
 						position++
 						if buffer[position] != rune('?') {
-							goto l154
+							goto l186
 						}
 						position++
-						goto l153
-					l154:
-						position, tokenIndex = position153, tokenIndex153
+						goto l185
+					l186:
+						position, tokenIndex = position185, tokenIndex185
 						{
-							position155, tokenIndex155 := position, tokenIndex
+							position187, tokenIndex187 := position, tokenIndex
 							{
-								position156, tokenIndex156 := position, tokenIndex
+								position188, tokenIndex188 := position, tokenIndex
 								if buffer[position] != rune('\n') {
-									goto l157
+									goto l189
 								}
 								position++
-								goto l156
-							l157:
-								position, tokenIndex = position156, tokenIndex156
+								goto l188
+							l189:
+								position, tokenIndex = position188, tokenIndex188
 								if buffer[position] != rune('?') {
-									goto l155
+									goto l187
 								}
 								position++
 							}
-						l156:
-							goto l147
-						l155:
-							position, tokenIndex = position155, tokenIndex155
+						l188:
+							goto l179
+						l187:
+							position, tokenIndex = position187, tokenIndex187
 						}
 						if !matchDot() {
-							goto l147
+							goto l179
 						}
 					}
-				l153:
-					goto l146
-				l147:
-					position, tokenIndex = position147, tokenIndex147
+				l185:
+					goto l178
+				l179:
+					position, tokenIndex = position179, tokenIndex179
 				}
-				add(ruleback_regex, position145)
+				add(ruleback_regex, position177)
 			}
 			return true
-		l144:
-			position, tokenIndex = position144, tokenIndex144
+		l176:
+			position, tokenIndex = position176, tokenIndex176
 			return false
 		},
-		/* 26 bareCmd <- <(('h' Action26) / ('H' Action27) / ('P' Action28) / ('q' Action29) / ('Q' Action30) / ('u' Action31))> */
+		/* 28 bareCmd <- <(('h' Action27) / ('H' Action28) / ('P' Action29) / ('q' Action30) / ('Q' Action31) / ('u' Action32))> */
 		func() bool {
-			position158, tokenIndex158 := position, tokenIndex
+			position190, tokenIndex190 := position, tokenIndex
 			{
-				position159 := position
+				position191 := position
 				{
-					position160, tokenIndex160 := position, tokenIndex
+					position192, tokenIndex192 := position, tokenIndex
 					if buffer[position] != rune('h') {
-						goto l161
+						goto l193
 					}
 					position++
-					if !_rules[ruleAction26]() {
-						goto l161
+					if !_rules[ruleAction27]() {
+						goto l193
 					}
-					goto l160
-				l161:
-					position, tokenIndex = position160, tokenIndex160
+					goto l192
+				l193:
+					position, tokenIndex = position192, tokenIndex192
 					if buffer[position] != rune('H') {
-						goto l162
+						goto l194
 					}
 					position++
-					if !_rules[ruleAction27]() {
-						goto l162
+					if !_rules[ruleAction28]() {
+						goto l194
 					}
-					goto l160
-				l162:
-					position, tokenIndex = position160, tokenIndex160
+					goto l192
+				l194:
+					position, tokenIndex = position192, tokenIndex192
 					if buffer[position] != rune('P') {
-						goto l163
+						goto l195
 					}
 					position++
-					if !_rules[ruleAction28]() {
-						goto l163
+					if !_rules[ruleAction29]() {
+						goto l195
 					}
-					goto l160
-				l163:
-					position, tokenIndex = position160, tokenIndex160
+					goto l192
+				l195:
+					position, tokenIndex = position192, tokenIndex192
 					if buffer[position] != rune('q') {
-						goto l164
+						goto l196
 					}
 					position++
-					if !_rules[ruleAction29]() {
-						goto l164
+					if !_rules[ruleAction30]() {
+						goto l196
 					}
-					goto l160
-				l164:
-					position, tokenIndex = position160, tokenIndex160
+					goto l192
+				l196:
+					position, tokenIndex = position192, tokenIndex192
 					if buffer[position] != rune('Q') {
-						goto l165
+						goto l197
 					}
 					position++
-					if !_rules[ruleAction30]() {
-						goto l165
+					if !_rules[ruleAction31]() {
+						goto l197
 					}
-					goto l160
-				l165:
-					position, tokenIndex = position160, tokenIndex160
+					goto l192
+				l197:
+					position, tokenIndex = position192, tokenIndex192
 					if buffer[position] != rune('u') {
-						goto l158
+						goto l190
 					}
 					position++
-					if !_rules[ruleAction31]() {
-						goto l158
+					if !_rules[ruleAction32]() {
+						goto l190
 					}
 				}
-			l160:
-				add(rulebareCmd, position159)
+			l192:
+				add(rulebareCmd, position191)
 			}
 			return true
-		l158:
-			position, tokenIndex = position158, tokenIndex158
+		l190:
+			position, tokenIndex = position190, tokenIndex190
 			return false
 		},
-		/* 27 offset <- <(('+' / '-') [0-9]*)> */
+		/* 29 offset <- <(('+' / '-') [0-9]*)> */
 		func() bool {
-			position166, tokenIndex166 := position, tokenIndex
+			position198, tokenIndex198 := position, tokenIndex
 			{
-				position167 := position
+				position199 := position
 				{
-					position168, tokenIndex168 := position, tokenIndex
+					position200, tokenIndex200 := position, tokenIndex
 					if buffer[position] != rune('+') {
-						goto l169
+						goto l201
 					}
 					position++
-					goto l168
-				l169:
-					position, tokenIndex = position168, tokenIndex168
+					goto l200
+				l201:
+					position, tokenIndex = position200, tokenIndex200
 					if buffer[position] != rune('-') {
-						goto l166
+						goto l198
 					}
 					position++
 				}
-			l168:
-			l170:
+			l200:
+			l202:
 				{
-					position171, tokenIndex171 := position, tokenIndex
+					position203, tokenIndex203 := position, tokenIndex
 					if c := buffer[position]; c < rune('0') || c > rune('9') {
-						goto l171
+						goto l203
 					}
 					position++
-					goto l170
-				l171:
-					position, tokenIndex = position171, tokenIndex171
+					goto l202
+				l203:
+					position, tokenIndex = position203, tokenIndex203
 				}
-				add(ruleoffset, position167)
+				add(ruleoffset, position199)
 			}
 			return true
-		l166:
-			position, tokenIndex = position166, tokenIndex166
+		l198:
+			position, tokenIndex = position198, tokenIndex198
 			return false
 		},
-		/* 28 paramCmd <- <((paramC sp <param> Action32) / paramC)> */
+		/* 30 paramCmd <- <((paramC sp <param> Action33) / paramC)> */
 		func() bool {
-			position172, tokenIndex172 := position, tokenIndex
+			position204, tokenIndex204 := position, tokenIndex
 			{
-				position173 := position
+				position205 := position
 				{
-					position174, tokenIndex174 := position, tokenIndex
+					position206, tokenIndex206 := position, tokenIndex
 					if !_rules[ruleparamC]() {
-						goto l175
+						goto l207
 					}
 					if !_rules[rulesp]() {
-						goto l175
+						goto l207
 					}
 					{
-						position176 := position
+						position208 := position
 						if !_rules[ruleparam]() {
-							goto l175
+							goto l207
 						}
-						add(rulePegText, position176)
+						add(rulePegText, position208)
 					}
-					if !_rules[ruleAction32]() {
-						goto l175
+					if !_rules[ruleAction33]() {
+						goto l207
 					}
-					goto l174
-				l175:
-					position, tokenIndex = position174, tokenIndex174
+					goto l206
+				l207:
+					position, tokenIndex = position206, tokenIndex206
 					if !_rules[ruleparamC]() {
-						goto l172
+						goto l204
 					}
 				}
-			l174:
-				add(ruleparamCmd, position173)
+			l206:
+				add(ruleparamCmd, position205)
 			}
 			return true
-		l172:
-			position, tokenIndex = position172, tokenIndex172
+		l204:
+			position, tokenIndex = position204, tokenIndex204
 			return false
 		},
-		/* 29 paramC <- <(('e' Action33) / ('E' Action34) / ('f' Action35))> */
+		/* 31 paramC <- <(('e' Action34) / ('E' Action35) / ('f' Action36))> */
 		func() bool {
-			position177, tokenIndex177 := position, tokenIndex
+			position209, tokenIndex209 := position, tokenIndex
 			{
-				position178 := position
+				position210 := position
 				{
-					position179, tokenIndex179 := position, tokenIndex
+					position211, tokenIndex211 := position, tokenIndex
 					if buffer[position] != rune('e') {
-						goto l180
+						goto l212
 					}
 					position++
-					if !_rules[ruleAction33]() {
-						goto l180
+					if !_rules[ruleAction34]() {
+						goto l212
 					}
-					goto l179
-				l180:
-					position, tokenIndex = position179, tokenIndex179
+					goto l211
+				l212:
+					position, tokenIndex = position211, tokenIndex211
 					if buffer[position] != rune('E') {
-						goto l181
+						goto l213
 					}
 					position++
-					if !_rules[ruleAction34]() {
-						goto l181
+					if !_rules[ruleAction35]() {
+						goto l213
 					}
-					goto l179
-				l181:
-					position, tokenIndex = position179, tokenIndex179
+					goto l211
+				l213:
+					position, tokenIndex = position211, tokenIndex211
 					if buffer[position] != rune('f') {
-						goto l177
+						goto l209
 					}
 					position++
-					if !_rules[ruleAction35]() {
-						goto l177
+					if !_rules[ruleAction36]() {
+						goto l209
 					}
 				}
-			l179:
-				add(ruleparamC, position178)
+			l211:
+				add(ruleparamC, position210)
 			}
 			return true
-		l177:
-			position, tokenIndex = position177, tokenIndex177
+		l209:
+			position, tokenIndex = position209, tokenIndex209
 			return false
 		},
-		/* 30 param <- <(!'\n' .)+> */
+		/* 32 param <- <(!'\n' .)+> */
 		func() bool {
-			position182, tokenIndex182 := position, tokenIndex
+			position214, tokenIndex214 := position, tokenIndex
 			{
-				position183 := position
+				position215 := position
 				{
-					position186, tokenIndex186 := position, tokenIndex
+					position218, tokenIndex218 := position, tokenIndex
 					if buffer[position] != rune('\n') {
-						goto l186
+						goto l218
 					}
 					position++
-					goto l182
-				l186:
-					position, tokenIndex = position186, tokenIndex186
+					goto l214
+				l218:
+					position, tokenIndex = position218, tokenIndex218
 				}
 				if !matchDot() {
-					goto l182
+					goto l214
 				}
-			l184:
+			l216:
 				{
-					position185, tokenIndex185 := position, tokenIndex
+					position217, tokenIndex217 := position, tokenIndex
 					{
-						position187, tokenIndex187 := position, tokenIndex
+						position219, tokenIndex219 := position, tokenIndex
 						if buffer[position] != rune('\n') {
-							goto l187
+							goto l219
 						}
 						position++
-						goto l185
-					l187:
-						position, tokenIndex = position187, tokenIndex187
+						goto l217
+					l219:
+						position, tokenIndex = position219, tokenIndex219
 					}
 					if !matchDot() {
-						goto l185
+						goto l217
 					}
-					goto l184
-				l185:
-					position, tokenIndex = position185, tokenIndex185
+					goto l216
+				l217:
+					position, tokenIndex = position217, tokenIndex217
 				}
-				add(ruleparam, position183)
+				add(ruleparam, position215)
 			}
 			return true
-		l182:
-			position, tokenIndex = position182, tokenIndex182
+		l214:
+			position, tokenIndex = position214, tokenIndex214
 			return false
 		},
-		/* 31 addrC <- <('=' Action36)> */
+		/* 33 addrC <- <('=' Action37)> */
 		func() bool {
-			position188, tokenIndex188 := position, tokenIndex
+			position220, tokenIndex220 := position, tokenIndex
 			{
-				position189 := position
+				position221 := position
 				if buffer[position] != rune('=') {
-					goto l188
+					goto l220
 				}
 				position++
-				if !_rules[ruleAction36]() {
-					goto l188
+				if !_rules[ruleAction37]() {
+					goto l220
 				}
-				add(ruleaddrC, position189)
+				add(ruleaddrC, position221)
 			}
 			return true
-		l188:
-			position, tokenIndex = position188, tokenIndex188
+		l220:
+			position, tokenIndex = position220, tokenIndex220
 			return false
 		},
-		/* 32 changeTextC <- <('c' Action37)> */
+		/* 34 changeTextC <- <('c' Action38)> */
 		func() bool {
-			position190, tokenIndex190 := position, tokenIndex
+			position222, tokenIndex222 := position, tokenIndex
 			{
-				position191 := position
+				position223 := position
 				if buffer[position] != rune('c') {
-					goto l190
+					goto l222
 				}
 				position++
-				if !_rules[ruleAction37]() {
-					goto l190
+				if !_rules[ruleAction38]() {
+					goto l222
 				}
-				add(rulechangeTextC, position191)
+				add(rulechangeTextC, position223)
 			}
 			return true
-		l190:
-			position, tokenIndex = position190, tokenIndex190
+		l222:
+			position, tokenIndex = position222, tokenIndex222
 			return false
 		},
-		/* 33 addTextC <- <(('a' Action38) / ('i' Action39))> */
+		/* 35 addTextC <- <(('a' Action39) / ('i' Action40))> */
 		func() bool {
-			position192, tokenIndex192 := position, tokenIndex
+			position224, tokenIndex224 := position, tokenIndex
 			{
-				position193 := position
+				position225 := position
 				{
-					position194, tokenIndex194 := position, tokenIndex
+					position226, tokenIndex226 := position, tokenIndex
 					if buffer[position] != rune('a') {
-						goto l195
+						goto l227
 					}
 					position++
-					if !_rules[ruleAction38]() {
-						goto l195
+					if !_rules[ruleAction39]() {
+						goto l227
 					}
-					goto l194
-				l195:
-					position, tokenIndex = position194, tokenIndex194
+					goto l226
+				l227:
+					position, tokenIndex = position226, tokenIndex226
 					if buffer[position] != rune('i') {
-						goto l192
+						goto l224
 					}
 					position++
-					if !_rules[ruleAction39]() {
-						goto l192
+					if !_rules[ruleAction40]() {
+						goto l224
 					}
 				}
-			l194:
-				add(ruleaddTextC, position193)
+			l226:
+				add(ruleaddTextC, position225)
 			}
 			return true
-		l192:
-			position, tokenIndex = position192, tokenIndex192
+		l224:
+			position, tokenIndex = position224, tokenIndex224
 			return false
 		},
-		/* 34 rangeC <- <(('d' Action40) / ('j' Action41) / ('l' Action42) / ('n' Action43) / ('p' Action44))> */
+		/* 36 rangeC <- <(('d' Action41) / ('j' Action42) / ('l' Action43) / ('n' Action44) / ('p' Action45))> */
 		func() bool {
-			position196, tokenIndex196 := position, tokenIndex
+			position228, tokenIndex228 := position, tokenIndex
 			{
-				position197 := position
+				position229 := position
 				{
-					position198, tokenIndex198 := position, tokenIndex
+					position230, tokenIndex230 := position, tokenIndex
 					if buffer[position] != rune('d') {
-						goto l199
+						goto l231
 					}
 					position++
-					if !_rules[ruleAction40]() {
-						goto l199
+					if !_rules[ruleAction41]() {
+						goto l231
 					}
-					goto l198
-				l199:
-					position, tokenIndex = position198, tokenIndex198
+					goto l230
+				l231:
+					position, tokenIndex = position230, tokenIndex230
 					if buffer[position] != rune('j') {
-						goto l200
+						goto l232
 					}
 					position++
-					if !_rules[ruleAction41]() {
-						goto l200
+					if !_rules[ruleAction42]() {
+						goto l232
 					}
-					goto l198
-				l200:
-					position, tokenIndex = position198, tokenIndex198
+					goto l230
+				l232:
+					position, tokenIndex = position230, tokenIndex230
 					if buffer[position] != rune('l') {
-						goto l201
+						goto l233
 					}
 					position++
-					if !_rules[ruleAction42]() {
-						goto l201
+					if !_rules[ruleAction43]() {
+						goto l233
 					}
-					goto l198
-				l201:
-					position, tokenIndex = position198, tokenIndex198
+					goto l230
+				l233:
+					position, tokenIndex = position230, tokenIndex230
 					if buffer[position] != rune('n') {
-						goto l202
+						goto l234
 					}
 					position++
-					if !_rules[ruleAction43]() {
-						goto l202
+					if !_rules[ruleAction44]() {
+						goto l234
 					}
-					goto l198
-				l202:
-					position, tokenIndex = position198, tokenIndex198
+					goto l230
+				l234:
+					position, tokenIndex = position230, tokenIndex230
 					if buffer[position] != rune('p') {
-						goto l196
+						goto l228
 					}
 					position++
-					if !_rules[ruleAction44]() {
-						goto l196
+					if !_rules[ruleAction45]() {
+						goto l228
 					}
 				}
-			l198:
-				add(rulerangeC, position197)
+			l230:
+				add(rulerangeC, position229)
 			}
 			return true
-		l196:
-			position, tokenIndex = position196, tokenIndex196
+		l228:
+			position, tokenIndex = position228, tokenIndex228
 			return false
 		},
-		/* 35 destC <- <(('m' Action45) / ('t' Action46))> */
+		/* 37 destC <- <(('m' Action46) / ('t' Action47))> */
 		func() bool {
-			position203, tokenIndex203 := position, tokenIndex
+			position235, tokenIndex235 := position, tokenIndex
 			{
-				position204 := position
+				position236 := position
 				{
-					position205, tokenIndex205 := position, tokenIndex
+					position237, tokenIndex237 := position, tokenIndex
 					if buffer[position] != rune('m') {
-						goto l206
+						goto l238
 					}
 					position++
-					if !_rules[ruleAction45]() {
-						goto l206
+					if !_rules[ruleAction46]() {
+						goto l238
 					}
-					goto l205
-				l206:
-					position, tokenIndex = position205, tokenIndex205
+					goto l237
+				l238:
+					position, tokenIndex = position237, tokenIndex237
 					if buffer[position] != rune('t') {
-						goto l203
+						goto l235
 					}
 					position++
-					if !_rules[ruleAction46]() {
-						goto l203
+					if !_rules[ruleAction47]() {
+						goto l235
 					}
 				}
-			l205:
-				add(ruledestC, position204)
+			l237:
+				add(ruledestC, position236)
 			}
 			return true
-		l203:
-			position, tokenIndex = position203, tokenIndex203
+		l235:
+			position, tokenIndex = position235, tokenIndex235
 			return false
 		},
-		/* 36 newLine <- <'\n'> */
+		/* 38 newLine <- <'\n'> */
 		func() bool {
-			position207, tokenIndex207 := position, tokenIndex
+			position239, tokenIndex239 := position, tokenIndex
 			{
-				position208 := position
+				position240 := position
 				if buffer[position] != rune('\n') {
-					goto l207
+					goto l239
 				}
 				position++
-				add(rulenewLine, position208)
+				add(rulenewLine, position240)
 			}
 			return true
-		l207:
-			position, tokenIndex = position207, tokenIndex207
+		l239:
+			position, tokenIndex = position239, tokenIndex239
 			return false
 		},
-		/* 37 sp <- <(' ' / '\t')+> */
+		/* 39 sp <- <(' ' / '\t')+> */
 		func() bool {
-			position209, tokenIndex209 := position, tokenIndex
+			position241, tokenIndex241 := position, tokenIndex
 			{
-				position210 := position
+				position242 := position
 				{
-					position213, tokenIndex213 := position, tokenIndex
+					position245, tokenIndex245 := position, tokenIndex
 					if buffer[position] != rune(' ') {
-						goto l214
+						goto l246
 					}
 					position++
-					goto l213
-				l214:
-					position, tokenIndex = position213, tokenIndex213
+					goto l245
+				l246:
+					position, tokenIndex = position245, tokenIndex245
 					if buffer[position] != rune('\t') {
-						goto l209
+						goto l241
 					}
 					position++
 				}
-			l213:
-			l211:
+			l245:
+			l243:
 				{
-					position212, tokenIndex212 := position, tokenIndex
+					position244, tokenIndex244 := position, tokenIndex
 					{
-						position215, tokenIndex215 := position, tokenIndex
+						position247, tokenIndex247 := position, tokenIndex
 						if buffer[position] != rune(' ') {
-							goto l216
+							goto l248
 						}
 						position++
-						goto l215
-					l216:
-						position, tokenIndex = position215, tokenIndex215
+						goto l247
+					l248:
+						position, tokenIndex = position247, tokenIndex247
 						if buffer[position] != rune('\t') {
-							goto l212
+							goto l244
 						}
 						position++
 					}
-				l215:
-					goto l211
-				l212:
-					position, tokenIndex = position212, tokenIndex212
+				l247:
+					goto l243
+				l244:
+					position, tokenIndex = position244, tokenIndex244
 				}
-				add(rulesp, position210)
+				add(rulesp, position242)
 			}
 			return true
-		l209:
-			position, tokenIndex = position209, tokenIndex209
+		l241:
+			position, tokenIndex = position241, tokenIndex241
 			return false
 		},
-		/* 39 Action0 <- <{ }> */
+		/* 41 Action0 <- <{ }> */
 		func() bool {
 			{
 				add(ruleAction0, position)
 			}
 			return true
 		},
-		/* 40 Action1 <- <{
+		/* 42 Action1 <- <{
 		  p.Out <- p.curCmd
 		  p.curCmd = Command{}
 		}> */
@@ -2249,7 +2469,7 @@ func (p *Parser) Init() {
 			return true
 		},
 		nil,
-		/* 42 Action2 <- <{
+		/* 44 Action2 <- <{
 		  p.curCmd.typ = ctmark
 		  p.curCmd.params = []string{buffer[begin:end]}
 		}> */
@@ -2259,14 +2479,14 @@ func (p *Parser) Init() {
 			}
 			return true
 		},
-		/* 43 Action3 <- <{p.curCmd.dest = p.curAddr ; p.curAddr = address{}}> */
+		/* 45 Action3 <- <{p.curCmd.dest = p.curAddr ; p.curAddr = address{}}> */
 		func() bool {
 			{
 				add(ruleAction3, position)
 			}
 			return true
 		},
-		/* 44 Action4 <- <{
+		/* 46 Action4 <- <{
 		  p.curCmd.typ = ctread
 		  p.curCmd.text = buffer[begin:end]
 		}> */
@@ -2276,7 +2496,7 @@ func (p *Parser) Init() {
 			}
 			return true
 		},
-		/* 45 Action5 <- <{
+		/* 47 Action5 <- <{
 		  p.curCmd.typ = ctwrite
 		  p.curCmd.text = buffer[begin:end]
 		}> */
@@ -2286,7 +2506,7 @@ func (p *Parser) Init() {
 			}
 			return true
 		},
-		/* 46 Action6 <- <{
+		/* 48 Action6 <- <{
 		  p.curCmd.typ = ctwrite
 		}> */
 		func() bool {
@@ -2295,8 +2515,8 @@ func (p *Parser) Init() {
 			}
 			return true
 		},
-		/* 47 Action7 <- <{
-		  p.curCmd.typ = ctshell
+		/* 49 Action7 <- <{
+		  p.curCmd.typ = ctsubstitute
 		  p.curCmd.text = buffer[begin:end]
 		}> */
 		func() bool {
@@ -2305,279 +2525,290 @@ func (p *Parser) Init() {
 			}
 			return true
 		},
-		/* 48 Action8 <- <{p.curCmd.text = buffer[begin:end]; fmt.Println("t", p.curCmd.text)}> */
+		/* 50 Action8 <- <{
+		  p.curCmd.typ = ctshell
+		  p.curCmd.text = buffer[begin:end]
+		}> */
 		func() bool {
 			{
 				add(ruleAction8, position)
 			}
 			return true
 		},
-		/* 49 Action9 <- <{p.curCmd.end = p.curCmd.start}> */
+		/* 51 Action9 <- <{p.curCmd.text = buffer[begin:end]; fmt.Println("t", p.curCmd.text)}> */
 		func() bool {
 			{
 				add(ruleAction9, position)
 			}
 			return true
 		},
-		/* 50 Action10 <- <{p.curCmd.start = aFirst}> */
+		/* 52 Action10 <- <{p.curCmd.end = p.curCmd.start}> */
 		func() bool {
 			{
 				add(ruleAction10, position)
 			}
 			return true
 		},
-		/* 51 Action11 <- <{p.curCmd.end = p.curCmd.start}> */
+		/* 53 Action11 <- <{p.curCmd.start = aFirst}> */
 		func() bool {
 			{
 				add(ruleAction11, position)
 			}
 			return true
 		},
-		/* 52 Action12 <- <{p.curCmd.start = aCur}> */
+		/* 54 Action12 <- <{p.curCmd.end = p.curCmd.start}> */
 		func() bool {
 			{
 				add(ruleAction12, position)
 			}
 			return true
 		},
-		/* 53 Action13 <- <{p.curCmd.end = p.curCmd.start}> */
+		/* 55 Action13 <- <{p.curCmd.start = aCur}> */
 		func() bool {
 			{
 				add(ruleAction13, position)
 			}
 			return true
 		},
-		/* 54 Action14 <- <{p.curCmd.start = aFirst; p.curCmd.end = aLast}> */
+		/* 56 Action14 <- <{p.curCmd.end = p.curCmd.start}> */
 		func() bool {
 			{
 				add(ruleAction14, position)
 			}
 			return true
 		},
-		/* 55 Action15 <- <{p.curCmd.start = aCur; p.curCmd.end = aLast}> */
+		/* 57 Action15 <- <{p.curCmd.start = aFirst; p.curCmd.end = aLast}> */
 		func() bool {
 			{
 				add(ruleAction15, position)
 			}
 			return true
 		},
-		/* 56 Action16 <- <{p.curCmd.start.text = buffer[begin:end]}> */
+		/* 58 Action16 <- <{p.curCmd.start = aCur; p.curCmd.end = aLast}> */
 		func() bool {
 			{
 				add(ruleAction16, position)
 			}
 			return true
 		},
-		/* 57 Action17 <- <{p.curCmd.start = p.curAddr; p.curAddr = address{}}> */
+		/* 59 Action17 <- <{p.curCmd.start.text = buffer[begin:end]}> */
 		func() bool {
 			{
 				add(ruleAction17, position)
 			}
 			return true
 		},
-		/* 58 Action18 <- <{p.curCmd.end = p.curAddr; p.curAddr = address{}}> */
+		/* 60 Action18 <- <{p.curCmd.start = p.curAddr; p.curAddr = address{}}> */
 		func() bool {
 			{
 				add(ruleAction18, position)
 			}
 			return true
 		},
-		/* 59 Action19 <- <{p.curAddr.text = buffer[begin:end]}> */
+		/* 61 Action19 <- <{p.curCmd.end = p.curAddr; p.curAddr = address{}}> */
 		func() bool {
 			{
 				add(ruleAction19, position)
 			}
 			return true
 		},
-		/* 60 Action20 <- <{p.curAddr.typ = lCurrent}> */
+		/* 62 Action20 <- <{p.curAddr.text = buffer[begin:end]}> */
 		func() bool {
 			{
 				add(ruleAction20, position)
 			}
 			return true
 		},
-		/* 61 Action21 <- <{p.curAddr.typ = lLast}> */
+		/* 63 Action21 <- <{p.curAddr.typ = lCurrent}> */
 		func() bool {
 			{
 				add(ruleAction21, position)
 			}
 			return true
 		},
-		/* 62 Action22 <- <{p.curAddr.typ = lNum}> */
+		/* 64 Action22 <- <{p.curAddr.typ = lLast}> */
 		func() bool {
 			{
 				add(ruleAction22, position)
 			}
 			return true
 		},
-		/* 63 Action23 <- <{ p.curAddr.typ = lMark }> */
+		/* 65 Action23 <- <{p.curAddr.typ = lNum}> */
 		func() bool {
 			{
 				add(ruleAction23, position)
 			}
 			return true
 		},
-		/* 64 Action24 <- <{p.curAddr.typ = lRegex}> */
+		/* 66 Action24 <- <{ p.curAddr.typ = lMark }> */
 		func() bool {
 			{
 				add(ruleAction24, position)
 			}
 			return true
 		},
-		/* 65 Action25 <- <{p.curAddr.typ = lRegexReverse}> */
+		/* 67 Action25 <- <{p.curAddr.typ = lRegex}> */
 		func() bool {
 			{
 				add(ruleAction25, position)
 			}
 			return true
 		},
-		/* 66 Action26 <- <{p.curCmd.typ = cthelp}> */
+		/* 68 Action26 <- <{p.curAddr.typ = lRegexReverse}> */
 		func() bool {
 			{
 				add(ruleAction26, position)
 			}
 			return true
 		},
-		/* 67 Action27 <- <{p.curCmd.typ = cthelpMode}> */
+		/* 69 Action27 <- <{p.curCmd.typ = cthelp}> */
 		func() bool {
 			{
 				add(ruleAction27, position)
 			}
 			return true
 		},
-		/* 68 Action28 <- <{p.curCmd.typ = ctprompt}> */
+		/* 70 Action28 <- <{p.curCmd.typ = cthelpMode}> */
 		func() bool {
 			{
 				add(ruleAction28, position)
 			}
 			return true
 		},
-		/* 69 Action29 <- <{p.curCmd.typ = ctquit}> */
+		/* 71 Action29 <- <{p.curCmd.typ = ctprompt}> */
 		func() bool {
 			{
 				add(ruleAction29, position)
 			}
 			return true
 		},
-		/* 70 Action30 <- <{p.curCmd.typ = ctquitForce}> */
+		/* 72 Action30 <- <{p.curCmd.typ = ctquit}> */
 		func() bool {
 			{
 				add(ruleAction30, position)
 			}
 			return true
 		},
-		/* 71 Action31 <- <{p.curCmd.typ = ctundo}> */
+		/* 73 Action31 <- <{p.curCmd.typ = ctquitForce}> */
 		func() bool {
 			{
 				add(ruleAction31, position)
 			}
 			return true
 		},
-		/* 72 Action32 <- <{ p.curCmd.params = []string{buffer[begin:end]}}> */
+		/* 74 Action32 <- <{p.curCmd.typ = ctundo}> */
 		func() bool {
 			{
 				add(ruleAction32, position)
 			}
 			return true
 		},
-		/* 73 Action33 <- <{p.curCmd.typ = ctedit}> */
+		/* 75 Action33 <- <{ p.curCmd.params = []string{buffer[begin:end]}}> */
 		func() bool {
 			{
 				add(ruleAction33, position)
 			}
 			return true
 		},
-		/* 74 Action34 <- <{p.curCmd.typ = cteditForce}> */
+		/* 76 Action34 <- <{p.curCmd.typ = ctedit}> */
 		func() bool {
 			{
 				add(ruleAction34, position)
 			}
 			return true
 		},
-		/* 75 Action35 <- <{p.curCmd.typ = ctfilename}> */
+		/* 77 Action35 <- <{p.curCmd.typ = cteditForce}> */
 		func() bool {
 			{
 				add(ruleAction35, position)
 			}
 			return true
 		},
-		/* 76 Action36 <- <{p.curCmd.typ = ctlineNumber}> */
+		/* 78 Action36 <- <{p.curCmd.typ = ctfilename}> */
 		func() bool {
 			{
 				add(ruleAction36, position)
 			}
 			return true
 		},
-		/* 77 Action37 <- <{p.curCmd.typ = ctchange}> */
+		/* 79 Action37 <- <{p.curCmd.typ = ctlineNumber}> */
 		func() bool {
 			{
 				add(ruleAction37, position)
 			}
 			return true
 		},
-		/* 78 Action38 <- <{p.curCmd.typ = ctappend}> */
+		/* 80 Action38 <- <{p.curCmd.typ = ctchange}> */
 		func() bool {
 			{
 				add(ruleAction38, position)
 			}
 			return true
 		},
-		/* 79 Action39 <- <{p.curCmd.typ = ctinsert}> */
+		/* 81 Action39 <- <{p.curCmd.typ = ctappend}> */
 		func() bool {
 			{
 				add(ruleAction39, position)
 			}
 			return true
 		},
-		/* 80 Action40 <- <{p.curCmd.typ = ctdelete}> */
+		/* 82 Action40 <- <{p.curCmd.typ = ctinsert}> */
 		func() bool {
 			{
 				add(ruleAction40, position)
 			}
 			return true
 		},
-		/* 81 Action41 <- <{p.curCmd.typ = ctjoin}> */
+		/* 83 Action41 <- <{p.curCmd.typ = ctdelete}> */
 		func() bool {
 			{
 				add(ruleAction41, position)
 			}
 			return true
 		},
-		/* 82 Action42 <- <{p.curCmd.typ = ctlist}> */
+		/* 84 Action42 <- <{p.curCmd.typ = ctjoin}> */
 		func() bool {
 			{
 				add(ruleAction42, position)
 			}
 			return true
 		},
-		/* 83 Action43 <- <{p.curCmd.typ = ctnumber}> */
+		/* 85 Action43 <- <{p.curCmd.typ = ctlist}> */
 		func() bool {
 			{
 				add(ruleAction43, position)
 			}
 			return true
 		},
-		/* 84 Action44 <- <{p.curCmd.typ = ctprint}> */
+		/* 86 Action44 <- <{p.curCmd.typ = ctnumber}> */
 		func() bool {
 			{
 				add(ruleAction44, position)
 			}
 			return true
 		},
-		/* 85 Action45 <- <{p.curCmd.typ = ctmove}> */
+		/* 87 Action45 <- <{p.curCmd.typ = ctprint}> */
 		func() bool {
 			{
 				add(ruleAction45, position)
 			}
 			return true
 		},
-		/* 86 Action46 <- <{p.curCmd.typ = ctcopy}> */
+		/* 88 Action46 <- <{p.curCmd.typ = ctmove}> */
 		func() bool {
 			{
 				add(ruleAction46, position)
 			}
 			return true
 		},
+		/* 89 Action47 <- <{p.curCmd.typ = ctcopy}> */
+		func() bool {
+			{
+				add(ruleAction47, position)
+			}
+			return true
+		},
 	}
 	p.rules = _rules
+	return nil
 }
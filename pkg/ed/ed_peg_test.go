@@ -163,3 +163,33 @@ func TestMiscCommandParser(t *testing.T) {
 		})
 	}
 }
+
+func TestSubstituteCommandParser(t *testing.T) {
+	var pSubstituteCmds = []struct {
+		cmd  string
+		args string
+	}{
+		{"s/a/b/", "/a/b/"},
+		{"1,$s/a/b/g", "/a/b/g"},
+		{"/x/s.a.b.", ".a.b."},
+		{"2;.+1s|a|b|3p", "|a|b|3p"},
+		{",s/a b/c d/", "/a b/c d/"},
+	}
+	for _, tt := range pSubstituteCmds {
+		t.Run("substituteParse:"+tt.cmd, func(t *testing.T) {
+			cmds, err := parse(tt.cmd + "\n")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(cmds) != 1 || cmds[0].typ != ctsubstitute || cmds[0].text != tt.args {
+				t.Errorf("got %+v", cmds)
+			}
+		})
+	}
+
+	for _, s := range []string{"s", "s /a/b/", "1,2,s/a/b/"} {
+		if _, err := parse(s + "\n"); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+}
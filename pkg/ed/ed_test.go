@@ -101,6 +101,36 @@ var e2etests = []e2etest{
 		"b",
 		"regex works",
 	},
+	{"1,2s/[ab]/x&/",
+		abc,
+		"xa\nxb\nc",
+		"",
+		"substitute in a range",
+	},
+	{"s/\\(c\\)\\1*/&\\1/g\n1s//z/",
+		"a\nb\ncc",
+		"a\nb\nccc",
+		"",
+		"substitute with back-references, and a failed substitution",
+	},
+	{"1s/a/b/p",
+		abc,
+		"b\nb\nc",
+		"b",
+		"substitute and print",
+	},
+	{"s.a\\.b.x.",
+		"a.b axb",
+		"x axb",
+		"",
+		"substitute with an escaped delimiter that is special",
+	},
+	{"2l",
+		"a\nb\tc\nd",
+		"",
+		"b\\tc$",
+		"list a line",
+	},
 }
 
 func TestEndToEnd(t *testing.T) {
@@ -129,3 +159,24 @@ func TestEndToEnd(t *testing.T) {
 		})
 	}
 }
+
+func TestPrompt(t *testing.T) {
+	run := func(ed *Itor, cmds string) string {
+		ed.pt = txt.NewPieceTable(strings.NewReader("a\nb"), 3)
+		output := &strings.Builder{}
+		ed.ProcessCommands(strings.NewReader(cmds), output)
+		return output.String()
+	}
+
+	ed := NewEditor(nil, nil)
+	ed.SetPrompt("> ")
+	if got, want := run(ed, "1p\n2p\n"), "> a\n> b\n> "; got != want {
+		t.Errorf("-p: got %q, expected %q", got, want)
+	}
+
+	// P turns on the default prompt
+	ed = NewEditor(nil, nil)
+	if got := run(ed, "1p\nP\n2p\n"); !strings.HasPrefix(got, "a\n") || !strings.HasSuffix(got, "*b\n*") {
+		t.Errorf("P: got %q", got)
+	}
+}
@@ -0,0 +1,59 @@
+package ed
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ListWidth is where the l command of ed and sed folds long lines
+const ListWidth = 72
+
+// escapes are the characters the l command writes as C escapes
+var escapes = map[rune]string{
+	'\\': `\\`,
+	'\a': `\a`,
+	'\b': `\b`,
+	'\f': `\f`,
+	'\n': `\n`,
+	'\r': `\r`,
+	'\t': `\t`,
+	'\v': `\v`,
+}
+
+// List writes a line unambiguously, as the l command of ed and sed does.
+// Characters with C escapes are written with them, and other
+// non-printable bytes as three-digit octal escapes. A $ marks the end of
+// the line. If width is positive, long lines are folded with a \ so that
+// none is longer than width.
+func List(line string, width int) string {
+	var b strings.Builder
+	col := 0
+	write := func(s string) {
+		if width > 1 && col+utf8.RuneCountInString(s) > width-1 {
+			b.WriteString("\\\n")
+			col = 0
+		}
+		b.WriteString(s)
+		col += utf8.RuneCountInString(s)
+	}
+	for i := 0; i < len(line); {
+		r, size := utf8.DecodeRuneInString(line[i:])
+		switch e, ok := escapes[r]; {
+		case ok:
+			write(e)
+		case r == utf8.RuneError && size == 1, !unicode.IsPrint(r):
+			var esc strings.Builder
+			for _, c := range []byte(line[i : i+size]) {
+				fmt.Fprintf(&esc, "\\%03o", c)
+			}
+			write(esc.String())
+		default:
+			write(line[i : i+size])
+		}
+		i += size
+	}
+	b.WriteString("$")
+	return b.String()
+}
@@ -0,0 +1,186 @@
+package ed
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fwip/posix-utils/pkg/regexes"
+)
+
+// Substitution is what the s command of ed and sed does to a line: replace
+// matches of a regular expression with a replacement
+type Substitution struct {
+	Regexp *regexes.Regexp
+	// Replacement may contain & for the match, \1 to \9 for subexpressions,
+	// and \ before any character, including a newline, for itself
+	Replacement string
+	Global      bool // Replace every match, from the Occurrence'th
+	Occurrence  int  // Which match to replace; 0 means the first
+}
+
+// ReadSubstitution splits the arguments of an s command, starting from the
+// delimiter that follows the s, into the pattern and the replacement. It
+// returns the text after the closing delimiter, where the flags are.
+//
+// A delimiter escaped with a backslash stands for itself, and one inside a
+// bracket expression needs no escape. Other escapes are left for the
+// regular expression and the replacement.
+func ReadSubstitution(s string) (pattern, replacement, rest string, err error) {
+	delim, size := utf8.DecodeRuneInString(s)
+	if s == "" || delim == '\\' || delim == '\n' || delim == ' ' {
+		return "", "", s, errors.New("invalid delimiter")
+	}
+	if pattern, rest, err = ReadDelimited(s[size:], delim, true); err != nil {
+		return "", "", s, err
+	}
+	if replacement, rest, err = ReadDelimited(rest, delim, false); err != nil {
+		return "", "", s, err
+	}
+	return pattern, replacement, rest, nil
+}
+
+// ReadDelimited returns s up to the first unescaped delim, and what follows
+// the delim. When re is true, s is a basic regular expression, so a delim
+// inside a bracket expression is part of it, and an escaped delim that is
+// special in the expression keeps its backslash, to stay a literal
+// character. An unescaped newline is an error.
+func ReadDelimited(s string, delim rune, re bool) (field, rest string, err error) {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == delim:
+			return b.String(), s[i+size:], nil
+		case r == '\n':
+			return "", s, fmt.Errorf("unterminated expression")
+		case r == '\\' && i+1 < len(s):
+			e, esize := utf8.DecodeRuneInString(s[i+1:])
+			// A delimiter that means something unescaped keeps its
+			// backslash, so that it's still the literal character
+			if e == delim && !strings.ContainsRune(special(re), e) {
+				b.WriteRune(e)
+			} else {
+				b.WriteString(s[i : i+1+esize])
+			}
+			i += 1 + esize
+			continue
+		case r == '[' && re:
+			end := bracketEnd(s, i)
+			if end < 0 {
+				return "", s, fmt.Errorf("unterminated bracket expression")
+			}
+			b.WriteString(s[i:end])
+			i = end
+			continue
+		}
+		b.WriteRune(r)
+		i += size
+	}
+	return "", s, fmt.Errorf("unterminated expression")
+}
+
+// special returns the characters that mean something unescaped in a basic
+// regular expression, or in a replacement
+func special(re bool) string {
+	if re {
+		return `.[]*^$`
+	}
+	return `&`
+}
+
+// bracketEnd returns the index after the bracket expression that starts at
+// s[i], or -1 if it doesn't end on this line
+func bracketEnd(s string, i int) int {
+	j := i + 1
+	if j < len(s) && s[j] == '^' {
+		j++
+	}
+	if j < len(s) && s[j] == ']' {
+		j++
+	}
+	for j < len(s) && s[j] != '\n' {
+		switch {
+		case s[j] == ']':
+			return j + 1
+		case s[j] == '[' && j+1 < len(s) && strings.IndexByte(":.=", s[j+1]) >= 0:
+			end := strings.Index(s[j+2:], string(s[j+1])+"]")
+			if end < 0 {
+				return -1
+			}
+			j += 2 + end + 2
+		default:
+			j++
+		}
+	}
+	return -1
+}
+
+// Check reports a replacement that refers to a subexpression the regular
+// expression doesn't have
+func (sub *Substitution) Check() error {
+	r := sub.Replacement
+	for i := 0; i < len(r)-1; i++ {
+		if r[i] != '\\' {
+			continue
+		}
+		i++
+		if n := int(r[i] - '0'); r[i] >= '1' && r[i] <= '9' && n > sub.Regexp.NumSubexp() {
+			return fmt.Errorf("invalid reference \\%d on s command's RHS", n)
+		}
+	}
+	return nil
+}
+
+// Apply makes the substitution in line, and reports whether it replaced
+// anything
+func (sub *Substitution) Apply(line string) (string, bool) {
+	n := -1
+	if !sub.Global {
+		n = sub.Occurrence
+		if n < 1 {
+			n = 1
+		}
+	}
+	matches := sub.Regexp.FindAllStringSubmatchIndex(line, n)
+	first := sub.Occurrence - 1
+	if first < 0 {
+		first = 0
+	}
+	if len(matches) <= first {
+		return line, false
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches[first:] {
+		b.WriteString(line[last:m[0]])
+		sub.expand(&b, line, m)
+		last = m[1]
+	}
+	b.WriteString(line[last:])
+	return b.String(), true
+}
+
+// expand writes the replacement for the match m of line
+func (sub *Substitution) expand(b *strings.Builder, line string, m []int) {
+	r := sub.Replacement
+	for i := 0; i < len(r); i++ {
+		switch {
+		case r[i] == '&':
+			b.WriteString(line[m[0]:m[1]])
+		case r[i] == '\\' && i+1 < len(r):
+			i++
+			if r[i] >= '1' && r[i] <= '9' {
+				n := int(r[i] - '0')
+				if 2*n+1 < len(m) && m[2*n] >= 0 {
+					b.WriteString(line[m[2*n]:m[2*n+1]])
+				}
+				continue
+			}
+			b.WriteByte(r[i])
+		default:
+			b.WriteByte(r[i])
+		}
+	}
+}
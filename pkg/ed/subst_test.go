@@ -0,0 +1,98 @@
+package ed
+
+import (
+	"testing"
+
+	"github.com/fwip/posix-utils/pkg/regexes"
+)
+
+func TestReadSubstitution(t *testing.T) {
+	tests := []struct {
+		args                        string
+		pattern, replacement, flags string
+	}{
+		{"/a/b/g", "a", "b", "g"},
+		{"/a\\/b/c\\/d/", "a/b", "c/d", ""},
+		{"|[|]|x|", "[|]", "x", ""},
+		{"/[]/]/x/", "[]/]", "x", ""},
+		{"/[[:alpha:]/]/x/", "[[:alpha:]/]", "x", ""},
+		{`/a\.\(b\)/\1\&/p`, `a\.\(b\)`, `\1\&`, "p"},
+		{`&a&\&&`, "a", `\&`, ""},
+		{"/a/b\\\nc/", "a", "b\\\nc", ""},
+		{`.a\.b.x.`, `a\.b`, "x", ""},
+		{`*a\*\**\**`, `a\*\*`, `*`, ""},
+		{`$a\$$b$`, `a\$`, "b", ""},
+		{`|a\|b|\||`, "a|b", "|", ""},
+	}
+	for _, tt := range tests {
+		pattern, replacement, flags, err := ReadSubstitution(tt.args)
+		if err != nil || pattern != tt.pattern || replacement != tt.replacement || flags != tt.flags {
+			t.Errorf("%q: got %q, %q, %q, %v", tt.args, pattern, replacement, flags, err)
+		}
+	}
+	for _, args := range []string{"", " a b ", "\\a\\b\\", "/a/b", "/a", "/[/]", "/a\n/b/"} {
+		if _, _, _, err := ReadSubstitution(args); err == nil {
+			t.Errorf("%q: expected an error", args)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		pattern, replacement string
+		global               bool
+		occurrence           int
+		line, want           string
+	}{
+		{"o", "0", false, 0, "foo boo", "f0o boo"},
+		{"o", "0", true, 0, "foo boo", "f00 b00"},
+		{"o", "0", false, 3, "foo boo", "foo b0o"},
+		{"o", "0", true, 2, "foo boo", "fo0 b00"},
+		{"x*", "-", true, 0, "abc", "-a-b-c-"},
+		{"b*", "-", true, 0, "abbc", "-a-c-"},
+		{`\([a-z]*\) \([a-z]*\)`, `\2 \1`, false, 0, "hello world", "world hello"},
+		{"o", `[&][\&][\\]`, false, 0, "o", `[o][&][\]`},
+		{"b", "\\\n", false, 0, "abc", "a\nc"},
+		{`\(a\)\|b`, `\1`, false, 0, "a|b", "a"},
+		{`\(x\)*y`, `[\1]`, false, 0, "y", "[]"},
+		{"z", "-", true, 0, "abc", "abc"},
+	}
+	for _, tt := range tests {
+		sub := &Substitution{regexes.MustCompile(tt.pattern, 0), tt.replacement, tt.global, tt.occurrence}
+		got, ok := sub.Apply(tt.line)
+		if got != tt.want || ok != (tt.line != tt.want) {
+			t.Errorf("s/%s/%s/ on %q: got %q, %v, expected %q", tt.pattern, tt.replacement, tt.line, got, ok, tt.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	sub := &Substitution{Regexp: regexes.MustCompile(`\(a\)`, 0), Replacement: `\1`}
+	if err := sub.Check(); err != nil {
+		t.Error(err)
+	}
+	sub.Replacement = `\\2\2`
+	if err := sub.Check(); err == nil {
+		t.Errorf("%q: expected an error", sub.Replacement)
+	}
+}
+
+func TestList(t *testing.T) {
+	tests := []struct {
+		line  string
+		width int
+		want  string
+	}{
+		{"abc", 0, "abc$"},
+		{"a\tb\\c\a\b\f\r\v\n", 0, `a\tb\\c\a\b\f\r\v\n$`},
+		{"\x01\x7f\xff", 0, `\001\177\377$`},
+		{"héllo", 0, "héllo$"},
+		{"abcdefgh", 5, "abcd\\\nefgh$"},
+		{"ab\tcd", 4, "ab\\\n\\tc\\\nd$"},
+	}
+	for _, tt := range tests {
+		if got := List(tt.line, tt.width); got != tt.want {
+			t.Errorf("%q: got %q, expected %q", tt.line, got, tt.want)
+		}
+	}
+}
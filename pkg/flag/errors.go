@@ -0,0 +1,54 @@
+package flag
+
+import "fmt"
+
+// The errors are worded as getopts reports them, to be shown after the name
+// of the utility and a colon.
+
+// ErrUnknownOption indicates an option that wasn't registered
+type ErrUnknownOption struct {
+	Option rune
+}
+
+func (e ErrUnknownOption) Error() string {
+	return fmt.Sprintf("illegal option -- %c", e.Option)
+}
+
+// ErrMissingArgument indicates an option that takes an option-argument came
+// last, without one
+type ErrMissingArgument struct {
+	Option rune
+}
+
+func (e ErrMissingArgument) Error() string {
+	return fmt.Sprintf("option requires an argument -- %c", e.Option)
+}
+
+// ErrNonInt indicates a non-integer value passed to an integer flag
+type ErrNonInt struct {
+	Option rune
+	Value  string
+}
+
+func (e ErrNonInt) Error() string {
+	return fmt.Sprintf("option -%c requires an integer, not %q", e.Option, e.Value)
+}
+
+// ErrInvalidValue indicates an option-argument that the option's Value
+// rejected. Option is 0 for a numeric option, and the message is then the
+// Value's own.
+type ErrInvalidValue struct {
+	Option rune
+	Value  string
+	Err    error
+}
+
+func (e ErrInvalidValue) Error() string {
+	if e.Option == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("option -%c: %s", e.Option, e.Err)
+}
+
+// ErrBadBundle indicates a misuse of bundling options
+type ErrBadBundle error
@@ -1,11 +1,24 @@
 package flag
 
-import "strconv"
+import (
+	"strconv"
+	"strings"
+)
 
-// TODO: Is this right?
-//type flag interface {
-//execute(s string) error
-//}
+// Value is where an option's value goes. Set is called with the
+// option-argument each time the option appears, or with "" for an option
+// that doesn't take one.
+type Value interface {
+	Set(s string) error
+	String() string
+}
+
+// BoolValue is a Value for an option that takes no option-argument, which
+// is the case if IsBoolFlag returns true
+type BoolValue interface {
+	Value
+	IsBoolFlag() bool
+}
 
 type stringFlag struct {
 	dest *string
@@ -19,21 +32,61 @@ type intFlag struct {
 	dest *int64
 }
 
-// ErrNonInt indicates a non-integer value passed to an integer flag
-type ErrNonInt error
+// stringsFlag collects each option-argument of a repeated option
+type stringsFlag struct {
+	dest *[]string
+}
+
+// countFlag counts how many times an option appears
+type countFlag struct {
+	dest *int
+}
 
-func (bflag boolFlag) execute() {
+func (bflag boolFlag) Set(string) error {
 	*(bflag.dest) = true
+	return nil
 }
-func (iflag intFlag) execute(s string) error {
+
+func (bflag boolFlag) String() string { return strconv.FormatBool(*bflag.dest) }
+
+func (bflag boolFlag) IsBoolFlag() bool { return true }
+
+func (iflag intFlag) Set(s string) error {
 	x, err := strconv.ParseInt(s, 0, 64)
 	if err != nil {
-		return ErrNonInt(err)
+		return err
 	}
 	*(iflag.dest) = x
 	return nil
 }
 
-func (sflag stringFlag) execute(s string) {
+func (iflag intFlag) String() string { return strconv.FormatInt(*iflag.dest, 10) }
+
+func (sflag stringFlag) Set(s string) error {
 	*(sflag.dest) = s
+	return nil
+}
+
+func (sflag stringFlag) String() string { return *sflag.dest }
+
+func (sflag stringsFlag) Set(s string) error {
+	*(sflag.dest) = append(*(sflag.dest), s)
+	return nil
+}
+
+func (sflag stringsFlag) String() string { return strings.Join(*sflag.dest, " ") }
+
+func (cflag countFlag) Set(string) error {
+	*(cflag.dest)++
+	return nil
+}
+
+func (cflag countFlag) String() string { return strconv.Itoa(*cflag.dest) }
+
+func (cflag countFlag) IsBoolFlag() bool { return true }
+
+// isBool reports whether v takes no option-argument
+func isBool(v Value) bool {
+	b, ok := v.(BoolValue)
+	return ok && b.IsBoolFlag()
 }
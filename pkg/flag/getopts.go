@@ -0,0 +1,112 @@
+package flag
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Getopter steps through options the way the shell's getopts does, one call
+// to Next at a time, for callers that keep their own state between calls
+type Getopter struct {
+	// Optstring lists the option characters, each followed by ':' if it takes
+	// an option-argument. A leading ':' selects silent error reporting.
+	Optstring string
+	Args      []string
+
+	// Optind is the index, counting from 1, of the next argument to examine
+	Optind int
+	// Optpos is the byte offset of the next option within a bundle, such as
+	// "-abc", in the argument at Optind, or 0 between arguments
+	Optpos int
+
+	// Optarg is the value of OPTARG after the last call to Next, which is
+	// unset unless HasOptarg is true
+	Optarg    string
+	HasOptarg bool
+	// Err is the diagnostic getopts would write for the last call to Next,
+	// or nil. There never is one with silent error reporting.
+	Err error
+
+	// lastOptind notices a caller moving Optind, which restarts bundles
+	lastOptind int
+}
+
+// Getopts returns a Getopter for args, starting at optind, which is normally 1
+func Getopts(optstring string, args []string, optind int) *Getopter {
+	return &Getopter{Optstring: optstring, Args: args, Optind: optind, lastOptind: optind}
+}
+
+// Next finds the next option. It returns the option character and true, or
+// '?' and false at the end of the options, when Optind is left at the first
+// operand.
+// An option that isn't in Optstring is reported as '?'. One missing its
+// option-argument is reported as '?', or as ':' with silent error reporting.
+// With silent error reporting, Optarg is then set to the option character.
+func (g *Getopter) Next() (name rune, ok bool) {
+	g.Optarg, g.HasOptarg, g.Err = "", false, nil
+	if g.Optind != g.lastOptind || g.Optind < 1 {
+		g.Optpos = 0
+	}
+	if g.Optind < 1 {
+		g.Optind = 1
+	}
+	defer func() { g.lastOptind = g.Optind }()
+
+	if g.Optpos == 0 {
+		if g.Optind > len(g.Args) {
+			return '?', false
+		}
+		arg := g.Args[g.Optind-1]
+		if arg == "--" {
+			g.Optind++
+			return '?', false
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			return '?', false
+		}
+		g.Optpos = 1
+	}
+
+	arg := g.Args[g.Optind-1]
+	c, size := utf8.DecodeRuneInString(arg[g.Optpos:])
+	g.Optpos += size
+	rest := arg[g.Optpos:]
+	if rest == "" {
+		g.Optind++
+		g.Optpos = 0
+	}
+
+	silent := strings.HasPrefix(g.Optstring, ":")
+	opts := strings.TrimPrefix(g.Optstring, ":")
+	i := strings.IndexRune(opts, c)
+	if c == ':' || i < 0 {
+		if silent {
+			g.Optarg, g.HasOptarg = string(c), true
+		} else {
+			g.Err = ErrUnknownOption{c}
+		}
+		return '?', true
+	}
+	if !strings.HasPrefix(opts[i+size:], ":") {
+		return c, true
+	}
+
+	// The option-argument is the rest of this argument, or the next
+	if rest != "" {
+		g.Optind++
+		g.Optpos = 0
+	} else {
+		if g.Optind > len(g.Args) {
+			if silent {
+				g.Optarg, g.HasOptarg = string(c), true
+				return ':', true
+			}
+			g.Err = ErrMissingArgument{c}
+			return '?', true
+		}
+		rest = g.Args[g.Optind-1]
+		g.Optind++
+	}
+	g.Optarg, g.HasOptarg = rest, true
+	return c, true
+}
@@ -0,0 +1,89 @@
+package flag
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var getoptsTests = []struct {
+	optstring string
+	args      string
+	want      []string // Each option, then "=" and its argument, then "!" and any diagnostic
+	optind    int      // At the end
+}{
+	{"ab", "", nil, 1},
+	{"ab", "file", nil, 1},
+	{"ab", "-a -b file", []string{"a", "b"}, 3},
+	{"ab", "-ab file", []string{"a", "b"}, 2},
+	{"ab", "-a -- -b", []string{"a"}, 3},
+	{"ab", "-a - -b", []string{"a"}, 2},
+	{"ab:", "-b value -a", []string{"b=value", "a"}, 4},
+	{"ab:", "-abvalue file", []string{"a", "b=value"}, 2},
+	{"ab:", "-b -a", []string{"b=-a"}, 3},
+	{"ab", "-ax -b", []string{"a", "?!illegal option -- x", "b"}, 3},
+	{":ab", "-ax -b", []string{"a", "?=x", "b"}, 3},
+	{"ab:", "-a -b", []string{"a", "?!option requires an argument -- b"}, 3},
+	{":ab:", "-ab", []string{"a", ":=b"}, 2},
+	{"a:b", "-a: -:", []string{"a=:", "?!illegal option -- :"}, 3},
+}
+
+func TestGetopts(t *testing.T) {
+	for _, tt := range getoptsTests {
+		g := Getopts(tt.optstring, strings.Fields(tt.args), 1)
+		var got []string
+		for {
+			c, ok := g.Next()
+			if !ok {
+				if c != '?' || g.HasOptarg || g.Err != nil {
+					t.Errorf("%q %q: bad end: %c %v %v", tt.optstring, tt.args, c, g.HasOptarg, g.Err)
+				}
+				break
+			}
+			s := string(c)
+			if g.HasOptarg {
+				s += "=" + g.Optarg
+			}
+			if g.Err != nil {
+				s += "!" + g.Err.Error()
+			}
+			got = append(got, s)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q %q: expected %q, got %q", tt.optstring, tt.args, tt.want, got)
+		}
+		if g.Optind != tt.optind {
+			t.Errorf("%q %q: expected OPTIND %d, got %d", tt.optstring, tt.args, tt.optind, g.Optind)
+		}
+	}
+}
+
+func TestGetoptsResetOptind(t *testing.T) {
+	g := Getopts("abc", []string{"-abc", "-b"}, 1)
+	g.Next()
+	g.Next()
+	g.Next()
+	g.Next()
+	g.Optind = 1
+	if c, _ := g.Next(); c != 'a' {
+		t.Errorf("resetting OPTIND: expected a, got %c", c)
+	}
+}
+
+func ExampleGetopts() {
+	g := Getopts("vo:", []string{"-vo", "out", "file"}, 1)
+	for {
+		c, ok := g.Next()
+		if !ok {
+			break
+		}
+		fmt.Printf("%c %q\n", c, g.Optarg)
+	}
+	fmt.Println("operands:", g.Args[g.Optind-1:])
+
+	// Output:
+	// v ""
+	// o "out"
+	// operands: [file]
+}
@@ -3,20 +3,51 @@ package flag
 import (
 	"fmt"
 	"os"
+	"unicode/utf8"
 )
 
 // Parser does the hard work of assigning parses
 type Parser struct {
 	Input   []string
-	bools   map[rune]boolFlag
-	strings map[rune]stringFlag
-	ints    map[rune]intFlag
+	options map[rune]option
+	number  *option
 }
 
-// ErrBadBundle indicates a misuse of bundling options
-type ErrBadBundle error
+// How an option takes an option-argument
+const (
+	noArg = iota
+	requiredArg
+	optionalArg
+)
+
+// option is a registered option
+type option struct {
+	name  rune
+	value Value
+	arg   int
+	usage string
+}
 
-// Parse does the parsing, returns the positional arguments and any error that occurred
+// isNumber reports whether arg is a sign followed by digits, like "-5" or
+// "+10"
+func isNumber(arg string) bool {
+	if len(arg) < 2 || (arg[0] != '-' && arg[0] != '+') {
+		return false
+	}
+	for _, c := range arg[1:] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Parse does the parsing, following the Utility Syntax Guidelines, and
+// returns the operands that follow the options. Options may be bundled, and
+// an option-argument may follow its option in the same argument or be the
+// next one. The options end at "--", at "-", or at the first argument that
+// doesn't start with "-". With NumberVar, an argument like "-5" or "+10" is
+// an option too.
 // If Input is nil, Parse() will use os.Args[1:]
 func (p *Parser) Parse() (positionalArgs []string, err error) {
 	if p.Input == nil {
@@ -25,82 +56,122 @@ func (p *Parser) Parse() (positionalArgs []string, err error) {
 	for i := 0; i < len(p.Input); i++ {
 		arg := p.Input[i]
 
-		// Non-argument ends options
-		if len(arg) == 0 || arg[0] != '-' || arg == "-" {
-			return p.Input[i:], nil
-		}
 		// '--' explicitly ends options
 		if arg == "--" {
 			return p.Input[i+1:], nil
 		}
+		if p.number != nil && isNumber(arg) {
+			if err := p.number.value.Set(arg); err != nil {
+				return nil, ErrInvalidValue{Value: arg, Err: err}
+			}
+			continue
+		}
+		// Non-argument ends options
+		if len(arg) < 2 || arg[0] != '-' {
+			return p.Input[i:], nil
+		}
 
 		// Bundling is allowed, so check each character to apply it
-		for j, c := range arg[1:] {
-			// Check for boolean flag.
-			if flag, ok := p.bools[c]; ok {
-				flag.execute()
+		for j := 1; j < len(arg); {
+			c, size := utf8.DecodeRuneInString(arg[j:])
+			j += size
+			opt, ok := p.options[c]
+			if !ok {
+				return nil, ErrUnknownOption{c}
+			}
+			if opt.arg == noArg {
+				if err := opt.value.Set(""); err != nil {
+					return nil, ErrInvalidValue{c, "", err}
+				}
 				continue
 			}
 
-			// Argument may be either bundled or separate
-			value := arg[j+2:]
-			if len(value) == 0 {
+			// The option-argument is the rest of this argument, or the next,
+			// unless it's optional, in which case it can only be the rest
+			value := arg[j:]
+			if value == "" && opt.arg == requiredArg {
 				i++
+				if i == len(p.Input) {
+					return nil, ErrMissingArgument{c}
+				}
 				value = p.Input[i]
 			}
-
-			if flag, ok := p.strings[c]; ok {
-				flag.execute(value)
-				continue
-			}
-			if flag, ok := p.ints[c]; ok {
-				err := flag.execute(value)
-				if err != nil {
-					return nil, err
+			if err := opt.value.Set(value); err != nil {
+				if _, ok := opt.value.(intFlag); ok {
+					return nil, ErrNonInt{c, value}
 				}
-				continue
+				return nil, ErrInvalidValue{c, value, err}
 			}
-			return nil, fmt.Errorf("man what's happening")
-			//// non-boolean flags may only be bundled if they are the last
-			//if j != len(arg)-2 {
-			//	return nil, ErrBadBundle(fmt.Errorf("inappropriate bundling of %c in %s", c, arg))
-			//}
-			//i++
-			//value := p.Input[i]
-
-			//if flag, ok := p.strings[c]; ok {
-			//	flag.execute(value)
-			//} else if flag, ok := p.ints[c]; ok {
-			//	err := flag.execute(value)
-			//	if err != nil {
-			//		return nil, err
-			//	}
-			//}
+			break
 		}
 	}
-	return nil, nil
+	return p.Input[len(p.Input):], nil
 }
 
-// BoolVar creates a new boolean variable
-func (p *Parser) BoolVar(addr *bool, name rune) {
-	if p.bools == nil {
-		p.bools = make(map[rune]boolFlag)
+// add registers an option. Registering the same one twice is a mistake in
+// the program, so it panics.
+func (p *Parser) add(name rune, v Value, arg int, usage string) {
+	if p.options == nil {
+		p.options = make(map[rune]option)
 	}
-	p.bools[name] = boolFlag{addr}
+	if _, ok := p.options[name]; ok {
+		panic(fmt.Sprintf("flag: -%c registered twice", name))
+	}
+	p.options[name] = option{name, v, arg, usage}
 }
 
-// IntVar creates a new int64 variable
-func (p *Parser) IntVar(addr *int64, name rune) {
-	if p.ints == nil {
-		p.ints = make(map[rune]intFlag)
+// Var registers an option that sets a Value. It takes an option-argument
+// unless the Value is a BoolValue whose IsBoolFlag returns true.
+// usage describes it; see Usage.
+func (p *Parser) Var(v Value, name rune, usage string) {
+	arg := requiredArg
+	if isBool(v) {
+		arg = noArg
 	}
-	p.ints[name] = intFlag{addr}
+	p.add(name, v, arg, usage)
+}
+
+// OptionalVar registers an option whose option-argument is optional. As the
+// guidelines require, it must then be in the same argument as the option,
+// like "-xvalue"; on its own, the option sets v to "".
+func (p *Parser) OptionalVar(v Value, name rune, usage string) {
+	p.add(name, v, optionalArg, usage)
 }
 
-// StringVar creates a new int64 variable
-func (p *Parser) StringVar(addr *string, name rune) {
-	if p.strings == nil {
-		p.strings = make(map[rune]stringFlag)
+// NumberVar registers a handler for numeric options, such as "-5" or "+10",
+// in place of options. v is set to the whole argument, sign included.
+func (p *Parser) NumberVar(v Value, usage string) {
+	if p.number != nil {
+		panic("flag: numeric options registered twice")
 	}
-	p.strings[name] = stringFlag{addr}
+	p.number = &option{value: v, arg: requiredArg, usage: usage}
+}
+
+// BoolVar creates a new boolean variable, which is set by the option.
+// usage describes it; see Usage.
+func (p *Parser) BoolVar(addr *bool, name rune, usage string) {
+	p.Var(boolFlag{addr}, name, usage)
+}
+
+// CountVar creates a new int variable, which counts how many times the
+// option appears, as with "-v -v"
+func (p *Parser) CountVar(addr *int, name rune, usage string) {
+	p.Var(countFlag{addr}, name, usage)
+}
+
+// IntVar creates a new int64 variable, which is set to the option's argument
+func (p *Parser) IntVar(addr *int64, name rune, usage string) {
+	p.Var(intFlag{addr}, name, usage)
+}
+
+// StringVar creates a new string variable, which is set to the option's
+// argument
+func (p *Parser) StringVar(addr *string, name rune, usage string) {
+	p.Var(stringFlag{addr}, name, usage)
+}
+
+// StringsVar creates a new list of strings, which gets each of the option's
+// arguments when it's repeated, as with "-e one -e two"
+func (p *Parser) StringsVar(addr *[]string, name rune, usage string) {
+	p.Var(stringsFlag{addr}, name, usage)
 }
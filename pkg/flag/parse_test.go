@@ -36,6 +36,11 @@ var parsetests = []parsetest{
 	{"string", "-p hello world", []flagtest{{'p', tString, "hello"}}, "world"},
 	{"int", "-n 32 world", []flagtest{{'n', tInt, "32"}}, "world"},
 	{"bundled_complex", "-vxp hi", []flagtest{{name: 'v'}, {name: 'x'}, {'p', tString, "hi"}}, ""},
+	{"attached", "-vphello world", []flagtest{{name: 'v'}, {'p', tString, "hello"}}, "world"},
+	{"dash argument", "-p -v world", []flagtest{{'p', tString, "-v"}}, "world"},
+	{"negative int", "-n -3", []flagtest{{'n', tInt, "-3"}}, ""},
+	{"stdin operand", "-v - -x", []flagtest{{name: 'v'}}, "- -x"},
+	{"-- operand", "-- --", nil, "--"},
 	//{"unset", "-vx", []flagtest{{name: 'v'}, {name: 'x'}, {name: 'y'}}, ""},
 }
 
@@ -53,15 +58,15 @@ func TestParse(t *testing.T) {
 				case tBool:
 					var x bool
 					mbool[f.name] = &x
-					p.BoolVar(&x, f.name)
+					p.BoolVar(&x, f.name, "")
 				case tInt:
 					var x int64
 					mint[f.name] = &x
-					p.IntVar(&x, f.name)
+					p.IntVar(&x, f.name, "")
 				case tString:
 					var x string
 					mstring[f.name] = &x
-					p.StringVar(&x, f.name)
+					p.StringVar(&x, f.name, "")
 				}
 			}
 
@@ -124,10 +129,54 @@ func expectError(err error, typ reflect.Type, t *testing.T) {
 //	}
 //}
 
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		args string
+		err  error
+		msg  string
+	}{
+		{"-x", ErrUnknownOption{'x'}, "illegal option -- x"},
+		{"-vx", ErrUnknownOption{'x'}, "illegal option -- x"},
+		{"-v -p", ErrMissingArgument{'p'}, "option requires an argument -- p"},
+		{"-vn", ErrMissingArgument{'n'}, "option requires an argument -- n"},
+		{"-n 3x", ErrNonInt{'n', "3x"}, `option -n requires an integer, not "3x"`},
+	}
+	for _, tt := range tests {
+		p := Parser{Input: strings.Fields(tt.args)}
+		var v bool
+		var n int64
+		var s string
+		p.BoolVar(&v, 'v', "")
+		p.IntVar(&n, 'n', "")
+		p.StringVar(&s, 'p', "")
+		pos, err := p.Parse()
+		if err != tt.err {
+			t.Errorf("%q: expected error %#v, got %#v", tt.args, tt.err, err)
+			continue
+		}
+		if err.Error() != tt.msg {
+			t.Errorf("%q: expected message %q, got %q", tt.args, tt.msg, err.Error())
+		}
+		if pos != nil {
+			t.Errorf("%q: expected no operands, got %q", tt.args, pos)
+		}
+	}
+}
+
+func TestNoOperands(t *testing.T) {
+	p := Parser{Input: []string{"-v"}}
+	var v bool
+	p.BoolVar(&v, 'v', "")
+	pos, err := p.Parse()
+	if err != nil || pos == nil || len(pos) != 0 {
+		t.Errorf("expected an empty list of operands, got %#v, %v", pos, err)
+	}
+}
+
 func TestNonInt(t *testing.T) {
 	p := Parser{Input: strings.Fields("-a hello")}
 	var a int64
-	p.IntVar(&a, 'a')
+	p.IntVar(&a, 'a', "")
 	_, err := p.Parse()
 	if err == nil {
 		t.Errorf("no error thrown")
@@ -137,3 +186,90 @@ func TestNonInt(t *testing.T) {
 	}
 
 }
+
+// listValue is a custom Value splitting its argument on commas
+type listValue []string
+
+func (l *listValue) Set(s string) error {
+	if s == "" {
+		return fmt.Errorf("empty list")
+	}
+	*l = append(*l, strings.Split(s, ",")...)
+	return nil
+}
+
+func (l *listValue) String() string { return strings.Join(*l, ",") }
+
+func TestShapes(t *testing.T) {
+	tests := []struct {
+		args     string
+		patterns []string
+		verbose  int
+		suffix   string
+		number   string
+		list     string
+		operands string
+	}{
+		{"-e a -eb -v -vv c", []string{"a", "b"}, 3, "", "", "", "c"},
+		{"-s -v", nil, 1, "", "", "", ""},
+		{"-sbak -v", nil, 1, "bak", "", "", ""},
+		{"-s bak", nil, 0, "", "", "", "bak"},
+		{"-12 -v", nil, 1, "", "-12", "", ""},
+		{"+3 file", nil, 0, "", "+3", "", "file"},
+		{"-v +3 -e x", []string{"x"}, 1, "", "+3", "", ""},
+		{"-l a,b -l c", nil, 0, "", "", "a,b,c", ""},
+		{"-- -12", nil, 0, "", "", "", "-12"},
+	}
+	for _, tt := range tests {
+		var p Parser
+		p.Input = strings.Fields(tt.args)
+		var patterns []string
+		var verbose int
+		var suffix string
+		var number string
+		var list listValue
+		p.StringsVar(&patterns, 'e', "")
+		p.CountVar(&verbose, 'v', "")
+		p.OptionalVar(stringFlag{&suffix}, 's', "")
+		p.NumberVar(stringFlag{&number}, "")
+		p.Var(&list, 'l', "")
+
+		pos, err := p.Parse()
+		if err != nil {
+			t.Errorf("%q: %s", tt.args, err)
+			continue
+		}
+		if !reflect.DeepEqual(patterns, tt.patterns) {
+			t.Errorf("%q: -e: expected %q, got %q", tt.args, tt.patterns, patterns)
+		}
+		if verbose != tt.verbose {
+			t.Errorf("%q: -v: expected %d, got %d", tt.args, tt.verbose, verbose)
+		}
+		if suffix != tt.suffix {
+			t.Errorf("%q: -s: expected %q, got %q", tt.args, tt.suffix, suffix)
+		}
+		if number != tt.number {
+			t.Errorf("%q: number: expected %q, got %q", tt.args, tt.number, number)
+		}
+		if list.String() != tt.list {
+			t.Errorf("%q: -l: expected %q, got %q", tt.args, tt.list, list.String())
+		}
+		if strings.Join(pos, " ") != tt.operands {
+			t.Errorf("%q: expected operands %q, got %q", tt.args, tt.operands, pos)
+		}
+	}
+}
+
+func TestInvalidValue(t *testing.T) {
+	var p Parser
+	p.Input = []string{"-l", ""}
+	var list listValue
+	p.Var(&list, 'l', "")
+	_, err := p.Parse()
+	if _, ok := err.(ErrInvalidValue); !ok {
+		t.Fatalf("expected ErrInvalidValue, got %#v", err)
+	}
+	if msg := "option -l: empty list"; err.Error() != msg {
+		t.Errorf("expected %q, got %q", msg, err.Error())
+	}
+}
@@ -0,0 +1,112 @@
+package flag
+
+import (
+	"sort"
+	"strings"
+)
+
+// sorted returns the registered options in order of their names, with any
+// numeric option first
+func (p *Parser) sorted() []option {
+	opts := make([]option, 0, len(p.options)+1)
+	if p.number != nil {
+		opts = append(opts, *p.number)
+	}
+	for _, opt := range p.options {
+		opts = append(opts, opt)
+	}
+	sort.Slice(opts, func(i, j int) bool { return opts[i].name < opts[j].name })
+	return opts
+}
+
+// argName returns the name of an option's option-argument and its
+// description. A word in the usage string between backquotes names the
+// option-argument, and the backquotes are removed; otherwise the name is
+// taken from the option's type.
+func (opt option) argName() (name, usage string) {
+	usage = opt.usage
+	if i := strings.IndexByte(usage, '`'); i >= 0 {
+		if j := strings.IndexByte(usage[i+1:], '`'); j >= 0 {
+			name = usage[i+1 : i+1+j]
+			return name, usage[:i] + name + usage[i+1+j+1:]
+		}
+	}
+	if opt.name == 0 {
+		return "number", usage
+	}
+	switch opt.value.(type) {
+	case intFlag:
+		name = "number"
+	default:
+		name = "string"
+	}
+	return name, usage
+}
+
+// head returns how the option is written in a usage message, such as
+// "-k number", or "-x[string]" if the option-argument is optional
+func (opt option) head() (string, string) {
+	if opt.arg == noArg {
+		return "-" + string(opt.name), opt.usage
+	}
+	name, usage := opt.argName()
+	switch {
+	case opt.name == 0:
+		return "-" + name, usage
+	case opt.arg == optionalArg:
+		return "-" + string(opt.name) + "[" + name + "]", usage
+	}
+	return "-" + string(opt.name) + " " + name, usage
+}
+
+// Synopsis returns the registered options as they appear in a usage line:
+// the options without option-arguments bundled together, followed by each of
+// the others, such as "[-nr] [-k number]"
+func (p *Parser) Synopsis() string {
+	var bundle strings.Builder
+	var parts []string
+	for _, opt := range p.sorted() {
+		if opt.name != 0 && opt.arg == noArg {
+			bundle.WriteRune(opt.name)
+			continue
+		}
+		head, _ := opt.head()
+		parts = append(parts, "["+head+"]")
+	}
+	if bundle.Len() > 0 {
+		parts = append([]string{"[-" + bundle.String() + "]"}, parts...)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Usage returns a usage message for the utility called name, taking the
+// given operands: a synopsis, followed by a line describing each option
+func (p *Parser) Usage(name, operands string) string {
+	var b strings.Builder
+	b.WriteString("usage: " + name)
+	for _, s := range []string{p.Synopsis(), operands} {
+		if s != "" {
+			b.WriteString(" " + s)
+		}
+	}
+	b.WriteString("\n")
+
+	opts := p.sorted()
+	heads := make([]string, len(opts))
+	usages := make([]string, len(opts))
+	width := 0
+	for i, opt := range opts {
+		heads[i], usages[i] = opt.head()
+		if len(heads[i]) > width {
+			width = len(heads[i])
+		}
+	}
+	for i := range opts {
+		line := "  " + heads[i]
+		if usages[i] != "" {
+			line += strings.Repeat(" ", width-len(heads[i])) + "  " + usages[i]
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
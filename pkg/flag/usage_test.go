@@ -12,11 +12,11 @@ func Example() {
 	var n, r, z bool
 	var k int64
 	var x string
-	p.BoolVar(&n, 'n')
-	p.BoolVar(&r, 'r')
-	p.BoolVar(&z, 'z')
-	p.IntVar(&k, 'k')
-	p.StringVar(&x, 'x')
+	p.BoolVar(&n, 'n', "compare numerically")
+	p.BoolVar(&r, 'r', "reverse the order")
+	p.BoolVar(&z, 'z', "")
+	p.IntVar(&k, 'k', "sort on `field`")
+	p.StringVar(&x, 'x', "an example")
 
 	args, _ := p.Parse()
 
@@ -35,3 +35,39 @@ func Example() {
 	// x: hello
 	// args: [from ExampleLand]
 }
+
+func ExampleParser_Usage() {
+	var p Parser
+	var n, r bool
+	var k int64
+	var o string
+	p.BoolVar(&r, 'r', "reverse the order")
+	p.BoolVar(&n, 'n', "compare numerically")
+	p.IntVar(&k, 'k', "sort on `field`")
+	p.StringVar(&o, 'o', "write to `output` instead of standard output")
+
+	fmt.Print(p.Usage("sort", "[file...]"))
+
+	// Output:
+	// usage: sort [-nr] [-k field] [-o output] [file...]
+	//   -k field   sort on field
+	//   -n         compare numerically
+	//   -o output  write to output instead of standard output
+	//   -r         reverse the order
+}
+
+func ExampleParser_Synopsis() {
+	var p Parser
+	var patterns []string
+	var verbose int
+	var lines, suffix string
+	p.StringsVar(&patterns, 'e', "match `pattern`")
+	p.CountVar(&verbose, 'v', "")
+	p.OptionalVar(stringFlag{&suffix}, 'i', "edit in place, keeping a copy ending in `suffix`")
+	p.NumberVar(stringFlag{&lines}, "")
+
+	fmt.Println(p.Synopsis())
+
+	// Output:
+	// [-v] [-number] [-e pattern] [-i[suffix]]
+}
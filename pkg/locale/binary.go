@@ -0,0 +1,410 @@
+package locale
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+)
+
+// The compiled form of a Def starts with a magic string and a version,
+// followed by each category in turn. Numbers are written as varints, and
+// strings and lists are preceded by their length.
+const (
+	binaryMagic   = "PULC"
+	binaryVersion = 1
+)
+
+var errTruncated = errors.New("compiled locale is truncated")
+
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) int(n int) {
+	var b [binary.MaxVarintLen64]byte
+	e.buf = append(e.buf, b[:binary.PutVarint(b[:], int64(n))]...)
+}
+
+func (e *encoder) bool(b bool) {
+	if b {
+		e.int(1)
+	} else {
+		e.int(0)
+	}
+}
+
+func (e *encoder) string(s string) {
+	e.int(len(s))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *encoder) strings(ss []string) {
+	e.int(len(ss))
+	for _, s := range ss {
+		e.string(s)
+	}
+}
+
+func (e *encoder) ints(ns []int) {
+	e.int(len(ns))
+	for _, n := range ns {
+		e.int(n)
+	}
+}
+
+func (e *encoder) char(c Character) {
+	e.string(c.Bytes)
+	e.int(int(c.Rune))
+}
+
+func (e *encoder) chars(cs []Character) {
+	e.int(len(cs))
+	for _, c := range cs {
+		e.char(c)
+	}
+}
+
+// charMap writes a case conversion, sorted so the output is reproducible
+func (e *encoder) charMap(m map[Character]Character) {
+	keys := sortedKeys(m)
+	e.int(len(keys))
+	for _, k := range keys {
+		e.char(k)
+		e.char(m[k])
+	}
+}
+
+// decoder reads what encoder wrote. After the first error, every read
+// returns a zero value.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) int() int {
+	if d.err != nil {
+		return 0
+	}
+	n, size := binary.Varint(d.buf)
+	if size <= 0 {
+		d.err = errTruncated
+		return 0
+	}
+	d.buf = d.buf[size:]
+	return int(n)
+}
+
+// length reads the length of a list whose items take at least one byte each
+func (d *decoder) length() int {
+	n := d.int()
+	if n < 0 || n > len(d.buf) {
+		d.err = errTruncated
+		return 0
+	}
+	return n
+}
+
+func (d *decoder) bool() bool {
+	return d.int() != 0
+}
+
+func (d *decoder) string() string {
+	n := d.length()
+	if d.err != nil {
+		return ""
+	}
+	s := string(d.buf[:n])
+	d.buf = d.buf[n:]
+	return s
+}
+
+func (d *decoder) strings() []string {
+	n := d.length()
+	if n == 0 {
+		return nil
+	}
+	ss := make([]string, n)
+	for i := range ss {
+		ss[i] = d.string()
+	}
+	return ss
+}
+
+func (d *decoder) ints() []int {
+	n := d.length()
+	if n == 0 {
+		return nil
+	}
+	ns := make([]int, n)
+	for i := range ns {
+		ns[i] = d.int()
+	}
+	return ns
+}
+
+func (d *decoder) char() Character {
+	return Character{d.string(), rune(d.int())}
+}
+
+func (d *decoder) chars() []Character {
+	n := d.length()
+	if n == 0 {
+		return nil
+	}
+	cs := make([]Character, n)
+	for i := range cs {
+		cs[i] = d.char()
+	}
+	return cs
+}
+
+func (d *decoder) charMap() map[Character]Character {
+	n := d.length()
+	if n == 0 {
+		return nil
+	}
+	m := make(map[Character]Character, n)
+	for i := 0; i < n; i++ {
+		k := d.char()
+		m[k] = d.char()
+	}
+	return m
+}
+
+// MarshalBinary encodes the definition in the compact form written by
+// localedef
+func (def Def) MarshalBinary() ([]byte, error) {
+	e := &encoder{buf: []byte(binaryMagic)}
+	e.int(binaryVersion)
+
+	c := def.Ctype
+	for _, class := range [][]Character{c.upper, c.lower, c.alpha, c.digit, c.alnum, c.space,
+		c.cntrl, c.punct, c.graph, c.print, c.xdigit, c.blank} {
+		e.chars(class)
+	}
+	e.strings(c.classes)
+	for _, class := range c.classes {
+		e.chars(c.other[class])
+	}
+	e.charMap(c.toupper)
+	e.charMap(c.tolower)
+	e.string(c.codeset)
+	e.int(c.mbCurMax)
+
+	co := def.Collate
+	names := make([]string, 0, len(co.elements))
+	for name := range co.elements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	e.int(len(names))
+	for _, name := range names {
+		e.string(name)
+		e.chars(co.elements[name])
+	}
+	e.strings(co.symbols)
+	e.int(len(co.levels))
+	for _, l := range co.levels {
+		e.bool(l.backward)
+		e.bool(l.position)
+	}
+	e.int(len(co.order))
+	for _, o := range co.order {
+		e.string(o.id)
+		e.chars(o.chars)
+		e.int(len(o.weights))
+		for _, w := range o.weights {
+			e.strings(w)
+		}
+	}
+
+	m := def.Monetary
+	for _, s := range []string{m.intCurrSymbol, m.currencySymbol, m.monDecimalPoint,
+		m.monThousandsSep, m.positiveSign, m.negativeSign} {
+		e.string(s)
+	}
+	e.ints(m.monGrouping)
+	for _, n := range []int{m.intFracDigits, m.fracDigits, m.pCsPrecedes, m.pSepBySpace,
+		m.nCsPrecedes, m.nSepBySpace, m.pSignPosn, m.nSignPosn, m.intPcsPrecedes,
+		m.intPsepBySpace, m.intNcsPrecedes, m.intNsepBySpace, m.intPsignPosn, m.intNsignPosn} {
+		e.int(n)
+	}
+
+	n := def.Numeric
+	e.string(n.decimalPoint)
+	e.string(n.thousandsSep)
+	e.ints(n.grouping)
+
+	t := def.Time
+	for _, list := range [][]string{t.abday, t.day, t.abmon, t.mon} {
+		e.strings(list)
+	}
+	for _, s := range []string{t.dtFmt, t.dFmt, t.tFmt, t.am, t.pm, t.tFmtAmPm} {
+		e.string(s)
+	}
+	e.int(len(t.eras))
+	for _, era := range t.eras {
+		e.bool(era.reverse)
+		e.int(era.offset)
+		e.string(era.startDate)
+		e.string(era.endDate)
+		e.string(era.name)
+		e.string(era.format)
+	}
+	e.string(t.eraDTFmt)
+	e.string(t.eraDFmt)
+	e.string(t.eraTFmt)
+	e.strings(t.altDigits)
+
+	e.string(def.Messages.yesexpr)
+	e.string(def.Messages.noexpr)
+
+	categories := make([]string, 0, len(def.Copies))
+	for category := range def.Copies {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	e.int(len(categories))
+	for _, category := range categories {
+		e.string(category)
+		e.string(def.Copies[category])
+	}
+
+	return e.buf, nil
+}
+
+// UnmarshalBinary decodes a definition written by MarshalBinary
+func (def *Def) UnmarshalBinary(data []byte) error {
+	if len(data) < len(binaryMagic) || string(data[:len(binaryMagic)]) != binaryMagic {
+		return errors.New("not a compiled locale")
+	}
+	d := &decoder{buf: data[len(binaryMagic):]}
+	if v := d.int(); v != binaryVersion && d.err == nil {
+		return fmt.Errorf("unsupported compiled locale version %d", v)
+	}
+	var out Def
+
+	c := &out.Ctype
+	for _, class := range []*[]Character{&c.upper, &c.lower, &c.alpha, &c.digit, &c.alnum, &c.space,
+		&c.cntrl, &c.punct, &c.graph, &c.print, &c.xdigit, &c.blank} {
+		*class = d.chars()
+	}
+	c.classes = d.strings()
+	if len(c.classes) > 0 {
+		c.other = make(map[string][]Character, len(c.classes))
+	}
+	for _, class := range c.classes {
+		c.other[class] = d.chars()
+	}
+	c.toupper = d.charMap()
+	c.tolower = d.charMap()
+	c.codeset = d.string()
+	c.mbCurMax = d.int()
+
+	co := &out.Collate
+	if n := d.length(); n > 0 {
+		co.elements = make(map[string][]Character, n)
+		for i := 0; i < n; i++ {
+			name := d.string()
+			co.elements[name] = d.chars()
+		}
+	}
+	co.symbols = d.strings()
+	if n := d.length(); n > 0 {
+		co.levels = make([]direction, n)
+		for i := range co.levels {
+			co.levels[i] = direction{backward: d.bool(), position: d.bool()}
+		}
+	}
+	if n := d.length(); n > 0 {
+		co.order = make([]ordering, n)
+		for i := range co.order {
+			o := &co.order[i]
+			o.id = d.string()
+			o.chars = d.chars()
+			o.weights = make([][]string, d.length())
+			for j := range o.weights {
+				o.weights[j] = d.strings()
+			}
+		}
+	}
+
+	m := &out.Monetary
+	for _, s := range []*string{&m.intCurrSymbol, &m.currencySymbol, &m.monDecimalPoint,
+		&m.monThousandsSep, &m.positiveSign, &m.negativeSign} {
+		*s = d.string()
+	}
+	m.monGrouping = d.ints()
+	for _, n := range []*int{&m.intFracDigits, &m.fracDigits, &m.pCsPrecedes, &m.pSepBySpace,
+		&m.nCsPrecedes, &m.nSepBySpace, &m.pSignPosn, &m.nSignPosn, &m.intPcsPrecedes,
+		&m.intPsepBySpace, &m.intNcsPrecedes, &m.intNsepBySpace, &m.intPsignPosn, &m.intNsignPosn} {
+		*n = d.int()
+	}
+
+	n := &out.Numeric
+	n.decimalPoint = d.string()
+	n.thousandsSep = d.string()
+	n.grouping = d.ints()
+
+	t := &out.Time
+	for _, list := range []*[]string{&t.abday, &t.day, &t.abmon, &t.mon} {
+		*list = d.strings()
+	}
+	for _, s := range []*string{&t.dtFmt, &t.dFmt, &t.tFmt, &t.am, &t.pm, &t.tFmtAmPm} {
+		*s = d.string()
+	}
+	if n := d.length(); n > 0 {
+		t.eras = make([]Era, n)
+		for i := range t.eras {
+			era := &t.eras[i]
+			era.reverse = d.bool()
+			era.offset = d.int()
+			era.startDate = d.string()
+			era.endDate = d.string()
+			era.name = d.string()
+			era.format = d.string()
+			if err := era.parseDates(); err != nil && d.err == nil {
+				d.err = err
+			}
+		}
+	}
+	t.eraDTFmt = d.string()
+	t.eraDFmt = d.string()
+	t.eraTFmt = d.string()
+	t.altDigits = d.strings()
+
+	out.Messages.yesexpr = d.string()
+	out.Messages.noexpr = d.string()
+
+	if n := d.length(); n > 0 {
+		out.Copies = make(map[string]string, n)
+		for i := 0; i < n; i++ {
+			category := d.string()
+			out.Copies[category] = d.string()
+		}
+	}
+
+	if d.err != nil {
+		return d.err
+	}
+	if len(d.buf) != 0 {
+		return errors.New("trailing data after compiled locale")
+	}
+	*def = out
+	return nil
+}
+
+// LoadDef reads a locale compiled by localedef
+func LoadDef(path string) (Def, error) {
+	var def Def
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return def, err
+	}
+	if err := def.UnmarshalBinary(data); err != nil {
+		return def, fmt.Errorf("%s: %s", path, err)
+	}
+	return def, nil
+}
@@ -0,0 +1,121 @@
+package locale
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func parsePOSIX(t testing.TB) Def {
+	f, err := os.Open("POSIX.locale")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	def, err := ParseDef(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return def
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	japanese, err := ParseDef(strings.NewReader(parseTests[6]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, def := range []Def{parsePOSIX(t), japanese, {}} {
+		data, err := def.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Def
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, def) {
+			t.Errorf("round trip changed the definition:\n%+v\n%+v", got, def)
+		}
+	}
+}
+
+func TestBinaryCorrupt(t *testing.T) {
+	data, err := parsePOSIX(t).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var def Def
+	for _, bad := range [][]byte{nil, []byte("nope"), data[:len(data)/2], append(data, 0)} {
+		if err := def.UnmarshalBinary(bad); err == nil {
+			t.Errorf("expected an error for %d bytes", len(bad))
+		}
+	}
+}
+
+func TestLoadDef(t *testing.T) {
+	data, err := parsePOSIX(t).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := ioutil.TempFile("", "locale")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.Write(data)
+	f.Close()
+
+	def, err := LoadDef(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if def.Numeric.decimalPoint != "." {
+		t.Errorf("decimal point is %q", def.Numeric.decimalPoint)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if errs := parsePOSIX(t).Validate(); len(errs) != 0 {
+		t.Errorf("POSIX locale has warnings: %v", errs)
+	}
+
+	bad := `LC_CTYPE
+upper <A>;<b>
+lower <b>
+digit <one>
+END LC_CTYPE
+LC_COLLATE
+order_start forward
+<a> <z>
+<a>
+order_end
+END LC_COLLATE
+LC_MONETARY
+p_sign_posn 7
+int_curr_symbol "$"
+END LC_MONETARY
+`
+	def, err := ParseDef(strings.NewReader(bad))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := def.Validate(); len(errs) != 6 {
+		t.Errorf("expected 6 warnings, got %d: %v", len(errs), errs)
+	}
+}
+
+func BenchmarkLoadPOSIX(b *testing.B) {
+	data, err := parsePOSIX(b).MarshalBinary()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var def Def
+		if err := def.UnmarshalBinary(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,424 @@
+package locale
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Character is a character of a locale's codeset
+type Character struct {
+	Bytes string // The character's encoding in the codeset
+	Rune  rune   // The Unicode code point, or -1 if it isn't known
+}
+
+// Charmap maps the symbolic names used in locale definitions to the
+// characters of a codeset, as described in chapter 6 of the Base Definitions
+type Charmap struct {
+	CodeSetName string
+	MbCurMax    int
+	MbCurMin    int
+
+	chars     map[string]Character // Maps a symbolic name to its character
+	encodings map[string]Character // Maps an encoding to its character
+	ucs       bool                 // Whether <Uxxxx> names not in chars are encoded as UTF-8
+}
+
+// Lookup returns the character with the symbolic name, without its angle
+// brackets
+func (c *Charmap) Lookup(name string) (Character, bool) {
+	if ch, ok := c.chars[name]; ok {
+		return ch, true
+	}
+	if r, ok := ucsName(name); ok && c.ucs {
+		return Character{string(r), r}, true
+	}
+	return Character{}, false
+}
+
+// decode returns the character with an encoding, as written literally or with
+// escape sequences in a locale definition
+func (c *Charmap) decode(encoding string) Character {
+	if ch, ok := c.encodings[encoding]; ok {
+		return ch
+	}
+	if r, size := utf8.DecodeRuneInString(encoding); c.ucs && r != utf8.RuneError && size == len(encoding) {
+		return Character{encoding, r}
+	}
+	return Character{encoding, -1}
+}
+
+// index fills in encodings, once chars is complete
+func (c *Charmap) index() {
+	c.encodings = make(map[string]Character, len(c.chars))
+	for _, ch := range c.chars {
+		c.encodings[ch.Bytes] = ch
+	}
+}
+
+// between returns the characters whose encodings lie strictly between those
+// of first and last, in order
+func (c *Charmap) between(first, last Character) []Character {
+	var out []Character
+	if c.ucs && first.Rune >= 0 && last.Rune >= 0 {
+		for r := first.Rune + 1; r < last.Rune; r++ {
+			if utf8.ValidRune(r) {
+				out = append(out, Character{string(r), r})
+			}
+		}
+		return out
+	}
+	seen := make(map[string]bool)
+	for _, ch := range c.chars {
+		if encodedBetween(first.Bytes, ch.Bytes, last.Bytes) && !seen[ch.Bytes] {
+			seen[ch.Bytes] = true
+			out = append(out, ch)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return encodedLess(out[i].Bytes, out[j].Bytes) })
+	return out
+}
+
+// sortedKeys returns the characters a case conversion maps, in the order of
+// their encodings
+func sortedKeys(m map[Character]Character) []Character {
+	keys := make([]Character, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return encodedLess(keys[i].Bytes, keys[j].Bytes) })
+	return keys
+}
+
+// encodedLess compares encodings as numbers, so shorter ones come first
+func encodedLess(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+func encodedBetween(first, x, last string) bool {
+	return encodedLess(first, x) && encodedLess(x, last)
+}
+
+// ucsName decodes the names <Uxxxx> and <Uxxxxxxxx>, which give a character
+// by its code point
+func ucsName(name string) (rune, bool) {
+	if (len(name) != 5 && len(name) != 9) || name[0] != 'U' {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(name[1:], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(n)) {
+		return 0, false
+	}
+	return rune(n), true
+}
+
+// isUTF8 reports whether a code set name refers to UTF-8
+func isUTF8(name string) bool {
+	name = strings.ToUpper(strings.Replace(name, "-", "", -1))
+	return name == "UTF8"
+}
+
+// defaultCharmap is used when a locale definition is parsed without one. It
+// holds the portable character set and any <Uxxxx> name, encoded as UTF-8.
+var defaultCharmap = func() *Charmap {
+	c := &Charmap{CodeSetName: "UTF-8", MbCurMax: utf8.UTFMax, MbCurMin: 1, ucs: true}
+	c.chars = make(map[string]Character, len(portable))
+	for name, r := range portable {
+		c.chars[name] = Character{string(r), r}
+	}
+	c.index()
+	return c
+}()
+
+// runeFor works out the code point of a character defined in a charmap
+func (c *Charmap) runeFor(name string, encoding string) rune {
+	if r, ok := ucsName(name); ok {
+		return r
+	}
+	if isUTF8(c.CodeSetName) {
+		if r, size := utf8.DecodeRuneInString(encoding); r != utf8.RuneError && size == len(encoding) {
+			return r
+		}
+	}
+	if r, ok := portable[name]; ok {
+		return r
+	}
+	return -1
+}
+
+// charmapParser holds the state of ParseCharmap
+type charmapParser struct {
+	cm      *Charmap
+	escape  rune
+	comment rune
+	line    int
+	err     error
+}
+
+func (p *charmapParser) fail(col int, format string, args ...interface{}) {
+	if p.err == nil {
+		p.err = &ParseError{Line: p.line, Column: col, Msg: fmt.Sprintf(format, args...)}
+	}
+}
+
+// ParseCharmap reads a character set description (charmap) file
+func ParseCharmap(r io.Reader) (*Charmap, error) {
+	p := charmapParser{
+		cm:      &Charmap{MbCurMax: 1, MbCurMin: 1, chars: make(map[string]Character)},
+		escape:  '\\',
+		comment: '#',
+	}
+
+	const (
+		header = iota
+		charmap
+		width
+		done
+	)
+	section := header
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() && p.err == nil {
+		p.line++
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		fields := strings.Fields(text)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], string(p.comment)) {
+			continue
+		}
+
+		switch section {
+		case header, done:
+			switch fields[0] {
+			case "CHARMAP":
+				section = charmap
+				continue
+			case "WIDTH":
+				section = width
+				continue
+			case "WIDTH_DEFAULT":
+				continue
+			}
+			if section == done {
+				p.fail(1, "unexpected %s after END CHARMAP", fields[0])
+				break
+			}
+			p.headerLine(fields)
+		case charmap:
+			if len(fields) == 2 && fields[0] == "END" && fields[1] == "CHARMAP" {
+				section = done
+				continue
+			}
+			p.charLine(text)
+		case width:
+			// Column widths aren't used, so they are skipped
+			if len(fields) == 2 && fields[0] == "END" && fields[1] == "WIDTH" {
+				section = done
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	if section == charmap || section == width {
+		p.fail(1, "missing END")
+		return nil, p.err
+	}
+	if p.cm.MbCurMin > p.cm.MbCurMax {
+		return nil, fmt.Errorf("<mb_cur_min> %d is more than <mb_cur_max> %d", p.cm.MbCurMin, p.cm.MbCurMax)
+	}
+	p.cm.index()
+	return p.cm, nil
+}
+
+// headerLine handles the declarations that come before CHARMAP
+func (p *charmapParser) headerLine(fields []string) {
+	if len(fields) != 2 {
+		p.fail(1, "expected a declaration and a value")
+		return
+	}
+	value := fields[1]
+	var err error
+	switch fields[0] {
+	case "<code_set_name>":
+		p.cm.CodeSetName = value
+		p.cm.ucs = isUTF8(value)
+	case "<mb_cur_max>":
+		p.cm.MbCurMax, err = strconv.Atoi(value)
+	case "<mb_cur_min>":
+		p.cm.MbCurMin, err = strconv.Atoi(value)
+	case "<escape_char>":
+		p.escape, _ = utf8.DecodeRuneInString(value)
+	case "<comment_char>":
+		p.comment, _ = utf8.DecodeRuneInString(value)
+	default:
+		p.fail(1, "unknown declaration %s", fields[0])
+	}
+	if err != nil {
+		p.fail(len(fields[0])+2, "%s must be a number", fields[0])
+	}
+}
+
+// charLine handles a line between CHARMAP and END CHARMAP. It is a symbolic
+// name, or a range of them, followed by an encoding and an optional comment.
+func (p *charmapParser) charLine(text string) {
+	col := len(text) - len(strings.TrimLeft(text, " \t")) + 1
+	rest := text[col-1:]
+
+	first, rest, ok := p.name(rest, &col)
+	if !ok {
+		return
+	}
+	last := first
+	hex := false
+	if strings.HasPrefix(rest, "..") {
+		// POSIX ranges use "..." and decimal numbers, but "..", with
+		// hexadecimal numbers, is also widespread
+		dots := 2
+		if strings.HasPrefix(rest, "...") {
+			dots = 3
+		} else {
+			hex = true
+		}
+		col += dots
+		if last, rest, ok = p.name(rest[dots:], &col); !ok {
+			return
+		}
+	}
+
+	trimmed := strings.TrimLeft(rest, " \t")
+	col += len(rest) - len(trimmed)
+	encoding, ok := p.encoding(trimmed, col)
+	if !ok {
+		return
+	}
+	if len(encoding) > p.cm.MbCurMax {
+		p.fail(col, "encoding is %d bytes, but <mb_cur_max> is %d", len(encoding), p.cm.MbCurMax)
+		return
+	}
+
+	if first == last {
+		p.add(first, encoding)
+		return
+	}
+	names, ok := rangeNames(first, last, hex)
+	if !ok {
+		p.fail(1, "invalid range <%s>...<%s>", first, last)
+		return
+	}
+	for _, name := range names {
+		p.add(name, encoding)
+		encoding = increment(encoding)
+	}
+}
+
+func (p *charmapParser) add(name string, encoding []byte) {
+	if _, ok := p.cm.chars[name]; ok {
+		p.fail(1, "<%s> is defined more than once", name)
+		return
+	}
+	enc := string(encoding)
+	p.cm.chars[name] = Character{enc, p.cm.runeFor(name, enc)}
+}
+
+// name reads a <name> at the start of text, advancing col past it
+func (p *charmapParser) name(text string, col *int) (string, string, bool) {
+	if !strings.HasPrefix(text, "<") {
+		p.fail(*col, "expected a symbolic name")
+		return "", "", false
+	}
+	var name strings.Builder
+	escaped := false
+	for i, r := range text[1:] {
+		switch {
+		case escaped:
+			escaped = false
+		case r == p.escape:
+			escaped = true
+			continue
+		case r == '>':
+			*col += utf8.RuneCountInString(text[:i+2])
+			return name.String(), text[i+2:], true
+		}
+		name.WriteRune(r)
+	}
+	p.fail(*col, "unterminated symbolic name")
+	return "", "", false
+}
+
+// encoding reads the escape sequences giving a character's bytes
+func (p *charmapParser) encoding(text string, col int) ([]byte, bool) {
+	var out []byte
+	esc := string(p.escape)
+	for strings.HasPrefix(text, esc) && len(text) > len(esc) {
+		b, n, ok := escapeValue(text[len(esc):])
+		if !ok || n < 0 {
+			break
+		}
+		out = append(out, b)
+		text = text[len(esc)+n:]
+	}
+	if len(out) == 0 || (text != "" && text[0] != ' ' && text[0] != '\t') {
+		p.fail(col, "invalid encoding")
+		return nil, false
+	}
+	return out, true
+}
+
+// rangeNames lists the names from first to last, which must share a prefix
+// and end in numbers of the same length
+func rangeNames(first, last string, hex bool) ([]string, bool) {
+	isDigit := func(c byte) bool { return isDigitIn(c, 10) }
+	base := 10
+	if hex {
+		isDigit = func(c byte) bool { return isDigitIn(c, 16) }
+		base = 16
+	}
+	split := len(first)
+	for split > 0 && isDigit(first[split-1]) {
+		split--
+	}
+	if len(first) != len(last) || split == len(first) || first[:split] != last[:split] {
+		return nil, false
+	}
+	for i := split; i < len(last); i++ {
+		if !isDigit(last[i]) {
+			return nil, false
+		}
+	}
+	from, err1 := strconv.ParseUint(first[split:], base, 32)
+	to, err2 := strconv.ParseUint(last[split:], base, 32)
+	if err1 != nil || err2 != nil || to < from {
+		return nil, false
+	}
+	width := len(first) - split
+	format := fmt.Sprintf("%%s%%0%dd", width)
+	if hex {
+		format = fmt.Sprintf("%%s%%0%dX", width)
+	}
+	names := make([]string, 0, to-from+1)
+	for n := from; n <= to; n++ {
+		names = append(names, fmt.Sprintf(format, first[:split], n))
+	}
+	return names, true
+}
+
+// increment adds one to an encoding, treating it as a big-endian number
+func increment(encoding []byte) []byte {
+	out := append([]byte(nil), encoding...)
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i]++
+		if out[i] != 0 {
+			break
+		}
+	}
+	return out
+}
@@ -0,0 +1,143 @@
+package locale
+
+import (
+	"strings"
+	"testing"
+)
+
+const testCharmap = `<code_set_name> EXAMPLE
+<mb_cur_max> 2
+<escape_char> /
+<comment_char> %
+% A comment
+% Another comment
+CHARMAP
+<A>                /x41         LATIN CAPITAL LETTER A
+<B>                /d66
+<C>                /103
+<j0101>...<j0104>  /xa1/xfe
+<U00E9>            /xe9
+<space>            /x20
+<a>                /x61
+<z>                /x7a
+END CHARMAP
+WIDTH
+<A>...<C> 1
+END WIDTH
+`
+
+func TestParseCharmap(t *testing.T) {
+	cm, err := ParseCharmap(strings.NewReader(testCharmap))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cm.CodeSetName != "EXAMPLE" || cm.MbCurMax != 2 || cm.MbCurMin != 1 {
+		t.Errorf("header is %+v", cm)
+	}
+
+	tests := []struct {
+		name string
+		want Character
+	}{
+		{"A", Character{"A", 'A'}},
+		{"B", Character{"B", 'B'}},
+		{"C", Character{"C", 'C'}},
+		{"j0101", Character{"\xa1\xfe", -1}},
+		{"j0102", Character{"\xa1\xff", -1}},
+		{"j0103", Character{"\xa2\x00", -1}},
+		{"j0104", Character{"\xa2\x01", -1}},
+		{"U00E9", Character{"\xe9", 'é'}},
+	}
+	for _, test := range tests {
+		got, ok := cm.Lookup(test.name)
+		if !ok || got != test.want {
+			t.Errorf("<%s> is %+v, %v, expected %+v", test.name, got, ok, test.want)
+		}
+	}
+	if _, ok := cm.Lookup("U0041"); ok {
+		t.Errorf("<U0041> shouldn't be defined outside UTF-8")
+	}
+}
+
+func TestParseCharmapErrors(t *testing.T) {
+	tests := []struct {
+		input     string
+		line, col int
+	}{
+		{"CHARMAP\n<A> \\x41\n<A> \\x42\nEND CHARMAP\n", 3, 1},
+		{"CHARMAP\n<A> 41\nEND CHARMAP\n", 2, 5},
+		{"CHARMAP\n<A> \\x41\\x42\nEND CHARMAP\n", 2, 5},
+		{"CHARMAP\n<j0104>...<j0101> \\x41\nEND CHARMAP\n", 2, 1},
+		{"<code_set_name>\n", 1, 1},
+		{"CHARMAP\n<A> \\x41\n", 2, 1},
+	}
+	for _, test := range tests {
+		_, err := ParseCharmap(strings.NewReader(test.input))
+		perr, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("%q: expected a ParseError, got %v", test.input, err)
+			continue
+		}
+		if perr.Line != test.line || perr.Column != test.col {
+			t.Errorf("%q: error at %d:%d, expected %d:%d (%v)", test.input, perr.Line, perr.Column, test.line, test.col, err)
+		}
+	}
+}
+
+func TestParseDefCharmap(t *testing.T) {
+	cm, err := ParseCharmap(strings.NewReader(testCharmap))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := `LC_CTYPE
+upper <A>;...;<C>
+alpha <j0101>;...;<j0104>;<U00E9>
+toupper (<a>,<A>)
+END LC_CTYPE
+LC_COLLATE
+order_start forward
+<j0102>
+<z>
+order_end
+END LC_COLLATE
+LC_TIME
+d_fmt "<A><space><U00E9>"
+END LC_TIME
+`
+	def, err := ParseDefCharmap(strings.NewReader(input), cm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := encoded(def.Ctype.upper); got != "ABC" {
+		t.Errorf("upper is %q", got)
+	}
+	if got := encoded(def.Ctype.alpha); got != "\xa1\xfe\xa1\xff\xa2\x00\xa2\x01\xe9" {
+		t.Errorf("alpha is %q", got)
+	}
+	if def.Ctype.toupper[Character{"a", 'a'}].Rune != 'A' || def.Ctype.codeset != "EXAMPLE" {
+		t.Errorf("ctype is %+v", def.Ctype)
+	}
+	if o := def.Collate.order[0]; o.id != "\xa1\xff" || o.chars[0].Bytes != "\xa1\xff" {
+		t.Errorf("order starts with %+v", o)
+	}
+	if def.Time.dFmt != "A \xe9" {
+		t.Errorf("d_fmt is %q", def.Time.dFmt)
+	}
+
+	if _, err := ParseDefCharmap(strings.NewReader("LC_CTYPE\nupper <U0041>\nEND LC_CTYPE\n"), cm); err == nil {
+		t.Errorf("expected <U0041> to be undefined")
+	}
+}
+
+func TestDefaultCharmap(t *testing.T) {
+	def, err := ParseDef(strings.NewReader("LC_CTYPE\nalpha <U00E0>;...;<U00E3>\nEND LC_CTYPE\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := encoded(def.Ctype.alpha); got != "àáâã" {
+		t.Errorf("alpha is %q", got)
+	}
+	if def.Ctype.alpha[1].Rune != 'á' {
+		t.Errorf("rune is %q", def.Ctype.alpha[1].Rune)
+	}
+}
@@ -1,15 +1,261 @@
 package locale
 
+import (
+	"encoding/binary"
+	"strings"
+	"unicode/utf8"
+)
+
+// Collate holds the collation sequence of a locale, as it was written in the
+// source. Weights are kept symbolic: each is the ID of an entry in the order.
 type Collate struct {
-	elements []string
-	symbols  []string
+	elements map[string][]Character // Maps a collating element's name to the characters it stands for
+	symbols  []string               // Collating symbols, which only hold a place in the order
+
+	levels []direction // How each level of weights is compared
+	order  []ordering
+}
 
-	order    []ordering
+// direction is the set of order_start keywords for one level
+type direction struct {
 	backward bool
-	position bool // ???????
+	position bool
 }
 
+// ordering is one line of the collation sequence. Its id is the encoding of
+// a character, a collating element or symbol as "<name>", or one of
+// "UNDEFINED" and "...". An ellipsis between two characters is replaced by
+// the characters it stands for; one that isn't is kept, and matches nothing.
+// Each weight is a list of IDs, or "IGNORE"; an empty weight means the
+// entry's own ID.
 type ordering struct {
 	id      string
-	weights []int
+	chars   []Character // The characters the entry matches, if any
+	weights [][]string
+}
+
+// Collator compares strings by a collation sequence. Strings are split into
+// the longest characters and collating elements that have weights, and
+// compared level by level; strings that are equal at every level are
+// compared byte by byte, so only identical strings are equal.
+type Collator struct {
+	levels        []direction
+	units         map[string][][]uint64 // Maps an encoding to its weights at each level
+	longest       int                   // The length of the longest encoding in units
+	undefined     [][]uint64            // The weights of characters not in the order
+	undefinedRank uint64
+	byteOrder     bool // Whether the sequence is just the order of the encodings
+}
+
+// Weights are ranks in the order, shifted to leave room below for the
+// encodings of characters that aren't in it, which are ordered among
+// themselves. A weight of ownWeight stands for the character itself, and
+// ignoredWeight holds the place of an ignored character at a level with the
+// position directive.
+const (
+	rankShift     = 32
+	ownWeight     = 0
+	ignoredWeight = 1
+)
+
+// Collator prepares the collation sequence for comparing strings
+func (c Collate) Collator() *Collator {
+	col := &Collator{levels: c.levels, units: make(map[string][][]uint64, len(c.order))}
+	if len(col.levels) == 0 {
+		col.levels = []direction{{}}
+	}
+
+	ranks := make(map[string]uint64, len(c.order))
+	for i, o := range c.order {
+		if _, ok := ranks[o.id]; !ok {
+			ranks[o.id] = uint64(i+2) << rankShift
+		}
+	}
+	undefinedRank, ok := ranks["UNDEFINED"]
+	if !ok {
+		undefinedRank = uint64(len(c.order)+2) << rankShift
+	}
+	weigh := func(o ordering, own uint64) [][]uint64 {
+		weights := make([][]uint64, len(col.levels))
+		for level := range weights {
+			var ids []string
+			if level < len(o.weights) {
+				ids = o.weights[level]
+			}
+			if len(ids) == 0 {
+				weights[level] = []uint64{own}
+			}
+			for _, id := range ids {
+				switch rank, ok := ranks[id]; {
+				case id == "IGNORE":
+				case id == o.id:
+					weights[level] = append(weights[level], own)
+				case ok:
+					weights[level] = append(weights[level], rank)
+				default:
+					weights[level] = append(weights[level], undefinedRank)
+				}
+			}
+		}
+		return weights
+	}
+
+	col.undefined = weigh(ordering{id: "UNDEFINED"}, ownWeight)
+	for _, o := range c.order {
+		if o.id == "UNDEFINED" {
+			col.undefined = weigh(o, ownWeight)
+		}
+		if len(o.chars) == 0 {
+			continue
+		}
+		key := encodedChars(o.chars)
+		if _, ok := col.units[key]; ok {
+			continue
+		}
+		col.units[key] = weigh(o, ranks[o.id])
+		if len(key) > col.longest {
+			col.longest = len(key)
+		}
+	}
+	for _, weights := range col.undefined {
+		for i, w := range weights {
+			if w == ownWeight {
+				weights[i] = undefinedRank
+			}
+		}
+	}
+	col.undefinedRank = undefinedRank
+	col.byteOrder = c.isByteOrder()
+	return col
+}
+
+// isByteOrder reports whether the sequence is a single forward level over
+// the characters encoded as the bytes 0, 1, 2..., with no other weights. As
+// the characters not in the order come after them, comparing the encodings
+// gives the same result.
+func (c Collate) isByteOrder() bool {
+	if len(c.levels) > 1 || len(c.levels) == 1 && c.levels[0] != (direction{}) || len(c.order) > 128 {
+		return false
+	}
+	for i, o := range c.order {
+		if len(o.chars) != 1 || o.id != string(rune(i)) || o.id != o.chars[0].Bytes {
+			return false
+		}
+		for _, w := range o.weights {
+			if len(w) > 1 || len(w) == 1 && w[0] != o.id {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// split divides s into the units that are weighed
+func (c *Collator) split(s string) [][][]uint64 {
+	var units [][][]uint64
+	for len(s) > 0 {
+		n := c.longest
+		if n > len(s) {
+			n = len(s)
+		}
+		for ; n > 0; n-- {
+			if weights, ok := c.units[s[:n]]; ok {
+				units = append(units, weights)
+				break
+			}
+		}
+		if n > 0 {
+			s = s[n:]
+			continue
+		}
+
+		// A character not in the order, which takes the undefined weights
+		r, size := utf8.DecodeRuneInString(s)
+		if r == utf8.RuneError && size <= 1 {
+			r = rune(s[0])
+		}
+		s = s[size:]
+		weights := make([][]uint64, len(c.undefined))
+		for level, ws := range c.undefined {
+			weights[level] = make([]uint64, len(ws))
+			for i, w := range ws {
+				if w == c.undefinedRank {
+					w |= uint64(r)
+				}
+				weights[level][i] = w
+			}
+		}
+		units = append(units, weights)
+	}
+	return units
+}
+
+// level returns the weights of the units at one level
+func (c *Collator) level(units [][][]uint64, level int) []uint64 {
+	dir := c.levels[level]
+	var weights []uint64
+	for _, u := range units {
+		if len(u[level]) == 0 && dir.position {
+			weights = append(weights, ignoredWeight)
+		}
+		weights = append(weights, u[level]...)
+	}
+	if dir.backward {
+		for i, j := 0, len(weights)-1; i < j; i, j = i+1, j-1 {
+			weights[i], weights[j] = weights[j], weights[i]
+		}
+	}
+	return weights
+}
+
+// Compare returns -1, 0 or 1 as a collates before, equally with or after b
+func (c *Collator) Compare(a, b string) int {
+	if c.byteOrder || a == b {
+		return strings.Compare(a, b)
+	}
+	ua, ub := c.split(a), c.split(b)
+	for level := range c.levels {
+		wa, wb := c.level(ua, level), c.level(ub, level)
+		for i := 0; i < len(wa) && i < len(wb); i++ {
+			if wa[i] != wb[i] {
+				if wa[i] < wb[i] {
+					return -1
+				}
+				return 1
+			}
+		}
+		if len(wa) != len(wb) {
+			if len(wa) < len(wb) {
+				return -1
+			}
+			return 1
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// Key returns a sort key for s, like strxfrm: comparing the keys of two
+// strings byte by byte gives the same result as Compare.
+func (c *Collator) Key(s string) []byte {
+	if c.byteOrder {
+		return []byte(s)
+	}
+	var key []byte
+	units := c.split(s)
+	for level := range c.levels {
+		for _, w := range c.level(units, level) {
+			// Each weight is written as its length in bytes and then the
+			// bytes, so that longer numbers come later
+			var b [8]byte
+			binary.BigEndian.PutUint64(b[:], w)
+			n := 0
+			for n < 7 && b[n] == 0 {
+				n++
+			}
+			key = append(key, byte(8-n))
+			key = append(key, b[n:]...)
+		}
+		key = append(key, 0)
+	}
+	return append(key, s...)
 }
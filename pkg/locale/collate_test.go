@@ -0,0 +1,110 @@
+package locale
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// collateSource has three levels: letters, accents compared from the end of
+// the string, and case, where the position of ignored characters counts
+const collateSource = `LC_COLLATE
+collating-symbol <none>
+collating-symbol <accent>
+collating-symbol <lower>
+collating-symbol <upper>
+collating-element <c-h> from "<c><h>"
+order_start forward;backward;forward,position
+<none>
+<accent>
+<lower>
+<upper>
+<tilde>  IGNORE;IGNORE;IGNORE
+<a>      <a>;<none>;<lower>
+<A>      <a>;<none>;<upper>
+<U00E1>  <a>;<accent>;<lower>
+<b>      <b>;<none>;<lower>
+<c>      <c>;<none>;<lower>
+<c-h>    <c-h>;<none>;<lower>
+<d>
+...
+<r>
+<s>      <s>;<none>;<lower>
+<U00DF>  "<s><s>";<none>;<lower>
+UNDEFINED
+<z>
+order_end
+END LC_COLLATE
+`
+
+func TestCollator(t *testing.T) {
+	def, err := ParseDef(strings.NewReader(collateSource))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := def.Collate.Collator()
+	if c.byteOrder {
+		t.Fatal("the test sequence was taken for byte order")
+	}
+
+	want := []string{"a", "A", "áa", "aá", "a~b", "ab", "ab~", "b", "cz", "ch", "d", "e", "s", "ß", "ss", "st", "x", "é", "z"}
+	got := append([]string(nil), want...)
+	sort.Strings(got)
+	sort.Slice(got, func(i, j int) bool { return c.Compare(got[i], got[j]) < 0 })
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sorted as %q, expected %q", got, want)
+	}
+
+	keys := append([]string(nil), want...)
+	sort.Strings(keys)
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(c.Key(keys[i]), c.Key(keys[j])) < 0 })
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("sorted by key as %q, expected %q", keys, want)
+	}
+
+	for _, s := range want {
+		if c.Compare(s, s) != 0 {
+			t.Errorf("%q isn't equal to itself", s)
+		}
+	}
+}
+
+func TestCollatorPOSIX(t *testing.T) {
+	c := POSIX().Collate.Collator()
+	if !c.byteOrder {
+		t.Errorf("the POSIX sequence isn't byte order")
+	}
+	want := []string{"", "A", "B", "a", "ab", "b", "é", "\xff"}
+	for i := 1; i < len(want); i++ {
+		if c.Compare(want[i-1], want[i]) >= 0 || bytes.Compare(c.Key(want[i-1]), c.Key(want[i])) >= 0 {
+			t.Errorf("%q doesn't collate before %q", want[i-1], want[i])
+		}
+	}
+
+	// Without the shortcut, the weights give the same order
+	c.byteOrder = false
+	for i := 1; i < len(want); i++ {
+		if c.Compare(want[i-1], want[i]) >= 0 || bytes.Compare(c.Key(want[i-1]), c.Key(want[i])) >= 0 {
+			t.Errorf("%q doesn't collate before %q by weight", want[i-1], want[i])
+		}
+	}
+}
+
+func TestCollateEllipsis(t *testing.T) {
+	def, err := ParseDef(strings.NewReader(collateSource))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, o := range def.Collate.order {
+		ids = append(ids, o.id)
+	}
+	if got := strings.Join(ids, " "); !strings.Contains(got, "<c-h> d e f g h i j k l m n o p q r s") {
+		t.Errorf("the ellipsis wasn't expanded: %s", got)
+	}
+	if errs := def.Validate(); len(errs) != 0 {
+		t.Errorf("unexpected warnings: %v", errs)
+	}
+}
@@ -1,20 +1,35 @@
 package locale
 
+// Ctype defines character classification and case conversion
 type Ctype struct {
-	// TODO: Should these be 'character' structs rather than strings?
-	upper   []string
-	lower   []string
-	alpha   []string
-	digit   []string
-	alnum   []string
-	space   []string
-	cntrl   []string
-	punct   []string
-	graph   []string
-	print   []string
-	xdigit  []string
-	blank   []string
-	other   map[string][]string // This maps locale-defined class names to characters
-	toupper map[string]string
-	tolower map[string]string
+	upper   []Character
+	lower   []Character
+	alpha   []Character
+	digit   []Character
+	alnum   []Character
+	space   []Character
+	cntrl   []Character
+	punct   []Character
+	graph   []Character
+	print   []Character
+	xdigit  []Character
+	blank   []Character
+	classes []string               // Locale-defined class names, in the order they were declared
+	other   map[string][]Character // This maps locale-defined class names to characters
+	toupper map[Character]Character
+	tolower map[Character]Character
+
+	codeset  string // The name of the codeset, from the charmap
+	mbCurMax int    // The most bytes a character may take
+
+}
+
+// Codeset returns the name of the locale's codeset
+func (c Ctype) Codeset() string {
+	return c.codeset
+}
+
+// MbCurMax returns the most bytes a character of the codeset may take
+func (c Ctype) MbCurMax() int {
+	return c.mbCurMax
 }
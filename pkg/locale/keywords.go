@@ -0,0 +1,154 @@
+package locale
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Keyword is the value of one keyword of a definition, as the locale utility
+// reports it
+type Keyword struct {
+	Category string
+	Name     string
+	Value    interface{} // A string, an int, a []string or a []int
+}
+
+// String returns the value alone. The items of a list are separated by
+// semicolons.
+func (k Keyword) String() string {
+	switch v := k.Value.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case []string:
+		return strings.Join(v, ";")
+	case []int:
+		if v == nil {
+			return "-1"
+		}
+		s := make([]string, len(v))
+		for i, n := range v {
+			s[i] = strconv.Itoa(n)
+		}
+		return strings.Join(s, ";")
+	}
+	return ""
+}
+
+// Assignment returns the keyword as name=value. String values are quoted, so
+// that the output can be read by the shell.
+func (k Keyword) Assignment() string {
+	switch k.Value.(type) {
+	case string, []string:
+		return k.Name + "=" + quote(k.String())
+	}
+	return k.Name + "=" + k.String()
+}
+
+// quote puts s in double quotes, escaping the characters special inside them
+func quote(s string) string {
+	var out strings.Builder
+	out.WriteByte('"')
+	for _, c := range s {
+		switch c {
+		case '"', '\\', '$', '`':
+			out.WriteByte('\\')
+		}
+		out.WriteRune(c)
+	}
+	out.WriteByte('"')
+	return out.String()
+}
+
+// keywords lists the keywords that can be queried, in the order they are
+// shown for each category
+var keywords = []struct {
+	category string
+	name     string
+	value    func(Def) interface{}
+}{
+	{"LC_CTYPE", "charmap", func(d Def) interface{} { return d.Ctype.codeset }},
+
+	{"LC_MONETARY", "int_curr_symbol", func(d Def) interface{} { return d.Monetary.intCurrSymbol }},
+	{"LC_MONETARY", "currency_symbol", func(d Def) interface{} { return d.Monetary.currencySymbol }},
+	{"LC_MONETARY", "mon_decimal_point", func(d Def) interface{} { return d.Monetary.monDecimalPoint }},
+	{"LC_MONETARY", "mon_thousands_sep", func(d Def) interface{} { return d.Monetary.monThousandsSep }},
+	{"LC_MONETARY", "mon_grouping", func(d Def) interface{} { return d.Monetary.monGrouping }},
+	{"LC_MONETARY", "positive_sign", func(d Def) interface{} { return d.Monetary.positiveSign }},
+	{"LC_MONETARY", "negative_sign", func(d Def) interface{} { return d.Monetary.negativeSign }},
+	{"LC_MONETARY", "int_frac_digits", func(d Def) interface{} { return d.Monetary.intFracDigits }},
+	{"LC_MONETARY", "frac_digits", func(d Def) interface{} { return d.Monetary.fracDigits }},
+	{"LC_MONETARY", "p_cs_precedes", func(d Def) interface{} { return d.Monetary.pCsPrecedes }},
+	{"LC_MONETARY", "p_sep_by_space", func(d Def) interface{} { return d.Monetary.pSepBySpace }},
+	{"LC_MONETARY", "n_cs_precedes", func(d Def) interface{} { return d.Monetary.nCsPrecedes }},
+	{"LC_MONETARY", "n_sep_by_space", func(d Def) interface{} { return d.Monetary.nSepBySpace }},
+	{"LC_MONETARY", "p_sign_posn", func(d Def) interface{} { return d.Monetary.pSignPosn }},
+	{"LC_MONETARY", "n_sign_posn", func(d Def) interface{} { return d.Monetary.nSignPosn }},
+	{"LC_MONETARY", "int_p_cs_precedes", func(d Def) interface{} { return d.Monetary.intPcsPrecedes }},
+	{"LC_MONETARY", "int_p_sep_by_space", func(d Def) interface{} { return d.Monetary.intPsepBySpace }},
+	{"LC_MONETARY", "int_n_cs_precedes", func(d Def) interface{} { return d.Monetary.intNcsPrecedes }},
+	{"LC_MONETARY", "int_n_sep_by_space", func(d Def) interface{} { return d.Monetary.intNsepBySpace }},
+	{"LC_MONETARY", "int_p_sign_posn", func(d Def) interface{} { return d.Monetary.intPsignPosn }},
+	{"LC_MONETARY", "int_n_sign_posn", func(d Def) interface{} { return d.Monetary.intNsignPosn }},
+
+	{"LC_NUMERIC", "decimal_point", func(d Def) interface{} { return d.Numeric.decimalPoint }},
+	{"LC_NUMERIC", "thousands_sep", func(d Def) interface{} { return d.Numeric.thousandsSep }},
+	{"LC_NUMERIC", "grouping", func(d Def) interface{} { return d.Numeric.grouping }},
+
+	{"LC_TIME", "abday", func(d Def) interface{} { return d.Time.abday }},
+	{"LC_TIME", "day", func(d Def) interface{} { return d.Time.day }},
+	{"LC_TIME", "abmon", func(d Def) interface{} { return d.Time.abmon }},
+	{"LC_TIME", "mon", func(d Def) interface{} { return d.Time.mon }},
+	{"LC_TIME", "d_t_fmt", func(d Def) interface{} { return d.Time.dtFmt }},
+	{"LC_TIME", "d_fmt", func(d Def) interface{} { return d.Time.dFmt }},
+	{"LC_TIME", "t_fmt", func(d Def) interface{} { return d.Time.tFmt }},
+	{"LC_TIME", "am_pm", func(d Def) interface{} { return []string{d.Time.am, d.Time.pm} }},
+	{"LC_TIME", "t_fmt_ampm", func(d Def) interface{} { return d.Time.tFmtAmPm }},
+	{"LC_TIME", "era", func(d Def) interface{} {
+		eras := make([]string, len(d.Time.eras))
+		for i, era := range d.Time.eras {
+			eras[i] = era.String()
+		}
+		return eras
+	}},
+	{"LC_TIME", "era_d_fmt", func(d Def) interface{} { return d.Time.eraDFmt }},
+	{"LC_TIME", "era_t_fmt", func(d Def) interface{} { return d.Time.eraTFmt }},
+	{"LC_TIME", "era_d_t_fmt", func(d Def) interface{} { return d.Time.eraDTFmt }},
+	{"LC_TIME", "alt_digits", func(d Def) interface{} { return d.Time.altDigits }},
+
+	{"LC_MESSAGES", "yesexpr", func(d Def) interface{} { return d.Messages.yesexpr }},
+	{"LC_MESSAGES", "noexpr", func(d Def) interface{} { return d.Messages.noexpr }},
+}
+
+// Keyword looks up a keyword by name
+func (def Def) Keyword(name string) (Keyword, bool) {
+	for _, k := range keywords {
+		if k.name == name {
+			return Keyword{k.category, k.name, k.value(def)}, true
+		}
+	}
+	return Keyword{}, false
+}
+
+// Keywords returns every keyword of a category. LC_COLLATE has none that can
+// be shown.
+func (def Def) Keywords(category string) []Keyword {
+	var out []Keyword
+	for _, k := range keywords {
+		if k.category == category {
+			out = append(out, Keyword{k.category, k.name, k.value(def)})
+		}
+	}
+	return out
+}
+
+// IsCategory reports whether name is one of Categories
+func IsCategory(name string) bool {
+	for _, c := range Categories {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
@@ -18,40 +18,34 @@ type Locale struct {
 
 // Def is the settings & stuff in a locale
 type Def struct {
-	monetary Monetary
-	ctype    Ctype
-	time     Time
-	numeric  Numeric
-	collate  Collate
+	Monetary Monetary
+	Ctype    Ctype
+	Time     Time
+	Numeric  Numeric
+	Collate  Collate
+	Messages Messages
+
+	// Copies maps the name of each category defined with the copy keyword to
+	// the locale it was copied from
+	Copies map[string]string
 }
 
-// getVal will process overrides or fallbacks as necessary
-func (l Locale) getVal(val string) string {
-	if l.all != "" {
-		return "\"" + l.all + "\""
-	}
-	if val != "" {
-		return val
+// getVal returns a category's value as the locale utility shows it. A value
+// set by the category's own variable is shown as it is; one implied by LC_ALL,
+// LANG or the default is quoted.
+func (l Locale) getVal(category string) string {
+	if v := l.value(category); v != "" && l.all == "" {
+		return v
 	}
-	return "\"" + l.lang + "\""
+	return "\"" + l.Name(category) + "\""
 }
 
 func (l Locale) String() string {
 	var out strings.Builder
-	out.WriteString(fmt.Sprintf("LANG=\"%s\"\n", l.lang))
-	settings := []struct{ name, val string }{
-		{"LC_COLLATE", l.collate},
-		{"LC_CTYPE", l.ctype},
-		{"LC_MESSAGES", l.messages},
-		{"LC_MONETARY", l.monetary},
-		{"LC_NUMERIC", l.numeric},
-		{"LC_TIME", l.time},
+	out.WriteString(fmt.Sprintf("LANG=%s\n", l.lang))
+	for _, category := range Categories {
+		out.WriteString(fmt.Sprintf("%s=%s\n", category, l.getVal(category)))
 	}
-
-	for _, setting := range settings {
-		out.WriteString(fmt.Sprintf("%s=%s\n", setting.name, l.getVal(setting.val)))
-	}
-
 	out.WriteString(fmt.Sprintf("LC_ALL=%s\n", l.all))
 	return out.String()
 }
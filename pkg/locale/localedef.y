@@ -1,67 +1,42 @@
 %{
 package locale
 
-import (
-  "strconv"
-  "strings"
-)
-var def Def
-
-// TODO: Why don't the tokens have the value I expect in tok.yys?
-func assignMonetaryString (tok yySymType, value string) {
-  switch tok.val {
-  case "int_curr_symbol":
-    def.monetary.intCurrSymbol = value
-  }
-}
-
-func assignMonetaryNumber (tok int, value string) {
-  n, err := strconv.Atoi(value)
-  if err != nil {
-    panic("Can't convert " + value + " to integer")
-  }
-  switch tok {
-  case INT_FRAC_DIGITS_STR:
-    def.monetary.intFracDigits = n
-  default:
-    //panic("NYI" + value)
-  }
-}
-
-func assignMonetaryGrouping(tok int, value string) {
-  vals := strings.Split(value, ";")
-  g := make([]int, len(vals))
-  var err error
-  for i, v := range vals {
-    g[i], err = strconv.Atoi(v)
-    if err != nil {
-      panic("Err: " + err.Error())
-    }
-  }
-  def.monetary.monGrouping = g
+// lex returns the Lexer driving the parse, which holds the definition being
+// built
+func lex(yylex yyLexer) *Lexer {
+  return yylex.(*Lexer)
 }
 
 %}
 %union{
-  val string
+  val     string     // The text of a word or keyword
+  tok     int        // The token type of a keyword
+  pos     position   // Where the token started
+  num     int
+  ints    []int
+  names   []string
+  sym     symbol
+  syms    []symbol
+  strs    [][]symbol
+  dir     direction
+  dirs    []direction
+  weights [][]symbol
 }
 
-%token              LOC_NAME
-%token              CHAR
+%token              CHAR CHARSYMBOL
+%token              STRING
 %token              NUMBER
-%token              COLLSYMBOL COLLELEMENT
-%token              CHARSYMBOL OCTAL_CHAR HEX_CHAR DECIMAL_CHAR
+%token              WORD
 %token              CHARCLASS
 %token              ELLIPSIS
-%token              EXTENDED_REG_EXP
 %token              EOL
+%token              LEX_ERROR
 
-%token ESCAPE_CHAR_STR
-%token COMMENT_CHAR_STR
 %token COPY_STR
+%token END_STR
+
 %token LC_CTYPE_STR
 %token CHARCLASS_STR
-
 %token UPPER_STR
 %token LOWER_STR
 %token ALPHA_STR
@@ -76,97 +51,76 @@ func assignMonetaryGrouping(tok int, value string) {
 %token ALNUM_STR
 %token TOUPPER_STR
 %token TOLOWER_STR
-%token COPY_STR
+
 %token LC_COLLATE_STR
 %token COLLATING_SYMBOL_STR
 %token COLLATING_ELEMENT_STR
+%token FROM_STR
 %token ORDER_START_STR
-%token ORDER_START_STR
+%token ORDER_END_STR
 %token FORWARD_STR
 %token BACKWARD_STR
 %token POSITION_STR
 %token UNDEFINED_STR
 %token IGNORE_STR
-%token END_STR
+
 %token LC_TIME_STR
-%token ERA_STR
-%token ERA_D_FMT_STR
-%token ERA_T_FMT_STR
-%token ALT_DIGITS_STR
-%token ERA_D_T_FMT_STR
-%token AM_PM_STR
-%token D_T_FMT_STR
-%token D_FMT_STR
-%token T_FMT_STR
-%token T_FMT_AMPM_STR
 %token ABDAY_STR
 %token DAY_STR
 %token ABMON_STR
 %token MON_STR
-%token LC_TIME_STR
-%token END_STR
+%token D_T_FMT_STR
+%token D_FMT_STR
+%token T_FMT_STR
+%token AM_PM_STR
+%token T_FMT_AMPM_STR
+%token ERA_STR
+%token ERA_D_FMT_STR
+%token ERA_T_FMT_STR
+%token ERA_D_T_FMT_STR
+%token ALT_DIGITS_STR
+
 %token LC_NUMERIC_STR
 %token DECIMAL_POINT_STR
 %token THOUSANDS_SEP_STR
-%token LC_NUMERIC_STR
-%token COPY_STR
+%token GROUPING_STR
+
 %token LC_MONETARY_STR
-%token END_STR
-%token INT_P_SIGN_POSN_STR
-%token INT_N_CS_PRECEDES_STR
-%token INT_P_CS_PRECEDES_STR
-%token P_SIGN_POSN_STR
-%token N_CS_PRECEDES_STR
-%token P_CS_PRECEDES_STR
+%token INT_CURR_SYMBOL_STR
+%token CURRENCY_SYMBOL_STR
+%token MON_DECIMAL_POINT_STR
+%token MON_THOUSANDS_SEP_STR
+%token MON_GROUPING_STR
+%token POSITIVE_SIGN_STR
+%token NEGATIVE_SIGN_STR
 %token INT_FRAC_DIGITS_STR
 %token FRAC_DIGITS_STR
+%token P_CS_PRECEDES_STR
 %token P_SEP_BY_SPACE_STR
+%token N_CS_PRECEDES_STR
 %token N_SEP_BY_SPACE_STR
+%token P_SIGN_POSN_STR
 %token N_SIGN_POSN_STR
+%token INT_P_CS_PRECEDES_STR
 %token INT_P_SEP_BY_SPACE_STR
+%token INT_N_CS_PRECEDES_STR
 %token INT_N_SEP_BY_SPACE_STR
+%token INT_P_SIGN_POSN_STR
 %token INT_N_SIGN_POSN_STR
-%token NEGATIVE_SIGN_STR
-%token POSITIVE_SIGN_STR
-%token MON_DECIMAL_POINT_STR
-%token INT_CURR_SYMBOL_STR
-%token CURRENCY_SYMBOL_STR
-%token MON_THOUSANDS_SEP_STR
-%token LC_MONETARY_STR
-%token END_STR
+
 %token LC_MESSAGES_STR
-%token NOEXPR_STR
 %token YESEXPR_STR
-%token LC_MESSAGES_STR
-%token END_STR
-%token LC_CTYPE_STR
-%token FROM_STR
-%token ORDER_END_STR
-%token END_STR
-%token LC_COLLATE_STR
-%token COPY_STR
-%token COPY_STR
-%token MON_GROUPING_STR
-%token GROUPING_STR
-%token COPY_STR
-%token NEGATIVE_ONE_STR
-%token TWO_DOUBLE_QUOTE_STR
+%token NOEXPR_STR
+
 %start              locale_definition
 
 %%
 
-locale_definition   : global_statements locale_categories
-                    |                   locale_categories
-                    ;
-
+/* escape_char and comment_char are handled by the lexer, as they change how
+   the rest of the input is split into tokens */
 
-global_statements   : global_statements symbol_redefine
-                    | symbol_redefine
-                    ;
-
-
-symbol_redefine     : ESCAPE_CHAR_STR  CHAR EOL
-                    | COMMENT_CHAR_STR CHAR EOL
+locale_definition   : locale_categories
+                    | /* empty */
                     ;
 
 
@@ -183,44 +137,34 @@ locale_category     : lc_ctype | lc_collate | lc_messages
 /* The following grammar rules are common to all categories */
 
 
-char_list           : char_list char_symbol {$$.val = $1.val + string($2.val)}
-                    | char_symbol
-                    ;
-
-
 char_symbol         : CHAR | CHARSYMBOL
-                    | OCTAL_CHAR | HEX_CHAR | DECIMAL_CHAR
                     ;
 
 
-elem_list           : elem_list char_symbol
-                    | elem_list COLLSYMBOL
-                    | elem_list COLLELEMENT
-                    | char_symbol
-                    | COLLSYMBOL
-                    | COLLELEMENT
+copy_line           : COPY_STR STRING EOL { $$ = $2 }
                     ;
 
 
-symb_list           : symb_list COLLSYMBOL
-                    | COLLSYMBOL
+group_list          : group_list ';' NUMBER { $$.ints = append($1.ints, $3.num) }
+                    | NUMBER                { $$.ints = []int{$1.num} }
                     ;
 
 
-locale_name         : LOC_NAME
-                    | '"' LOC_NAME '"'
+string_list         : string_list ';' STRING { $$.strs = append($1.strs, $3.syms) }
+                    | STRING                 { $$.strs = [][]symbol{$1.syms} }
                     ;
 
 
 /* The following is the LC_CTYPE category grammar */
 
 
-lc_ctype            : ctype_hdr ctype_keywords         ctype_tlr
-                    | ctype_hdr COPY_STR locale_name EOL ctype_tlr
+lc_ctype            : ctype_hdr ctype_keywords ctype_tlr
+                    | ctype_hdr copy_line      ctype_tlr { lex(yylex).copy($1, $2) }
+                    | ctype_hdr                ctype_tlr
                     ;
 
 
-ctype_hdr           : LC_CTYPE_STR EOL
+ctype_hdr           : LC_CTYPE_STR EOL { lex(yylex).begin($1) }
                     ;
 
 
@@ -229,14 +173,14 @@ ctype_keywords      : ctype_keywords ctype_keyword
                     ;
 
 
-ctype_keyword       : charclass_keyword charclass_list EOL
-                    | charconv_keyword charconv_list EOL
-                    | CHARCLASS_STR charclass_namelist EOL
+ctype_keyword       : charclass_keyword charclass_list EOL { lex(yylex).addClass($1, $2.syms) }
+                    | charconv_keyword charconv_list EOL   { lex(yylex).addConversion($1, $2.syms) }
+                    | CHARCLASS_STR charclass_namelist EOL { lex(yylex).declareClasses($2.names) }
                     ;
 
 
-charclass_namelist  : charclass_namelist ';' CHARCLASS
-                    | CHARCLASS
+charclass_namelist  : charclass_namelist ';' WORD { $$.names = append($1.names, $3.val) }
+                    | WORD                        { $$.names = []string{$1.val} }
                     ;
 
 
@@ -248,8 +192,11 @@ charclass_keyword   : UPPER_STR | LOWER_STR | ALPHA_STR | DIGIT_STR
 
 
 charclass_list      : charclass_list ';' char_symbol
+                      { $$.syms = append($1.syms, $3.sym) }
                     | charclass_list ';' ELLIPSIS ';' char_symbol
+                      { $$.syms = append($1.syms, symbol{word: "...", pos: $3.pos}, $5.sym) }
                     | char_symbol
+                      { $$.syms = []symbol{$1.sym} }
                     ;
 
 
@@ -258,12 +205,12 @@ charconv_keyword    : TOUPPER_STR
                     ;
 
 
-charconv_list       : charconv_list ';' charconv_entry
+charconv_list       : charconv_list ';' charconv_entry { $$.syms = append($1.syms, $3.syms...) }
                     | charconv_entry
                     ;
 
 
-charconv_entry      : '(' char_symbol ',' char_symbol ')'
+charconv_entry      : '(' char_symbol ',' char_symbol ')' { $$.syms = []symbol{$2.sym, $4.sym} }
                     ;
 
 
@@ -274,56 +221,52 @@ ctype_tlr           : END_STR LC_CTYPE_STR EOL
 /* The following is the LC_COLLATE category grammar */
 
 
-lc_collate          : collate_hdr collate_keywords       collate_tlr
-                    | collate_hdr COPY_STR locale_name EOL collate_tlr
-                    ;
-
-
-collate_hdr         : LC_COLLATE_STR EOL
-                    ;
-
-
-collate_keywords    :                order_statements
-                    | opt_statements order_statements
-                    ;
-
-
-opt_statements      : opt_statements collating_symbols
-                    | opt_statements collating_elements
-                    | collating_symbols
-                    | collating_elements
+lc_collate          : collate_hdr collate_keywords collate_tlr
+                    | collate_hdr copy_line        collate_tlr { lex(yylex).copy($1, $2) }
+                    | collate_hdr                  collate_tlr
                     ;
 
 
-collating_symbols   : COLLATING_SYMBOL_STR COLLSYMBOL EOL
+collate_hdr         : LC_COLLATE_STR EOL { lex(yylex).begin($1) }
                     ;
 
 
-collating_elements  : COLLATING_ELEMENT_STR COLLELEMENT
-                    | FROM_STR '"' elem_list '"' EOL
+collate_keywords    : collate_keywords collate_keyword
+                    | collate_keyword
                     ;
 
 
-order_statements    : order_start collation_order order_end
+collate_keyword     : COLLATING_SYMBOL_STR CHARSYMBOL EOL
+                      { lex(yylex).collatingSymbol($2.sym) }
+                    | COLLATING_ELEMENT_STR CHARSYMBOL FROM_STR STRING EOL
+                      { lex(yylex).collatingElement($2.sym, $4.syms) }
+                    | order_start collation_order order_end
+                    | order_start                 order_end
                     ;
 
 
-order_start         : ORDER_START_STR EOL
-                    | ORDER_START_STR order_opts EOL
+order_start         : ORDER_START_STR EOL            { lex(yylex).orderStart(nil) }
+                    | ORDER_START_STR order_opts EOL { lex(yylex).orderStart($2.dirs) }
                     ;
 
 
-order_opts          : order_opts ';' order_opt
-                    | order_opt
+order_opts          : order_opts ';' order_opt { $$.dirs = append($1.dirs, $3.dir) }
+                    | order_opt                { $$.dirs = []direction{$1.dir} }
                     ;
 
 
 order_opt           : order_opt ',' opt_word
+                      {
+                        $$.dir.backward = $1.dir.backward || $3.dir.backward
+                        $$.dir.position = $1.dir.position || $3.dir.position
+                      }
                     | opt_word
                     ;
 
 
-opt_word            : FORWARD_STR | BACKWARD_STR | POSITION_STR
+opt_word            : FORWARD_STR  { $$.dir = direction{} }
+                    | BACKWARD_STR { $$.dir = direction{backward: true} }
+                    | POSITION_STR { $$.dir = direction{position: true} }
                     ;
 
 
@@ -332,32 +275,26 @@ collation_order     : collation_order collation_entry
                     ;
 
 
-collation_entry     : COLLSYMBOL EOL
-                    | collation_element weight_list EOL
-                    | collation_element             EOL
+collation_entry     : collation_element weight_list EOL { lex(yylex).orderEntry($1.sym, $2.weights) }
                     ;
 
 
 collation_element   : char_symbol
-                    | COLLELEMENT
-                    | ELLIPSIS
-                    | UNDEFINED_STR
+                    | ELLIPSIS      { $$.sym = symbol{word: "...", pos: $1.pos} }
+                    | UNDEFINED_STR { $$.sym = symbol{word: "UNDEFINED", pos: $1.pos} }
                     ;
 
 
-weight_list         : weight_list ';' weight_symbol
-                    | weight_list ';'
-                    | weight_symbol
+weight_list         : weight_list ';' weight_symbol { $$.weights = append($1.weights, $3.syms) }
+                    | weight_symbol                 { $$.weights = [][]symbol{$1.syms} }
                     ;
 
 
-weight_symbol       : /* empty */
-                    | char_symbol
-                    | COLLSYMBOL
-                    | '"' elem_list '"'
-                    | '"' symb_list '"'
-                    | ELLIPSIS
-                    | IGNORE_STR
+weight_symbol       : /* empty */ { $$.syms = nil }
+                    | char_symbol { $$.syms = []symbol{$1.sym} }
+                    | STRING
+                    | ELLIPSIS    { $$.syms = []symbol{{word: "...", pos: $1.pos}} }
+                    | IGNORE_STR  { $$.syms = []symbol{{word: "IGNORE", pos: $1.pos}} }
                     ;
 
 
@@ -372,12 +309,13 @@ collate_tlr         : END_STR LC_COLLATE_STR EOL
 /* The following is the LC_MESSAGES category grammar */
 
 
-lc_messages         : messages_hdr messages_keywords      messages_tlr
-                    | messages_hdr COPY_STR locale_name EOL messages_tlr
+lc_messages         : messages_hdr messages_keywords messages_tlr
+                    | messages_hdr copy_line         messages_tlr { lex(yylex).copy($1, $2) }
+                    | messages_hdr                   messages_tlr
                     ;
 
 
-messages_hdr        : LC_MESSAGES_STR EOL
+messages_hdr        : LC_MESSAGES_STR EOL { lex(yylex).begin($1) }
                     ;
 
 
@@ -386,8 +324,11 @@ messages_keywords   : messages_keywords messages_keyword
                     ;
 
 
-messages_keyword    : YESEXPR_STR '"' EXTENDED_REG_EXP '"' EOL
-                    | NOEXPR_STR  '"' EXTENDED_REG_EXP '"' EOL
+messages_keyword    : messages_keyword_name STRING EOL { lex(yylex).setMessages($1, $2.syms) }
+                    ;
+
+
+messages_keyword_name : YESEXPR_STR | NOEXPR_STR
                     ;
 
 
@@ -398,12 +339,13 @@ messages_tlr        : END_STR LC_MESSAGES_STR EOL
 /* The following is the LC_MONETARY category grammar */
 
 
-lc_monetary         : monetary_hdr monetary_keywords       monetary_tlr
-                    | monetary_hdr COPY_STR locale_name EOL  monetary_tlr
+lc_monetary         : monetary_hdr monetary_keywords monetary_tlr
+                    | monetary_hdr copy_line         monetary_tlr { lex(yylex).copy($1, $2) }
+                    | monetary_hdr                   monetary_tlr
                     ;
 
 
-monetary_hdr        : LC_MONETARY_STR EOL
+monetary_hdr        : LC_MONETARY_STR EOL { lex(yylex).begin($1) }
                     ;
 
 
@@ -412,10 +354,9 @@ monetary_keywords   : monetary_keywords monetary_keyword
                     ;
 
 
-monetary_keyword    : mon_keyword_string mon_string EOL       {assignMonetaryString($1, $2.val)}
-                    | mon_keyword_char NUMBER EOL             {assignMonetaryNumber($1.yys, $2.val)}
-                    | mon_keyword_char NEGATIVE_ONE_STR   EOL {assignMonetaryNumber($1.yys, "-1")}
-                    | mon_keyword_grouping mon_group_list EOL {assignMonetaryGrouping($1.yys, $2.val)}
+monetary_keyword    : mon_keyword_string STRING EOL       { lex(yylex).setMonetaryString($1, $2.syms) }
+                    | mon_keyword_char NUMBER EOL         { lex(yylex).setMonetaryNumber($1, $2.num) }
+                    | MON_GROUPING_STR group_list EOL     { lex(yylex).def.Monetary.monGrouping = grouping($2.ints) }
                     ;
 
 
@@ -425,11 +366,6 @@ mon_keyword_string  : INT_CURR_SYMBOL_STR | CURRENCY_SYMBOL_STR
                     ;
 
 
-mon_string          : '"' char_list '"' { $$ = $2 }
-                    | TWO_DOUBLE_QUOTE_STR
-                    ;
-
-
 mon_keyword_char    : INT_FRAC_DIGITS_STR | FRAC_DIGITS_STR
                     | P_CS_PRECEDES_STR | P_SEP_BY_SPACE_STR
                     | N_CS_PRECEDES_STR | N_SEP_BY_SPACE_STR
@@ -440,15 +376,6 @@ mon_keyword_char    : INT_FRAC_DIGITS_STR | FRAC_DIGITS_STR
                     ;
 
 
-mon_keyword_grouping : MON_GROUPING_STR
-                    ;
-
-
-mon_group_list      : NUMBER
-                    | mon_group_list ';' NUMBER { $$.val = $1.val + ";" + $3.val }
-                    ;
-
-
 monetary_tlr        : END_STR LC_MONETARY_STR EOL
                     ;
 
@@ -456,12 +383,13 @@ monetary_tlr        : END_STR LC_MONETARY_STR EOL
 /* The following is the LC_NUMERIC category grammar */
 
 
-lc_numeric          : numeric_hdr numeric_keywords       numeric_tlr
-                    | numeric_hdr COPY_STR locale_name EOL numeric_tlr
+lc_numeric          : numeric_hdr numeric_keywords numeric_tlr
+                    | numeric_hdr copy_line        numeric_tlr { lex(yylex).copy($1, $2) }
+                    | numeric_hdr                  numeric_tlr
                     ;
 
 
-numeric_hdr         : LC_NUMERIC_STR EOL
+numeric_hdr         : LC_NUMERIC_STR EOL { lex(yylex).begin($1) }
                     ;
 
 
@@ -470,8 +398,8 @@ numeric_keywords    : numeric_keywords numeric_keyword
                     ;
 
 
-numeric_keyword     : num_keyword_string num_string EOL
-                    | num_keyword_grouping num_group_list EOL
+numeric_keyword     : num_keyword_string STRING EOL { lex(yylex).setNumericString($1, $2.syms) }
+                    | GROUPING_STR group_list EOL   { lex(yylex).def.Numeric.grouping = grouping($2.ints) }
                     ;
 
 
@@ -480,20 +408,6 @@ num_keyword_string  : DECIMAL_POINT_STR
                     ;
 
 
-num_string          : '"' char_list '"'
-                    | TWO_DOUBLE_QUOTE_STR
-                    ;
-
-
-num_keyword_grouping: GROUPING_STR
-                    ;
-
-
-num_group_list      : NUMBER
-                    | num_group_list ';' NUMBER
-                    ;
-
-
 numeric_tlr         : END_STR LC_NUMERIC_STR EOL
                     ;
 
@@ -501,12 +415,13 @@ numeric_tlr         : END_STR LC_NUMERIC_STR EOL
 /* The following is the LC_TIME category grammar */
 
 
-lc_time             : time_hdr time_keywords          time_tlr
-                    | time_hdr COPY_STR locale_name EOL time_tlr
+lc_time             : time_hdr time_keywords time_tlr
+                    | time_hdr copy_line     time_tlr { lex(yylex).copy($1, $2) }
+                    | time_hdr               time_tlr
                     ;
 
 
-time_hdr            : LC_TIME_STR EOL
+time_hdr            : LC_TIME_STR EOL { lex(yylex).begin($1) }
                     ;
 
 
@@ -515,34 +430,16 @@ time_keywords       : time_keywords time_keyword
                     ;
 
 
-time_keyword        : time_keyword_name time_list EOL
-                    | time_keyword_fmt time_string EOL
-                    | time_keyword_opt time_list EOL
+time_keyword        : time_keyword_name string_list EOL { lex(yylex).setTime($1, $2.strs) }
                     ;
 
 
 time_keyword_name   : AM_PM_STR | ABDAY_STR | DAY_STR | ABMON_STR | MON_STR
-                    ;
-
-
-time_keyword_fmt    : D_T_FMT_STR | D_FMT_STR | T_FMT_STR
-                    |  T_FMT_AMPM_STR
-                    ;
-
-
-time_keyword_opt    : ERA_STR | ERA_D_FMT_STR | ERA_T_FMT_STR
+                    | D_T_FMT_STR | D_FMT_STR | T_FMT_STR | T_FMT_AMPM_STR
+                    | ERA_STR | ERA_D_FMT_STR | ERA_T_FMT_STR
                     | ERA_D_T_FMT_STR | ALT_DIGITS_STR
                     ;
 
 
-time_list           : time_list ';' time_string
-                    | time_string
-                    ;
-
-
-time_string         : '"' char_list '"'
-                    ;
-
-
 time_tlr            : END_STR LC_TIME_STR EOL
                     ;
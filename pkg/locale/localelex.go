@@ -4,549 +4,595 @@ package locale
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
-
+	"unicode"
 	"unicode/utf8"
-
-	"github.com/bbuck/go-lexer"
 )
 
-const upperChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ_"
-const lowerChars = "abcdefghijklmnopqrstuvwxyz_"
-const digits = "0123456789"
-const whiteSpace = " \t"
-const newLine = "\n\r"
-
-type decode struct {
-	typ  lexer.TokenType
-	grab grabFunc
+// Category mappings
+var categoryMap = map[string]int{
+	"LC_CTYPE":    LC_CTYPE_STR,
+	"LC_COLLATE":  LC_COLLATE_STR,
+	"LC_MONETARY": LC_MONETARY_STR,
+	"LC_NUMERIC":  LC_NUMERIC_STR,
+	"LC_TIME":     LC_TIME_STR,
+	"LC_MESSAGES": LC_MESSAGES_STR,
 }
 
-var escapeChar = '\\'
-
 // Ctype mappings
-var ctypeMap = map[string]decode{
-	"upper":     {UPPER_STR, grabCharList},
-	"lower":     {LOWER_STR, grabCharList},
-	"alpha":     {ALPHA_STR, grabCharList},
-	"digit":     {DIGIT_STR, grabCharList},
-	"alnum":     {ALNUM_STR, grabCharList},
-	"space":     {SPACE_STR, grabCharList},
-	"cntrl":     {CNTRL_STR, grabCharList},
-	"punct":     {PUNCT_STR, grabCharList},
-	"graph":     {GRAPH_STR, grabCharList},
-	"print":     {PRINT_STR, grabCharList},
-	"xdigit":    {XDIGIT_STR, grabCharList},
-	"blank":     {BLANK_STR, grabCharList},
-	"charclass": {CHARCLASS_STR, grabStringList},
-	"toupper":   {TOUPPER_STR, grabCharPairList},
-	"tolower":   {TOLOWER_STR, grabCharPairList},
+var ctypeMap = map[string]int{
+	"upper":     UPPER_STR,
+	"lower":     LOWER_STR,
+	"alpha":     ALPHA_STR,
+	"digit":     DIGIT_STR,
+	"alnum":     ALNUM_STR,
+	"space":     SPACE_STR,
+	"cntrl":     CNTRL_STR,
+	"punct":     PUNCT_STR,
+	"graph":     GRAPH_STR,
+	"print":     PRINT_STR,
+	"xdigit":    XDIGIT_STR,
+	"blank":     BLANK_STR,
+	"charclass": CHARCLASS_STR,
+	"toupper":   TOUPPER_STR,
+	"tolower":   TOLOWER_STR,
+}
+
+// Collate mappings
+var collateMap = map[string]int{
+	"collating-element": COLLATING_ELEMENT_STR,
+	"collating-symbol":  COLLATING_SYMBOL_STR,
+	"order_start":       ORDER_START_STR,
+	"order_end":         ORDER_END_STR,
+	"UNDEFINED":         UNDEFINED_STR,
 }
 
 // Time mappings
-var timeMap = map[string]decode{
-	"abday":       {ABDAY_STR, grabStringList},
-	"day":         {DAY_STR, grabStringList},
-	"abmon":       {ABMON_STR, grabStringList},
-	"mon":         {MON_STR, grabStringList},
-	"d_t_fmt":     {D_T_FMT_STR, grabString},
-	"d_fmt":       {D_FMT_STR, grabString},
-	"t_fmt":       {T_FMT_STR, grabString},
-	"am_pm":       {AM_PM_STR, grabStringList},
-	"t_fmt_ampm":  {T_FMT_AMPM_STR, grabString},
-	"era":         {ERA_STR, grabStringList},
-	"era_d_fmt":   {ERA_D_FMT_STR, grabString},
-	"era_t_fmt":   {ERA_T_FMT_STR, grabString},
-	"era_d_t_fmt": {ERA_D_T_FMT_STR, grabString},
-	"alt_digits":  {ALT_DIGITS_STR, grabStringList},
+var timeMap = map[string]int{
+	"abday":       ABDAY_STR,
+	"day":         DAY_STR,
+	"abmon":       ABMON_STR,
+	"mon":         MON_STR,
+	"d_t_fmt":     D_T_FMT_STR,
+	"d_fmt":       D_FMT_STR,
+	"t_fmt":       T_FMT_STR,
+	"am_pm":       AM_PM_STR,
+	"t_fmt_ampm":  T_FMT_AMPM_STR,
+	"era":         ERA_STR,
+	"era_d_fmt":   ERA_D_FMT_STR,
+	"era_t_fmt":   ERA_T_FMT_STR,
+	"era_d_t_fmt": ERA_D_T_FMT_STR,
+	"alt_digits":  ALT_DIGITS_STR,
 }
 
 // Numeric mappings
-var numericMap = map[string]decode{
-	"decimal_point": {DECIMAL_POINT_STR, grabString},
-	"thousands_sep": {THOUSANDS_SEP_STR, grabString},
-	"grouping":      {GROUPING_STR, grabInt},
+var numericMap = map[string]int{
+	"decimal_point": DECIMAL_POINT_STR,
+	"thousands_sep": THOUSANDS_SEP_STR,
+	"grouping":      GROUPING_STR,
 }
 
 // Monetary mappings
-var monetaryMap = map[string]decode{
-	"int_curr_symbol":    {INT_CURR_SYMBOL_STR, grabString},
-	"currency_symbol":    {CURRENCY_SYMBOL_STR, grabString},
-	"mon_decimal_point":  {MON_DECIMAL_POINT_STR, grabString},
-	"mon_thousands_sep":  {MON_THOUSANDS_SEP_STR, grabString},
-	"mon_grouping":       {MON_GROUPING_STR, grabIntList},
-	"positive_sign":      {POSITIVE_SIGN_STR, grabString},
-	"negative_sign":      {NEGATIVE_SIGN_STR, grabString},
-	"int_frac_digits":    {INT_FRAC_DIGITS_STR, grabInt},
-	"frac_digits":        {FRAC_DIGITS_STR, grabInt},
-	"p_cs_precedes":      {P_CS_PRECEDES_STR, grabInt},
-	"p_sep_by_space":     {P_SEP_BY_SPACE_STR, grabInt},
-	"n_cs_precedes":      {N_CS_PRECEDES_STR, grabInt},
-	"n_sep_by_space":     {N_SEP_BY_SPACE_STR, grabInt},
-	"p_sign_posn":        {P_SIGN_POSN_STR, grabInt},
-	"n_sign_posn":        {N_SIGN_POSN_STR, grabInt},
-	"int_p_cs_precedes":  {INT_P_CS_PRECEDES_STR, grabInt},
-	"int_p_sep_by_space": {INT_P_SEP_BY_SPACE_STR, grabInt},
-	"int_n_cs_precedes":  {INT_N_CS_PRECEDES_STR, grabInt},
-	"int_n_sep_by_space": {INT_N_SEP_BY_SPACE_STR, grabInt},
-	"int_p_sign_posn":    {INT_P_SIGN_POSN_STR, grabInt},
-	"int_n_sign_posn":    {INT_N_SIGN_POSN_STR, grabInt},
+var monetaryMap = map[string]int{
+	"int_curr_symbol":    INT_CURR_SYMBOL_STR,
+	"currency_symbol":    CURRENCY_SYMBOL_STR,
+	"mon_decimal_point":  MON_DECIMAL_POINT_STR,
+	"mon_thousands_sep":  MON_THOUSANDS_SEP_STR,
+	"mon_grouping":       MON_GROUPING_STR,
+	"positive_sign":      POSITIVE_SIGN_STR,
+	"negative_sign":      NEGATIVE_SIGN_STR,
+	"int_frac_digits":    INT_FRAC_DIGITS_STR,
+	"frac_digits":        FRAC_DIGITS_STR,
+	"p_cs_precedes":      P_CS_PRECEDES_STR,
+	"p_sep_by_space":     P_SEP_BY_SPACE_STR,
+	"n_cs_precedes":      N_CS_PRECEDES_STR,
+	"n_sep_by_space":     N_SEP_BY_SPACE_STR,
+	"p_sign_posn":        P_SIGN_POSN_STR,
+	"n_sign_posn":        N_SIGN_POSN_STR,
+	"int_p_cs_precedes":  INT_P_CS_PRECEDES_STR,
+	"int_p_sep_by_space": INT_P_SEP_BY_SPACE_STR,
+	"int_n_cs_precedes":  INT_N_CS_PRECEDES_STR,
+	"int_n_sep_by_space": INT_N_SEP_BY_SPACE_STR,
+	"int_p_sign_posn":    INT_P_SIGN_POSN_STR,
+	"int_n_sign_posn":    INT_N_SIGN_POSN_STR,
 }
 
 // Message mappings
-var msgMap = map[string]decode{
-	"yesexpr": {YESEXPR_STR, grabMsg},
-	"noexpr":  {NOEXPR_STR, grabMsg},
+var msgMap = map[string]int{
+	"yesexpr": YESEXPR_STR,
+	"noexpr":  NOEXPR_STR,
+}
+
+// keywordMaps gives the keywords that may start a line in each category
+var keywordMaps = map[int]map[string]int{
+	LC_CTYPE_STR:    ctypeMap,
+	LC_COLLATE_STR:  collateMap,
+	LC_MONETARY_STR: monetaryMap,
+	LC_NUMERIC_STR:  numericMap,
+	LC_TIME_STR:     timeMap,
+	LC_MESSAGES_STR: msgMap,
+}
+
+// Words with a special meaning in the middle of a line
+var operandMap = map[string]int{
+	"from":     FROM_STR,
+	"forward":  FORWARD_STR,
+	"backward": BACKWARD_STR,
+	"position": POSITION_STR,
+	"IGNORE":   IGNORE_STR,
 }
 
-// WHITESPACE is a pseudo-token that the lexer doesn't want
-const WHITESPACE = 123987654
+// position is a place in the source, counted from 1
+type position struct {
+	line, col int
+}
+
+// symbol is a character as it was written in the source
+type symbol struct {
+	name string // The name between the angle brackets, for <name>
+	text string // The character, when written as itself or as escape sequences
+	word string // A keyword standing in for characters: "...", "UNDEFINED" or "IGNORE"
+	pos  position
+}
+
+// logicalLine is one or more source lines joined by the escape character
+type logicalLine struct {
+	text string
+	pos  []position // The source position of each byte of text
+	end  position   // The source position just past the end of text
+}
 
-// Lexer does some lexing, y'all
+// Lexer splits a locale definition into tokens, and builds up the Def as the
+// parser recognises them
 type Lexer struct {
-	lexer.L
-	def Def
+	lines []logicalLine
+	line  int  // Index of the current line
+	col   int  // Byte offset into the current line
+	begun bool // Whether the current line has produced a token yet
+
+	category     int // The category being read, or 0 between categories
+	categoryName string
+	keywords     map[string]int // Keywords that may start a line in the category
+	ending       bool           // The current line is an END line
+	naming       bool           // The current line declares character classes
+	ordering     bool           // Between order_start and order_end
+	classes      map[string]bool
+	last         position // Where the most recent token started
+	escape       rune
+	comment      rune
+	def          Def
+	defined      map[int]bool // Categories that have been seen
+	err          error
+	charmap      *Charmap
 }
 
-// Lex does the lex
-func (l *Lexer) Lex(lval *yySymType) int {
-	tok, done := l.NextToken()
-	if done {
-		return int(lexer.EOFRune)
-	}
-	// Ignore whitespace and try again
-	if tok.Type == WHITESPACE {
-		return l.Lex(lval)
-	}
-	lval.val = tok.Value
-	return int(tok.Type)
-}
-
-// sInit is the starting function for most lexers
-func sInit(l *lexer.L) lexer.StateFunc {
-	// We don't do escape/comment char processing here because it's preprocessed
-	return sCategory
-}
-
-func sCategory(l *lexer.L) lexer.StateFunc {
-	var next lexer.StateFunc
-	var typ lexer.TokenType
-
-	l.Take(upperChars + "_")
-	header := l.Current()
-	switch header {
-	case "LC_CTYPE":
-		typ = LC_CTYPE_STR
-		next = getsGenericCategory(header, typ, ctypeMap)
-	case "LC_COLLATE":
-		typ = LC_COLLATE_STR
-		next = sCollateCategory
-	case "LC_MONETARY":
-		typ = LC_MONETARY_STR
-		next = getsGenericCategory(header, typ, monetaryMap)
-	case "LC_NUMERIC":
-		typ = LC_NUMERIC_STR
-		next = getsGenericCategory(header, typ, numericMap)
-	case "LC_TIME":
-		typ = LC_TIME_STR
-		next = getsGenericCategory(header, typ, timeMap)
-	case "LC_MESSAGES":
-		typ = LC_MESSAGES_STR
-		next = getsGenericCategory(header, typ, msgMap)
-	}
-	if next == nil {
-		return nil
-	}
-	l.Emit(typ)
-	l.Take(whiteSpace)
-	must(grabNewline, l)
-	return next
-}
-
-// A grabFunc will consume and Emit tokens.
-// Returns true if any tokens are emitted, returns false otherwise
-type grabFunc func(l *lexer.L) (ok bool)
-
-// must should be used with 'grab' functions - if it doesn't grab it, it dies
-func must(grabber grabFunc, l *lexer.L) {
-	ok := grabber(l)
-	if !ok {
-		l.Error("oops")
-	}
-}
-
-func grabWhitespace(l *lexer.L) bool {
-	l.Take(whiteSpace)
-	if l.Current() == "" {
-		return false
-	}
-	l.Emit(WHITESPACE)
-	return true
-}
-func grabNewline(l *lexer.L) bool {
-	l.Take(newLine)
-	if l.Current() == "" {
-		return false
-	}
-	l.Emit(EOL)
-	return true
-}
-func grabChar(l *lexer.L) bool {
-	if l.Next() != '<' {
-		l.Rewind()
-		return false
-	}
-	l.Take(upperChars + lowerChars + "-" + "_" + digits)
-	if l.Next() != '>' {
-		for range l.Current() {
-			l.Rewind()
-		}
-		return false
+func (l *Lexer) current() logicalLine {
+	return l.lines[l.line]
+}
+
+// pos returns the source position of the byte at offset i in the current line
+func (l *Lexer) pos(i int) position {
+	ln := l.current()
+	if i < len(ln.pos) {
+		return ln.pos[i]
 	}
-	l.Emit(CHARSYMBOL)
-	return true
+	// The text may be empty, if it was only escaped newlines
+	p := ln.end
+	p.col += i - len(ln.pos)
+	return p
 }
 
-func grabCharList(l *lexer.L) (ok bool) {
-	for grabChar(l) {
-		ok = true
-		if l.Next() == ';' {
-			l.Emit(';')
-		} else {
-			l.Rewind()
-			return true
-		}
+// fail records an error at p, unless one has already been recorded
+func (l *Lexer) fail(p position, format string, args ...interface{}) {
+	if l.err == nil {
+		l.err = &ParseError{Line: p.line, Column: p.col, Msg: fmt.Sprintf(format, args...)}
 	}
-	return ok
 }
-func grabCharPair(l *lexer.L) (ok bool) {
-	if l.Next() != '(' {
-		l.Rewind()
-		return false
+
+// Error is called by the parser on a syntax error
+func (l *Lexer) Error(msg string) {
+	l.fail(l.last, "%s", msg)
+}
+
+// peek returns the rune at the current offset, and its size
+func (l *Lexer) peek() (rune, int) {
+	return utf8.DecodeRuneInString(l.current().text[l.col:])
+}
+
+func (l *Lexer) rest() string {
+	return l.current().text[l.col:]
+}
+
+func (l *Lexer) skipBlanks() {
+	for l.col < len(l.current().text) {
+		r, size := l.peek()
+		if r != ' ' && r != '\t' && r != '\r' {
+			return
+		}
+		l.col += size
 	}
-	l.Emit('(')
-	if !grabChar(l) {
-		return false
+}
+
+// Lex returns the next token for the parser
+func (l *Lexer) Lex(lval *yySymType) int {
+	if l.err != nil {
+		return LEX_ERROR
 	}
-	if l.Next() != (',') {
-		l.Rewind()
-		return false
+	if l.line >= len(l.lines) {
+		return 0
 	}
-	l.Emit(',')
-	if !grabChar(l) {
-		return false
+	l.skipBlanks()
+	if l.col >= len(l.current().text) {
+		l.last = l.pos(l.col)
+		lval.pos = l.last
+		l.endLine()
+		return EOL
 	}
-	if l.Next() != (')') {
-		l.Rewind()
-		return false
+
+	l.last = l.pos(l.col)
+	*lval = yySymType{pos: l.last}
+	tok := l.token(lval)
+	l.begun = true
+	if l.err != nil {
+		return LEX_ERROR
 	}
-	l.Emit(')')
-	return true
+	return tok
+}
+
+// endLine moves on to the next line, leaving any state the last one set
+func (l *Lexer) endLine() {
+	if l.ending {
+		l.category = 0
+		l.keywords = nil
+		l.ending = false
+	}
+	l.naming = false
+	l.line++
+	l.col = 0
+	l.begun = false
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-'
 }
 
-func grabCharPairList(l *lexer.L) (ok bool) {
-	for grabCharPair(l) {
-		ok = true
-		if l.Next() == ';' {
-			l.Emit(';')
-		} else {
-			l.Rewind()
-			return true
+func (l *Lexer) word() string {
+	start := l.col
+	for l.col < len(l.current().text) {
+		r, size := l.peek()
+		if !isWordRune(r) {
+			break
 		}
+		l.col += size
 	}
-	return ok
+	return l.current().text[start:l.col]
+}
+
+func (l *Lexer) token(lval *yySymType) int {
+	r, size := l.peek()
+	if !l.begun && (unicode.IsLetter(r) || r == '_') {
+		return l.keyword(l.word(), lval)
+	}
+	if !l.begun && l.category == 0 {
+		l.fail(l.last, "expected a category, got %q", r)
+		return LEX_ERROR
+	}
+
+	switch {
+	case r == '"':
+		l.col += size
+		lval.syms = l.string()
+		return STRING
+	case r == '<':
+		lval.sym = l.symbolName()
+		return CHARSYMBOL
+	case r == l.escape:
+		lval.sym = symbol{text: l.escaped(), pos: l.last}
+		return CHAR
+	case strings.HasPrefix(l.rest(), "..."):
+		l.col += 3
+		return ELLIPSIS
+	case r == ';' || r == ',' || r == '(' || r == ')':
+		l.col += size
+		return int(r)
+	case unicode.IsDigit(r) || (r == '-' && len(l.rest()) > 1 && unicode.IsDigit(rune(l.rest()[1]))):
+		return l.number(lval)
+	case unicode.IsLetter(r) || r == '_':
+		return l.operand(l.word(), lval)
+	}
+	l.col += size
+	lval.sym = symbol{text: string(r), pos: l.last}
+	return CHAR
 }
 
-func grabIntList(l *lexer.L) (ok bool) {
-	for grabInt(l) {
-		ok = true
-		if l.Next() == ';' {
-			l.Emit(';')
-		} else {
-			l.Rewind()
-			return true
+// keyword identifies the word at the start of a line
+func (l *Lexer) keyword(word string, lval *yySymType) int {
+	lval.val = word
+	if l.category == 0 {
+		tok, ok := categoryMap[word]
+		if !ok {
+			l.fail(l.last, "unknown category '%s'", word)
+			return LEX_ERROR
 		}
+		l.category = tok
+		l.categoryName = word
+		l.keywords = keywordMaps[tok]
+		lval.tok = tok
+		return tok
 	}
-	return ok
-}
-func grabInt(l *lexer.L) bool {
-	l.Take("-1234567890")
-	n := l.Current()
-	if n == "" {
-		return false
-	}
-	if n == "-" || (len(n) > 2 && strings.ContainsRune(n[1:], '-')) {
-		l.Error(fmt.Sprintf("expected integer, got %s", n))
-	}
-	l.Emit(NUMBER)
-	return true
-}
-
-// Handle escaped characters in strings differently
-func grabEscapeInString(l *lexer.L) (ok bool) {
-	r := l.Next()
-	switch r {
-	case ',', ';', '<', '>', escapeChar:
-		l.Emit(CHAR)
-	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-		// octal - slurp up two more characters
-		l.Next()
-		l.Next()
-		l.Emit(OCTAL_CHAR)
-	case 'd':
-		// decimal - slurp up 2 or 3 characters
-		l.Next()
-		l.Next()
-		if !strings.ContainsRune(digits, l.Next()) {
-			l.Rewind()
-		}
-		l.Emit(DECIMAL_CHAR)
-
-	case 'x':
-		// hex - slurp up 2 characters
-		l.Next()
-		l.Next()
-		l.Emit(HEX_CHAR)
-	default:
-		l.Error(fmt.Sprintf("%q not valid after escape char %q", r, escapeChar))
-		return false
-	}
-	return true
-}
-
-func grabString(l *lexer.L) (ok bool) {
-	escapeChar := '\\' // TODO
-	if l.Peek() != '"' {
-		return false
-	}
-	l.Next()
-	if l.Peek() == '"' {
-		l.Next()
-		l.Emit(TWO_DOUBLE_QUOTE_STR)
-		return true
-	}
-	l.Emit('"')
-	escapeNext := false
-	for {
-		r := l.Next()
-		// TODO: Escape character handling is likely broken at the moment.
-		if escapeNext {
-			l.Rewind()
-			grabEscapeInString(l)
-			escapeNext = false
-			continue
-		}
-		if r == '"' {
-			l.Emit('"')
-			return true
-		}
-		if r == '\n' {
-			l.Error(fmt.Sprintf("Aww jeez. Got a newline before an end quote"))
-			return false
-		}
-		if r == escapeChar {
-			escapeNext = true
-		} else {
-			l.Emit(CHAR)
+
+	switch word {
+	case "END":
+		l.ending = true
+		return END_STR
+	case "copy":
+		return COPY_STR
+	}
+	if tok, ok := l.keywords[word]; ok {
+		switch tok {
+		case CHARCLASS_STR:
+			l.naming = true
+		case ORDER_START_STR:
+			l.ordering = true
+		case ORDER_END_STR:
+			l.ordering = false
 		}
+		lval.tok = tok
+		return tok
 	}
+	if l.category == LC_CTYPE_STR && l.classes[word] {
+		lval.tok = CHARCLASS
+		return CHARCLASS
+	}
+	if l.ordering && utf8.RuneCountInString(word) == 1 {
+		lval.sym = symbol{text: word, pos: l.last}
+		return CHAR
+	}
+	l.fail(l.last, "unexpected keyword '%s'", word)
+	return LEX_ERROR
 }
-func grabStringList(l *lexer.L) (ok bool) {
-	for grabString(l) {
-		ok = true
-		if l.Next() == ';' {
-			l.Emit(';')
-		} else {
-			l.Rewind()
-			return true
+
+// operand identifies a word after the start of a line
+func (l *Lexer) operand(word string, lval *yySymType) int {
+	lval.val = word
+	if l.ending {
+		tok, ok := categoryMap[word]
+		if !ok || tok != l.category {
+			l.fail(l.last, "expected END %s, got END %s", l.categoryName, word)
+			return LEX_ERROR
 		}
+		return tok
+	}
+	if l.naming {
+		l.classes[word] = true
+		return WORD
+	}
+	if tok, ok := operandMap[word]; ok {
+		return tok
 	}
-	return ok
+	if utf8.RuneCountInString(word) == 1 {
+		lval.sym = symbol{text: word, pos: l.last}
+		return CHAR
+	}
+	return WORD
 }
-func grabMsg(l *lexer.L) (ok bool) {
-	if l.Next() != '"' {
-		l.Rewind()
-		return false
+
+func (l *Lexer) number(lval *yySymType) int {
+	start := l.col
+	l.col++ // The sign or first digit
+	for l.col < len(l.current().text) && unicode.IsDigit(rune(l.current().text[l.col])) {
+		l.col++
 	}
-	l.Emit('"')
-	for l.Next() != '"' {
+	n, err := strconv.Atoi(l.current().text[start:l.col])
+	if err != nil {
+		l.fail(l.last, "invalid number: %s", err)
 	}
-	l.Rewind()
-	l.Emit(EXTENDED_REG_EXP)
-	l.Next()
-	l.Emit('"')
-	return true
+	lval.num = n
+	return NUMBER
 }
 
-func sCollateCategory(l *lexer.L) lexer.StateFunc {
-	grabWhitespace(l)
-	l.Take(upperChars + lowerChars + "_")
-	word := l.Current()
-	switch word {
-	case "END":
-		l.Emit(END_STR)
-		must(grabWhitespace, l)
-		l.Take(upperChars)
-		if l.Current() != "LC_COLLATE" {
-			l.Error(fmt.Sprintf("wrong category end: expected %s, got %s", "LC_COLLATE", l.Current()))
+// symbolName reads a <name>, in which the escape character may quote a '>'
+func (l *Lexer) symbolName() symbol {
+	sym := symbol{pos: l.pos(l.col)}
+	l.col++
+	var name strings.Builder
+	for l.col < len(l.current().text) {
+		r, size := l.peek()
+		l.col += size
+		switch r {
+		case '>':
+			sym.name = name.String()
+			return sym
+		case l.escape:
+			if l.col < len(l.current().text) {
+				r, size = l.peek()
+				l.col += size
+			}
 		}
-		l.Emit(LC_COLLATE_STR)
-		grabNewline(l)
-		return sCategory
-	case "order_start":
-		l.Emit(ORDER_START_STR)
-		return sCollateOrderStart
-	case "collating-element":
-		l.Emit(COLLATING_ELEMENT_STR)
-		must(grabWhitespace, l)
-		must(grabChar, l) // TODO: Is this right?
-		must(grabWhitespace, l)
-		l.Take("from")
-		if l.Current() != "from" {
-			l.Error("Expected 'from' after 'collating-element'")
+		name.WriteRune(r)
+	}
+	l.fail(sym.pos, "unterminated character name")
+	return sym
+}
+
+// escaped reads an escape sequence. Consecutive escapes giving byte values
+// are combined into one character, as a multibyte character is written as
+// the sequence of its bytes.
+func (l *Lexer) escaped() string {
+	var bytes []byte
+	for l.col < len(l.current().text) {
+		text := l.rest()
+		r, size := l.peek()
+		if r != l.escape {
+			break
 		}
-		l.Emit(FROM_STR)
-		must(grabWhitespace, l)
-		must(grabString, l)
-		grabWhitespace(l)
-		must(grabNewline, l)
-	case "collating-symbol":
-		l.Emit(COLLATING_SYMBOL_STR)
-		must(grabWhitespace, l)
-		must(grabString, l)
-		grabWhitespace(l)
-		must(grabNewline, l)
-	}
-
-	return sCollateCategory
-}
-
-func sCollateOrderStart(l *lexer.L) lexer.StateFunc {
-	grabWhitespace(l)
-	l.Take(lowerChars)
-	switch l.Current() {
-	case "":
-		must(grabNewline, l)
-		return sCollateOrder
-	case "forward":
-		l.Emit(FORWARD_STR)
-	case "backward":
-		l.Emit(BACKWARD_STR)
-	case "position":
-		l.Emit(POSITION_STR)
-	}
-	return sCollateOrderStart
-}
-
-func sCollateOrder(l *lexer.L) lexer.StateFunc {
-	if !grabChar(l) {
-		l.Take(lowerChars)
-		if l.Current() != "order_end" {
-			l.Error("what's going on here lol")
+		if len(text) == size {
+			l.fail(l.pos(l.col), "escape character at end of line")
+			l.col += size
+			break
 		}
-		l.Emit(ORDER_END_STR)
-		must(grabNewline, l)
-		return sCollateCategory
-	}
-	must(grabNewline, l)
-	return sCollateOrder
-}
-
-func getsGenericCategory(category string, tok lexer.TokenType, defs map[string]decode) lexer.StateFunc {
-
-	var f lexer.StateFunc
-	f = func(l *lexer.L) lexer.StateFunc {
-		grabWhitespace(l)
-		l.Take(upperChars + lowerChars + "_")
-		word := l.Current()
-		if word == "END" { // End the category
-			l.Emit(END_STR)
-			must(grabWhitespace, l)
-			l.Take(upperChars)
-			if l.Current() != category {
-				l.Error("expected: END " + category + ", but got: END " + l.Current())
+		b, n, ok := escapeValue(text[size:])
+		if !ok {
+			if len(bytes) > 0 {
+				break
 			}
-			l.Emit(tok)
-			grabWhitespace(l)
-			must(grabNewline, l)
-			return sCategory
+			// An escaped character stands for itself
+			l.col += size
+			r, size = l.peek()
+			l.col += size
+			return string(r)
 		}
-
-		// Handle basic keyword & option pair
-		if typ, ok := defs[word]; ok {
-			l.Emit(typ.typ)
-			must(grabWhitespace, l)
-			must(typ.grab, l)
-			must(grabNewline, l)
-		} else {
-			l.Error(fmt.Sprintf("unexpected keyword '%s'", word))
+		if n < 0 {
+			l.fail(l.pos(l.col), "invalid escape sequence")
+			n = 1
 		}
-		return f
+		bytes = append(bytes, b)
+		l.col += size + n
 	}
-	return f
+	return string(bytes)
 }
 
-func checkSpecialWord(line, word string, dest *rune) bool {
-	wordLen := len(word)
-	if len(line) >= wordLen && line[:wordLen] == word {
-		words := strings.Fields(line)
-		if len(words) == 2 {
-			*dest, _ = utf8.DecodeRuneInString(words[1])
-			return true
-		}
-	}
-	return false
+// escapeValue decodes the byte value following an escape character: d and
+// up to three decimal digits, x and up to two hex digits, or up to three
+// octal digits. It returns the bytes of text used, or -1 if they're invalid.
+func escapeValue(text string) (value byte, n int, ok bool) {
+	base, max, start := 8, 3, 0
+	switch {
+	case text[0] == 'd':
+		base, start = 10, 1
+	case text[0] == 'x':
+		base, max, start = 16, 2, 1
+	case text[0] < '0' || text[0] > '7':
+		return 0, 0, false
+	}
+	end := start
+	for end < len(text) && end-start < max && isDigitIn(text[end], base) {
+		end++
+	}
+	v, err := strconv.ParseUint(text[start:end], base, 8)
+	if err != nil {
+		return 0, -1, true
+	}
+	return byte(v), end, true
 }
 
-// Returns a string without comments
-func removeComments(input string) string {
-	var out strings.Builder
-	lines := strings.Split(input, "\n")
-	commentChar := '#'
-	escapeChar = '\\' // FIXME: Uses global escapeChar
-	// TODO: Resolve comment / escape-joined lines interaction
-	for i := 0; i < len(lines); i++ {
-		l := lines[i]
+func isDigitIn(c byte, base int) bool {
+	switch base {
+	case 16:
+		return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+	case 10:
+		return c >= '0' && c <= '9'
+	}
+	return c >= '0' && c <= '7'
+}
 
-		if c, _ := utf8.DecodeRuneInString(l); c == commentChar {
-			continue
+// string reads the rest of a quoted string, returning its characters
+func (l *Lexer) string() []symbol {
+	start := l.last
+	syms := []symbol{}
+	for l.col < len(l.current().text) {
+		r, size := l.peek()
+		switch r {
+		case '"':
+			l.col += size
+			return syms
+		case '<':
+			syms = append(syms, l.symbolName())
+		case l.escape:
+			p := l.pos(l.col)
+			syms = append(syms, symbol{text: l.escaped(), pos: p})
+		default:
+			syms = append(syms, symbol{text: string(r), pos: l.pos(l.col)})
+			l.col += size
 		}
-		// Check for escape/comment_char redefinition
-		checkSpecialWord(l, "comment_char", &commentChar)
-		if !checkSpecialWord(l, "escape_char", &escapeChar) {
-
-			// Join newlines if they end with escape character
-			for len(l) > 0 {
-				lastRune, size := utf8.DecodeLastRuneInString(l)
-				if lastRune == utf8.RuneError {
-					panic(fmt.Errorf("couldn't read last rune of '%s'", l))
-				}
-				if lastRune != escapeChar {
-					break
-				}
-				i++
-				l = l[:len(l)-size] + strings.TrimSpace(lines[i])
+	}
+	l.fail(start, "newline in string")
+	return syms
+}
+
+// split divides the input into logical lines, dropping blank lines and
+// comments. It also handles the escape_char and comment_char keywords, which
+// may only appear before the first category.
+func (l *Lexer) split(input string) {
+	var pending *logicalLine // A line ending in the escape character
+	var pendingEnd position
+	header := true
+	for n, text := range strings.Split(input, "\n") {
+		text = strings.TrimSuffix(text, "\r")
+		trimmed := strings.TrimLeft(text, " \t")
+		if pending == nil {
+			if first, _ := utf8.DecodeRuneInString(trimmed); trimmed == "" || first == l.comment {
+				continue
 			}
-			_, err := out.WriteString(l)
-			if err != nil {
-				panic(err)
+			if header {
+				if fields := strings.Fields(trimmed); len(fields) == 2 && utf8.RuneCountInString(fields[1]) == 1 {
+					r, _ := utf8.DecodeRuneInString(fields[1])
+					switch fields[0] {
+					case "escape_char":
+						l.escape = r
+						continue
+					case "comment_char":
+						l.comment = r
+						continue
+					}
+				}
+				header = false
 			}
-			_, err = out.WriteRune('\n')
-			if err != nil {
-				panic(err)
+			pending = &logicalLine{}
+		}
+
+		// A trailing escape character joins this line to the next, unless
+		// it is itself escaped
+		joined := false
+		var escapes int
+		for s := text; strings.HasSuffix(s, string(l.escape)); s = s[:len(s)-utf8.RuneLen(l.escape)] {
+			escapes++
+		}
+		if escapes%2 == 1 {
+			text = text[:len(text)-utf8.RuneLen(l.escape)]
+			joined = true
+			pendingEnd = position{n + 1, utf8.RuneCountInString(text) + 1}
+		}
+
+		// Columns count characters, so every byte of one shares a position
+		col := 1
+		for _, r := range text {
+			for i := 0; i < utf8.RuneLen(r); i++ {
+				pending.pos = append(pending.pos, position{n + 1, col})
 			}
+			col++
+		}
+		pending.text += text
+		pending.end = position{n + 1, col}
+
+		if !joined {
+			l.lines = append(l.lines, *pending)
+			pending = nil
 		}
 	}
-	return out.String()
+	if pending != nil {
+		l.fail(pendingEnd, "escape character at end of file")
+	}
 }
 
-// NewLexer creates a new lexer, ready to read from input
+// NewLexer creates a new lexer, ready to read from input. Symbolic names are
+// resolved with the portable character set, and <Uxxxx> names as UTF-8.
 func NewLexer(input string) *Lexer {
-	lite := removeComments(input)
-	l := Lexer{
-		*lexer.New(lite, sInit),
-		Def{},
-	}
-	l.Start()
-	return &l
+	return newLexer(input, defaultCharmap)
+}
+
+func newLexer(input string, charmap *Charmap) *Lexer {
+	l := &Lexer{
+		escape:  '\\',
+		comment: '#',
+		classes: make(map[string]bool),
+		defined: make(map[int]bool),
+		charmap: charmap,
+	}
+	l.def.Monetary = EmptyMonetary()
+	l.def.Ctype.codeset = charmap.CodeSetName
+	l.def.Ctype.mbCurMax = charmap.MbCurMax
+	l.split(input)
+	return l
 }
@@ -0,0 +1,17 @@
+package locale
+
+// Messages holds the patterns used to recognise answers to yes/no questions
+type Messages struct {
+	yesexpr string // An extended regular expression matching an affirmative answer
+	noexpr  string // An extended regular expression matching a negative answer
+}
+
+// YesExpr returns the extended regular expression for an affirmative answer
+func (m Messages) YesExpr() string {
+	return m.yesexpr
+}
+
+// NoExpr returns the extended regular expression for a negative answer
+func (m Messages) NoExpr() string {
+	return m.noexpr
+}
@@ -1,8 +1,73 @@
 package locale
 
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
 // Numeric holds the information necessary to format a numeric thing
 type Numeric struct {
 	decimalPoint string
 	thousandsSep string
-	grouping     int
+	grouping     []int // Digits in each group, from the right; nil for no grouping
+}
+
+// FormatInt writes i with the locale's thousands separator between groups of
+// digits
+func (n Numeric) FormatInt(i int64) string {
+	s := strconv.FormatInt(i, 10)
+	if i < 0 {
+		return "-" + group(s[1:], n.thousandsSep, n.grouping)
+	}
+	return group(s, n.thousandsSep, n.grouping)
+}
+
+// FormatFloat writes f as strconv.FormatFloat does, then puts in the locale's
+// decimal point and groups the digits before it. Infinities and NaN are left
+// alone.
+func (n Numeric) FormatFloat(f float64, fmt byte, prec int) string {
+	s := strconv.FormatFloat(f, fmt, prec, 64)
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return s
+	}
+	sign := ""
+	if s[0] == '-' {
+		sign, s = "-", s[1:]
+	}
+	end := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+	if end < 0 {
+		end = len(s)
+	}
+	intPart, rest := s[:end], s[end:]
+	if strings.HasPrefix(rest, ".") {
+		rest = orDefault(n.decimalPoint, ".") + rest[1:]
+	}
+	return sign + group(intPart, n.thousandsSep, n.grouping) + rest
+}
+
+// group puts sep between groups of digits. The sizes of the groups are given
+// from the right, and the last one is repeated; a size that isn't positive,
+// such as the -1 of localedef, ends the grouping.
+func group(digits, sep string, grouping []int) string {
+	if sep == "" || len(grouping) == 0 {
+		return digits
+	}
+	var groups []string
+	end, size := len(digits), 0
+	for i := 0; ; i++ {
+		if i < len(grouping) {
+			size = grouping[i]
+		}
+		if size <= 0 || size >= end {
+			break
+		}
+		groups = append(groups, digits[end-size:end])
+		end -= size
+	}
+	out := digits[:end]
+	for i := len(groups) - 1; i >= 0; i-- {
+		out += sep + groups[i]
+	}
+	return out
 }
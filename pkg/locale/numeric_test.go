@@ -0,0 +1,54 @@
+package locale
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFormatNumber(t *testing.T) {
+	german := Numeric{decimalPoint: ",", thousandsSep: ".", grouping: []int{3}}
+	indian := Numeric{decimalPoint: ".", thousandsSep: ",", grouping: []int{3, 2}}
+	once := Numeric{decimalPoint: ".", thousandsSep: ",", grouping: []int{3, -1}}
+	posix := POSIX().Numeric
+
+	ints := []struct {
+		n    Numeric
+		i    int64
+		want string
+	}{
+		{german, 1234567, "1.234.567"},
+		{german, -1234, "-1.234"},
+		{german, 123, "123"},
+		{german, -100, "-100"},
+		{indian, 1234567890, "1,23,45,67,890"},
+		{once, 1234567, "1234,567"},
+		{posix, 1234567, "1234567"},
+	}
+	for _, test := range ints {
+		if got := test.n.FormatInt(test.i); got != test.want {
+			t.Errorf("%d formatted as %q, expected %q", test.i, got, test.want)
+		}
+	}
+
+	floats := []struct {
+		n    Numeric
+		f    float64
+		fmt  byte
+		prec int
+		want string
+	}{
+		{german, 1234567.891, 'f', 2, "1.234.567,89"},
+		{german, -0.5, 'f', 1, "-0,5"},
+		{german, 1234567.891, 'e', 3, "1,235e+06"},
+		{german, 1234567, 'g', -1, "1,234567e+06"},
+		{german, 12345, 'f', 0, "12.345"},
+		{indian, 123456.25, 'f', -1, "1,23,456.25"},
+		{posix, 1234.5, 'f', 2, "1234.50"},
+		{german, math.Inf(1), 'f', 2, "+Inf"},
+	}
+	for _, test := range floats {
+		if got := test.n.FormatFloat(test.f, test.fmt, test.prec); got != test.want {
+			t.Errorf("%v formatted as %q, expected %q", test.f, got, test.want)
+		}
+	}
+}
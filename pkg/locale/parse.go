@@ -0,0 +1,456 @@
+package locale
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+func init() {
+	yyErrorVerbose = true
+}
+
+// ParseError describes a problem in a locale definition, and where it is
+type ParseError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// ParseDef reads a locale definition in the format accepted by localedef.
+// Characters are resolved with the portable character set, and names of the
+// form <Uxxxx> are encoded as UTF-8.
+func ParseDef(r io.Reader) (Def, error) {
+	return ParseDefCharmap(r, defaultCharmap)
+}
+
+// ParseDefCharmap reads a locale definition, resolving characters with a
+// charmap
+func ParseDefCharmap(r io.Reader, charmap *Charmap) (Def, error) {
+	input, err := ioutil.ReadAll(r)
+	if err != nil {
+		return Def{}, err
+	}
+	l := newLexer(string(input), charmap)
+	if yyParse(l) != 0 || l.err != nil {
+		return Def{}, l.err
+	}
+	return l.def, nil
+}
+
+// resolve returns the character a symbol stands for
+func (l *Lexer) resolve(sym symbol) (Character, bool) {
+	if sym.name == "" {
+		return l.charmap.decode(sym.text), true
+	}
+	ch, ok := l.charmap.Lookup(sym.name)
+	if !ok {
+		l.fail(sym.pos, "undefined character <%s>", sym.name)
+	}
+	return ch, ok
+}
+
+// text returns the encoded characters of a string
+func (l *Lexer) text(syms []symbol) string {
+	var out strings.Builder
+	for _, sym := range syms {
+		c, _ := l.resolve(sym)
+		out.WriteString(c.Bytes)
+	}
+	return out.String()
+}
+
+// begin starts a category, which may only be defined once
+func (l *Lexer) begin(hdr yySymType) {
+	if l.defined[hdr.tok] {
+		l.fail(hdr.pos, "%s is defined more than once", hdr.val)
+	}
+	l.defined[hdr.tok] = true
+}
+
+// copy records that a category is to be copied from another locale
+func (l *Lexer) copy(hdr yySymType, name yySymType) {
+	if l.def.Copies == nil {
+		l.def.Copies = make(map[string]string)
+	}
+	l.def.Copies[hdr.val] = l.text(name.syms)
+}
+
+// grouping converts a grouping list, in which -1 means no further grouping
+func grouping(list []int) []int {
+	if len(list) == 1 && list[0] == -1 {
+		return nil
+	}
+	return list
+}
+
+// chars resolves a list of characters, expanding ellipses into the
+// characters whose encodings lie between their neighbours
+func (l *Lexer) chars(syms []symbol) []Character {
+	var out []Character
+	for i := 0; i < len(syms); i++ {
+		if syms[i].word != "..." {
+			if c, ok := l.resolve(syms[i]); ok {
+				out = append(out, c)
+			}
+			continue
+		}
+		if len(out) == 0 || i+1 == len(syms) {
+			l.fail(syms[i].pos, "ellipsis must be between two characters")
+			return out
+		}
+		first := out[len(out)-1]
+		last, ok := l.resolve(syms[i+1])
+		if !ok {
+			return out
+		}
+		if encodedLess(last.Bytes, first.Bytes) {
+			l.fail(syms[i].pos, "range %q...%q is backwards", first.Bytes, last.Bytes)
+			return out
+		}
+		out = append(out, l.charmap.between(first, last)...)
+	}
+	return out
+}
+
+func (l *Lexer) addClass(kw yySymType, syms []symbol) {
+	c := &l.def.Ctype
+	chars := l.chars(syms)
+	switch kw.tok {
+	case UPPER_STR:
+		c.upper = append(c.upper, chars...)
+	case LOWER_STR:
+		c.lower = append(c.lower, chars...)
+	case ALPHA_STR:
+		c.alpha = append(c.alpha, chars...)
+	case DIGIT_STR:
+		c.digit = append(c.digit, chars...)
+	case ALNUM_STR:
+		c.alnum = append(c.alnum, chars...)
+	case SPACE_STR:
+		c.space = append(c.space, chars...)
+	case CNTRL_STR:
+		c.cntrl = append(c.cntrl, chars...)
+	case PUNCT_STR:
+		c.punct = append(c.punct, chars...)
+	case GRAPH_STR:
+		c.graph = append(c.graph, chars...)
+	case PRINT_STR:
+		c.print = append(c.print, chars...)
+	case XDIGIT_STR:
+		c.xdigit = append(c.xdigit, chars...)
+	case BLANK_STR:
+		c.blank = append(c.blank, chars...)
+	case CHARCLASS:
+		c.other[kw.val] = append(c.other[kw.val], chars...)
+	}
+}
+
+// addConversion adds (from, to) pairs to toupper or tolower
+func (l *Lexer) addConversion(kw yySymType, pairs []symbol) {
+	c := &l.def.Ctype
+	m := &c.toupper
+	if kw.tok == TOLOWER_STR {
+		m = &c.tolower
+	}
+	if *m == nil {
+		*m = make(map[Character]Character)
+	}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		from, ok1 := l.resolve(pairs[i])
+		to, ok2 := l.resolve(pairs[i+1])
+		if ok1 && ok2 {
+			(*m)[from] = to
+		}
+	}
+}
+
+func (l *Lexer) declareClasses(names []string) {
+	c := &l.def.Ctype
+	if c.other == nil {
+		c.other = make(map[string][]Character)
+	}
+	for _, name := range names {
+		if _, ok := ctypeMap[name]; ok {
+			l.fail(l.last, "charclass %s is already defined", name)
+			continue
+		}
+		if _, ok := c.other[name]; !ok {
+			c.classes = append(c.classes, name)
+			c.other[name] = nil
+		}
+	}
+}
+
+// collateID names a symbol in the collation order: collating elements and
+// symbols keep their names, and characters are known by their encoding. It
+// also returns the characters the symbol matches.
+func (l *Lexer) collateID(sym symbol) (string, []Character, bool) {
+	if sym.word != "" {
+		return sym.word, nil, true
+	}
+	if sym.name != "" {
+		if chars, ok := l.def.Collate.elements[sym.name]; ok {
+			return "<" + sym.name + ">", chars, true
+		}
+		for _, s := range l.def.Collate.symbols {
+			if s == sym.name {
+				return "<" + sym.name + ">", nil, true
+			}
+		}
+	}
+	ch, ok := l.resolve(sym)
+	return ch.Bytes, []Character{ch}, ok
+}
+
+// declared reports whether name is already a collating element or symbol
+func (l *Lexer) declared(name string) bool {
+	_, ok := l.def.Collate.elements[name]
+	for _, s := range l.def.Collate.symbols {
+		ok = ok || s == name
+	}
+	return ok
+}
+
+func (l *Lexer) collatingSymbol(sym symbol) {
+	if l.declared(sym.name) {
+		l.fail(sym.pos, "<%s> is already defined", sym.name)
+		return
+	}
+	l.def.Collate.symbols = append(l.def.Collate.symbols, sym.name)
+}
+
+func (l *Lexer) collatingElement(sym symbol, from []symbol) {
+	if l.declared(sym.name) {
+		l.fail(sym.pos, "<%s> is already defined", sym.name)
+		return
+	}
+	c := &l.def.Collate
+	if c.elements == nil {
+		c.elements = make(map[string][]Character)
+	}
+	var chars []Character
+	for _, f := range from {
+		if ch, ok := l.resolve(f); ok {
+			chars = append(chars, ch)
+		}
+	}
+	c.elements[sym.name] = chars
+}
+
+// orderStart begins the collation sequence. With no directions, there is a
+// single forward level.
+func (l *Lexer) orderStart(dirs []direction) {
+	if len(dirs) == 0 {
+		dirs = []direction{{}}
+	}
+	l.def.Collate.levels = dirs
+}
+
+func (l *Lexer) orderEntry(sym symbol, weights [][]symbol) {
+	c := &l.def.Collate
+	id, chars, ok := l.collateID(sym)
+	if !ok {
+		return
+	}
+	if len(weights) > len(c.levels) {
+		l.fail(sym.pos, "%d weights given, but order_start has %d levels", len(weights), len(c.levels))
+		return
+	}
+	entry := ordering{id: id, chars: chars, weights: make([][]string, len(c.levels))}
+	for i, weight := range weights {
+		for _, w := range weight {
+			wid, _, ok := l.collateID(w)
+			if !ok {
+				return
+			}
+			entry.weights[i] = append(entry.weights[i], wid)
+		}
+	}
+	l.expandEllipsis(entry)
+	c.order = append(c.order, entry)
+}
+
+// expandEllipsis replaces an ellipsis just before the next entry with the
+// characters whose encodings come between those of the entries either side
+// of it. Each gets the ellipsis's weights, with "..." standing for itself.
+// Characters that already have an entry are left where they are.
+func (l *Lexer) expandEllipsis(next ordering) {
+	c := &l.def.Collate
+	n := len(c.order)
+	if n < 2 || c.order[n-1].id != "..." || len(c.order[n-2].chars) != 1 || len(next.chars) != 1 {
+		return
+	}
+	ellipsis := c.order[n-1]
+	c.order = c.order[:n-1]
+	ordered := make(map[string]bool, len(c.order))
+	for _, o := range c.order {
+		ordered[o.id] = true
+	}
+	for _, ch := range l.charmap.between(c.order[n-2].chars[0], next.chars[0]) {
+		if ordered[ch.Bytes] {
+			continue
+		}
+		entry := ordering{id: ch.Bytes, chars: []Character{ch}, weights: make([][]string, len(ellipsis.weights))}
+		for i, weight := range ellipsis.weights {
+			for _, id := range weight {
+				if id == "..." {
+					id = ch.Bytes
+				}
+				entry.weights[i] = append(entry.weights[i], id)
+			}
+		}
+		c.order = append(c.order, entry)
+	}
+}
+
+func (l *Lexer) setMessages(kw yySymType, value []symbol) {
+	switch kw.tok {
+	case YESEXPR_STR:
+		l.def.Messages.yesexpr = l.text(value)
+	case NOEXPR_STR:
+		l.def.Messages.noexpr = l.text(value)
+	}
+}
+
+func (l *Lexer) setMonetaryString(kw yySymType, value []symbol) {
+	m := &l.def.Monetary
+	s := l.text(value)
+	switch kw.tok {
+	case INT_CURR_SYMBOL_STR:
+		m.intCurrSymbol = s
+	case CURRENCY_SYMBOL_STR:
+		m.currencySymbol = s
+	case MON_DECIMAL_POINT_STR:
+		m.monDecimalPoint = s
+	case MON_THOUSANDS_SEP_STR:
+		m.monThousandsSep = s
+	case POSITIVE_SIGN_STR:
+		m.positiveSign = s
+	case NEGATIVE_SIGN_STR:
+		m.negativeSign = s
+	}
+}
+
+func (l *Lexer) setMonetaryNumber(kw yySymType, n int) {
+	m := &l.def.Monetary
+	fields := map[int]*int{
+		INT_FRAC_DIGITS_STR:    &m.intFracDigits,
+		FRAC_DIGITS_STR:        &m.fracDigits,
+		P_CS_PRECEDES_STR:      &m.pCsPrecedes,
+		P_SEP_BY_SPACE_STR:     &m.pSepBySpace,
+		N_CS_PRECEDES_STR:      &m.nCsPrecedes,
+		N_SEP_BY_SPACE_STR:     &m.nSepBySpace,
+		P_SIGN_POSN_STR:        &m.pSignPosn,
+		N_SIGN_POSN_STR:        &m.nSignPosn,
+		INT_P_CS_PRECEDES_STR:  &m.intPcsPrecedes,
+		INT_P_SEP_BY_SPACE_STR: &m.intPsepBySpace,
+		INT_N_CS_PRECEDES_STR:  &m.intNcsPrecedes,
+		INT_N_SEP_BY_SPACE_STR: &m.intNsepBySpace,
+		INT_P_SIGN_POSN_STR:    &m.intPsignPosn,
+		INT_N_SIGN_POSN_STR:    &m.intNsignPosn,
+	}
+	*fields[kw.tok] = n
+}
+
+func (l *Lexer) setNumericString(kw yySymType, value []symbol) {
+	switch kw.tok {
+	case DECIMAL_POINT_STR:
+		l.def.Numeric.decimalPoint = l.text(value)
+	case THOUSANDS_SEP_STR:
+		l.def.Numeric.thousandsSep = l.text(value)
+	}
+}
+
+// setTime assigns a keyword from LC_TIME, checking it has the right number of
+// strings
+func (l *Lexer) setTime(kw yySymType, values [][]symbol) {
+	t := &l.def.Time
+	strs := make([]string, len(values))
+	for i, v := range values {
+		strs[i] = l.text(v)
+	}
+
+	count := func(n int) bool {
+		if len(strs) != n {
+			l.fail(kw.pos, "%s takes %d strings, not %d", kw.val, n, len(strs))
+			return false
+		}
+		return true
+	}
+	switch kw.tok {
+	case ABDAY_STR:
+		if count(7) {
+			t.abday = strs
+		}
+	case DAY_STR:
+		if count(7) {
+			t.day = strs
+		}
+	case ABMON_STR:
+		if count(12) {
+			t.abmon = strs
+		}
+	case MON_STR:
+		if count(12) {
+			t.mon = strs
+		}
+	case AM_PM_STR:
+		if count(2) {
+			t.am, t.pm = strs[0], strs[1]
+		}
+	case D_T_FMT_STR:
+		if count(1) {
+			t.dtFmt = strs[0]
+		}
+	case D_FMT_STR:
+		if count(1) {
+			t.dFmt = strs[0]
+		}
+	case T_FMT_STR:
+		if count(1) {
+			t.tFmt = strs[0]
+		}
+	case T_FMT_AMPM_STR:
+		if count(1) {
+			t.tFmtAmPm = strs[0]
+		}
+	case ERA_D_FMT_STR:
+		if count(1) {
+			t.eraDFmt = strs[0]
+		}
+	case ERA_T_FMT_STR:
+		if count(1) {
+			t.eraTFmt = strs[0]
+		}
+	case ERA_D_T_FMT_STR:
+		if count(1) {
+			t.eraDTFmt = strs[0]
+		}
+	case ALT_DIGITS_STR:
+		if len(strs) > 100 {
+			l.fail(kw.pos, "alt_digits takes at most 100 strings, not %d", len(strs))
+			return
+		}
+		t.altDigits = strs
+	case ERA_STR:
+		t.eras = nil
+		for i, s := range strs {
+			era, err := NewEra(s)
+			if err != nil {
+				p := kw.pos
+				if len(values[i]) > 0 {
+					p = values[i][0].pos
+				}
+				l.fail(p, "%s", err)
+				return
+			}
+			t.eras = append(t.eras, era)
+		}
+	}
+}
@@ -1,19 +1,20 @@
 package locale
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 )
 
 var parseTests = []string{
-	/*
-			``,          // Empty string
-			`# comment`, // Comment
-			`comment_char $
-		$ bakery!`, // Comment char redefinition
-	*/
+	``,          // Empty string
+	`# comment`, // Comment
+	`comment_char $
+$ bakery!`, // Comment char redefinition
 	`LC_MONETARY
 int_curr_symbol "USD "
 END LC_MONETARY`, // Basic monetary block
@@ -131,43 +132,191 @@ func TestShouldParse(t *testing.T) {
 			l := NewLexer(test)
 			parsed := yyParse(l)
 			if parsed != 0 {
-				t.Errorf("yyParse returned %d", parsed)
+				t.Errorf("yyParse returned %d: %v", parsed, l.err)
 			}
 		})
 	}
 }
 
+func TestShouldFail(t *testing.T) {
+	for i, test := range parseFailTests {
+		t.Run(fmt.Sprintf("Subtest %d", i), func(t *testing.T) {
+			if _, err := ParseDef(strings.NewReader(test)); err == nil {
+				t.Errorf("expected an error parsing %q", test)
+			}
+		})
+	}
+}
+
+func TestParseErrorPosition(t *testing.T) {
+	tests := []struct {
+		input     string
+		line, col int
+	}{
+		{"LC_NUMERIC\ndecimal_point \"<nope>\"\nEND LC_NUMERIC\n", 2, 16},
+		{"# comment\n\nLC_TIME\nfrac_digits 4\nEND LC_TIME\n", 4, 1},
+		{"LC_CTYPE\nupper <A>;\\\n  <B>;;<C>\nEND LC_CTYPE\n", 3, 7},
+		{"LC_TIME\nEND LC_NUMERIC\n", 2, 5},
+		{"LC_TIME\nEND LC_TIME\\", 2, 12},
+	}
+	for _, test := range tests {
+		_, err := ParseDef(strings.NewReader(test.input))
+		perr, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("%q: expected a ParseError, got %v", test.input, err)
+			continue
+		}
+		if perr.Line != test.line || perr.Column != test.col {
+			t.Errorf("%q: error at %d:%d, expected %d:%d (%v)", test.input, perr.Line, perr.Column, test.line, test.col, err)
+		}
+	}
+}
+
+func TestParseDef(t *testing.T) {
+	input := `comment_char %
+escape_char /
+% A comment
+LC_CTYPE
+charclass vowel;consonant
+upper <A>;.../
+  ;<F>
+vowel a;e;/x69;/157;/d117
+toupper (<a>,<A>);(b,B)
+END LC_CTYPE
+LC_COLLATE
+collating-symbol <low>
+collating-element <ch> from "<c><h>"
+order_start forward;backward,position
+<low>
+<a> <a>;<low>
+<ch> "<c><h>";IGNORE
+UNDEFINED
+order_end
+END LC_COLLATE
+LC_NUMERIC
+copy "en_US"
+END LC_NUMERIC
+LC_MESSAGES
+yesexpr "^[yY/]]"
+END LC_MESSAGES
+LC_MONETARY
+mon_grouping 3;2
+p_sign_posn 1
+currency_symbol "/xe2/x82/xac"
+END LC_MONETARY
+`
+	def, err := ParseDef(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := encoded(def.Ctype.upper); got != "ABCDEF" {
+		t.Errorf("upper is %q", got)
+	}
+	if got := encoded(def.Ctype.other["vowel"]); got != "aeiou" {
+		t.Errorf("vowel is %q", got)
+	}
+	if len(def.Ctype.classes) != 2 || def.Ctype.classes[1] != "consonant" {
+		t.Errorf("classes are %v", def.Ctype.classes)
+	}
+	if def.Ctype.toupper[Character{"a", 'a'}] != (Character{"A", 'A'}) || def.Ctype.toupper[Character{"b", 'b'}].Rune != 'B' {
+		t.Errorf("toupper is %v", def.Ctype.toupper)
+	}
+
+	c := def.Collate
+	if len(c.levels) != 2 || c.levels[0].backward || !c.levels[1].backward || !c.levels[1].position {
+		t.Errorf("levels are %+v", c.levels)
+	}
+	if encoded(c.elements["ch"]) != "ch" {
+		t.Errorf("elements are %v", c.elements)
+	}
+	want := []ordering{
+		{"<low>", nil, [][]string{nil, nil}},
+		{"a", []Character{{"a", 'a'}}, [][]string{{"a"}, {"<low>"}}},
+		{"<ch>", []Character{{"c", 'c'}, {"h", 'h'}}, [][]string{{"c", "h"}, {"IGNORE"}}},
+		{"UNDEFINED", nil, [][]string{nil, nil}},
+	}
+	if !reflect.DeepEqual(c.order, want) {
+		t.Errorf("order is %v, expected %v", c.order, want)
+	}
+
+	if def.Copies["LC_NUMERIC"] != "en_US" {
+		t.Errorf("copies are %v", def.Copies)
+	}
+	if def.Messages.yesexpr != "^[yY]]" {
+		t.Errorf("yesexpr is %q", def.Messages.yesexpr)
+	}
+	m := def.Monetary
+	if !reflect.DeepEqual(m.monGrouping, []int{3, 2}) || m.pSignPosn != 1 || m.currencySymbol != "€" || m.fracDigits != -1 {
+		t.Errorf("monetary is %+v", m)
+	}
+}
+
+// encoded joins the encodings of characters
+func encoded(chars []Character) string {
+	var out strings.Builder
+	for _, c := range chars {
+		out.WriteString(c.Bytes)
+	}
+	return out.String()
+}
+
 func TestParsePOSIX(t *testing.T) {
 	f, err := os.Open("POSIX.locale")
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
 	defer f.Close()
 
-	bytes, err := ioutil.ReadAll(f)
+	def, err := ParseDef(f)
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
+	}
+	if len(def.Ctype.punct) != 32 || def.Ctype.toupper[Character{"z", 'z'}].Bytes != "Z" {
+		t.Errorf("ctype is %+v", def.Ctype)
+	}
+	if len(def.Collate.order) != 128 || def.Collate.order[65].id != "A" {
+		t.Errorf("collation order has %d entries", len(def.Collate.order))
+	}
+	if def.Numeric.decimalPoint != "." || def.Numeric.grouping != nil {
+		t.Errorf("numeric is %+v", def.Numeric)
+	}
+	if def.Monetary.intFracDigits != -1 || def.Monetary.monGrouping != nil {
+		t.Errorf("monetary is %+v", def.Monetary)
+	}
+	tm := def.Time
+	if tm.dtFmt != "%a %b %e %H:%M:%S %Y" || tm.mon[8] != "September" || tm.pm != "PM" {
+		t.Errorf("time is %+v", tm)
+	}
+	if def.Messages.yesexpr != "^[yY]" || def.Messages.noexpr != "^[nN]" {
+		t.Errorf("messages are %+v", def.Messages)
 	}
-	yyErrorVerbose = true
-	l := NewLexer(string(bytes))
-	parsed := yyParse(l)
-	fmt.Println("parsed:", parsed)
-	fmt.Printf("def: %#v\n", def)
 }
 
 func BenchmarkParsePOSIX(b *testing.B) {
-	f, err := os.Open("POSIX.locale")
-	if err != nil {
-		b.Error(err)
-	}
-	bytes, err := ioutil.ReadAll(f)
-	f.Close()
+	input, err := ioutil.ReadFile("POSIX.locale")
 	if err != nil {
-		b.Error(err)
+		b.Fatal(err)
 	}
-	input := string(bytes)
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		yyParse(NewLexer(input))
+		if _, err := ParseDef(bytes.NewReader(input)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestLexEscapedNewlineOnly(t *testing.T) {
+	// The logical line has no text, so its position is where it ends
+	l := NewLexer("\\\n")
+	var lval yySymType
+	if tok := l.Lex(&lval); tok != EOL {
+		t.Fatalf("got token %d, expected EOL", tok)
+	}
+	if lval.pos != (position{2, 1}) {
+		t.Errorf("EOL at %d:%d, expected 2:1", lval.pos.line, lval.pos.col)
+	}
+	if tok := l.Lex(&lval); tok != 0 {
+		t.Errorf("got token %d, expected the end of input", tok)
 	}
 }
@@ -0,0 +1,125 @@
+package locale
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultPath is the directory for compiled locales when LOCPATH is not set
+const DefaultPath = "/usr/share/posix-utils/locale"
+
+// Path returns where the compiled locale called name is kept. A name
+// containing a slash is itself a pathname; any other name is kept in the
+// first directory of LOCPATH, or in DefaultPath.
+func Path(name string) string {
+	if strings.Contains(name, "/") {
+		return name
+	}
+	dir := DefaultPath
+	if locpath := os.Getenv("LOCPATH"); locpath != "" {
+		dir = filepath.SplitList(locpath)[0]
+	}
+	return filepath.Join(dir, name)
+}
+
+// DefaultCharmapPath is the directory searched last for charmaps
+const DefaultCharmapPath = "/usr/share/posix-utils/charmaps"
+
+// CharmapSearchPath returns the directories searched for charmaps: the
+// charmaps directory under each directory of I18NPATH, followed by
+// DefaultCharmapPath
+func CharmapSearchPath() []string {
+	var dirs []string
+	for _, dir := range filepath.SplitList(os.Getenv("I18NPATH")) {
+		if dir != "" {
+			dirs = append(dirs, filepath.Join(dir, "charmaps"))
+		}
+	}
+	return append(dirs, DefaultCharmapPath)
+}
+
+// FindCharmap returns the path of a charmap. A name containing a slash is a
+// pathname; others are looked for in each directory of CharmapSearchPath.
+func FindCharmap(name string) (string, error) {
+	if strings.Contains(name, "/") {
+		if _, err := os.Stat(name); err != nil {
+			return "", err
+		}
+		return name, nil
+	}
+	for _, dir := range CharmapSearchPath() {
+		path := filepath.Join(dir, name)
+		if fi, err := os.Stat(path); err == nil && fi.Mode().IsRegular() {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("charmap %s not found", name)
+}
+
+// Available returns the names of the locales that can be used, sorted: the
+// built-in C and POSIX, and every compiled locale in SearchPath
+func Available() []string {
+	names := []string{"C", "POSIX"}
+	for _, dir := range SearchPath() {
+		names = append(names, listDir(dir, isCompiled)...)
+	}
+	return sortedUnique(names)
+}
+
+// Charmaps returns the names of the charmaps that can be used, sorted. UTF-8
+// is built in; the others are those in CharmapSearchPath.
+func Charmaps() []string {
+	names := []string{defaultCharmap.CodeSetName}
+	for _, dir := range CharmapSearchPath() {
+		names = append(names, listDir(dir, nil)...)
+	}
+	return sortedUnique(names)
+}
+
+// listDir returns the names of the regular files in dir that keep passes,
+// ignoring hidden files. A directory that can't be read has no files.
+func listDir(dir string, keep func(path string) bool) []string {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, fi := range infos {
+		name := fi.Name()
+		if !fi.Mode().IsRegular() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		if keep == nil || keep(filepath.Join(dir, name)) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// isCompiled reports whether the file at path starts like a compiled locale
+func isCompiled(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	magic := make([]byte, len(binaryMagic))
+	_, err = io.ReadFull(f, magic)
+	return err == nil && string(magic) == binaryMagic
+}
+
+func sortedUnique(names []string) []string {
+	sort.Strings(names)
+	out := names[:0]
+	for i, name := range names {
+		if i == 0 || name != names[i-1] {
+			out = append(out, name)
+		}
+	}
+	return out
+}
@@ -0,0 +1,113 @@
+package locale
+
+// portable maps the symbolic names of the portable character set, from
+// chapter 6 of the Base Definitions, to the characters they stand for. Both
+// the preferred names and their alternates are included.
+var portable = withLetters(map[string]rune{
+	"NUL":                  0x00,
+	"SOH":                  0x01,
+	"STX":                  0x02,
+	"ETX":                  0x03,
+	"EOT":                  0x04,
+	"ENQ":                  0x05,
+	"ACK":                  0x06,
+	"alert":                0x07,
+	"BEL":                  0x07,
+	"backspace":            0x08,
+	"BS":                   0x08,
+	"tab":                  0x09,
+	"HT":                   0x09,
+	"newline":              0x0a,
+	"LF":                   0x0a,
+	"vertical-tab":         0x0b,
+	"VT":                   0x0b,
+	"form-feed":            0x0c,
+	"FF":                   0x0c,
+	"carriage-return":      0x0d,
+	"CR":                   0x0d,
+	"SO":                   0x0e,
+	"SI":                   0x0f,
+	"DLE":                  0x10,
+	"DC1":                  0x11,
+	"DC2":                  0x12,
+	"DC3":                  0x13,
+	"DC4":                  0x14,
+	"NAK":                  0x15,
+	"SYN":                  0x16,
+	"ETB":                  0x17,
+	"CAN":                  0x18,
+	"EM":                   0x19,
+	"SUB":                  0x1a,
+	"ESC":                  0x1b,
+	"IS4":                  0x1c,
+	"FS":                   0x1c,
+	"IS3":                  0x1d,
+	"GS":                   0x1d,
+	"IS2":                  0x1e,
+	"RS":                   0x1e,
+	"IS1":                  0x1f,
+	"US":                   0x1f,
+	"space":                ' ',
+	"exclamation-mark":     '!',
+	"quotation-mark":       '"',
+	"number-sign":          '#',
+	"dollar-sign":          '$',
+	"percent-sign":         '%',
+	"ampersand":            '&',
+	"apostrophe":           '\'',
+	"left-parenthesis":     '(',
+	"right-parenthesis":    ')',
+	"asterisk":             '*',
+	"plus-sign":            '+',
+	"comma":                ',',
+	"hyphen":               '-',
+	"hyphen-minus":         '-',
+	"period":               '.',
+	"full-stop":            '.',
+	"slash":                '/',
+	"solidus":              '/',
+	"zero":                 '0',
+	"one":                  '1',
+	"two":                  '2',
+	"three":                '3',
+	"four":                 '4',
+	"five":                 '5',
+	"six":                  '6',
+	"seven":                '7',
+	"eight":                '8',
+	"nine":                 '9',
+	"colon":                ':',
+	"semicolon":            ';',
+	"less-than-sign":       '<',
+	"equals-sign":          '=',
+	"greater-than-sign":    '>',
+	"question-mark":        '?',
+	"commercial-at":        '@',
+	"left-square-bracket":  '[',
+	"backslash":            '\\',
+	"reverse-solidus":      '\\',
+	"right-square-bracket": ']',
+	"circumflex":           '^',
+	"circumflex-accent":    '^',
+	"underscore":           '_',
+	"low-line":             '_',
+	"grave-accent":         '`',
+	"left-brace":           '{',
+	"left-curly-bracket":   '{',
+	"vertical-line":        '|',
+	"right-brace":          '}',
+	"right-curly-bracket":  '}',
+	"tilde":                '~',
+	"DEL":                  0x7f,
+})
+
+// withLetters adds the letters, which are named by themselves
+func withLetters(names map[string]rune) map[string]rune {
+	for c := 'A'; c <= 'Z'; c++ {
+		names[string(c)] = c
+	}
+	for c := 'a'; c <= 'z'; c++ {
+		names[string(c)] = c
+	}
+	return names
+}
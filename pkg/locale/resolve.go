@@ -0,0 +1,189 @@
+package locale
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Categories lists the category names, in the order locale prints them
+var Categories = []string{"LC_CTYPE", "LC_COLLATE", "LC_MONETARY", "LC_NUMERIC", "LC_TIME", "LC_MESSAGES"}
+
+// Name returns the locale selected for a category. LC_ALL overrides the
+// category's own variable, which overrides LANG. With none of them set, the
+// POSIX locale is used.
+func (l Locale) Name(category string) string {
+	if l.all != "" {
+		return l.all
+	}
+	if v := l.value(category); v != "" {
+		return v
+	}
+	if l.lang != "" {
+		return l.lang
+	}
+	return "POSIX"
+}
+
+// value returns the category's own variable
+func (l Locale) value(category string) string {
+	switch category {
+	case "LC_CTYPE":
+		return l.ctype
+	case "LC_COLLATE":
+		return l.collate
+	case "LC_MONETARY":
+		return l.monetary
+	case "LC_NUMERIC":
+		return l.numeric
+	case "LC_TIME":
+		return l.time
+	case "LC_MESSAGES":
+		return l.messages
+	}
+	return ""
+}
+
+var (
+	posixOnce sync.Once
+	posixDef  Def
+)
+
+// POSIX returns the built-in POSIX locale, which is also called C. Its
+// codeset is single-byte ASCII.
+func POSIX() Def {
+	posixOnce.Do(func() {
+		var err error
+		posixDef, err = ParseDef(strings.NewReader(posixSource))
+		if err != nil {
+			panic("locale: the built-in POSIX locale is invalid: " + err.Error())
+		}
+		posixDef.Ctype.codeset = "ANSI_X3.4-1968"
+		posixDef.Ctype.mbCurMax = 1
+	})
+	return posixDef
+}
+
+// SearchPath returns the directories searched for compiled locales: those in
+// LOCPATH, followed by DefaultPath
+func SearchPath() []string {
+	var dirs []string
+	for _, dir := range filepath.SplitList(os.Getenv("LOCPATH")) {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return append(dirs, DefaultPath)
+}
+
+// normalizeCodeset rewrites the codeset in a name like en_US.UTF-8 to the
+// form en_US.utf8, which is also commonly used
+func normalizeCodeset(name string) string {
+	dot := strings.IndexByte(name, '.')
+	if dot < 0 {
+		return name
+	}
+	codeset, modifier := name[dot+1:], ""
+	if at := strings.IndexByte(codeset, '@'); at >= 0 {
+		codeset, modifier = codeset[:at], codeset[at:]
+	}
+	codeset = strings.ToLower(strings.Replace(codeset, "-", "", -1))
+	return name[:dot+1] + codeset + modifier
+}
+
+// Find returns the path of a compiled locale. A name containing a slash is
+// a pathname; others are looked for in each directory of SearchPath.
+func Find(name string) (string, error) {
+	if strings.Contains(name, "/") {
+		if _, err := os.Stat(name); err != nil {
+			return "", err
+		}
+		return name, nil
+	}
+	candidates := []string{name}
+	if n := normalizeCodeset(name); n != name {
+		candidates = append(candidates, n)
+	}
+	for _, dir := range SearchPath() {
+		for _, c := range candidates {
+			path := filepath.Join(dir, c)
+			if fi, err := os.Stat(path); err == nil && fi.Mode().IsRegular() {
+				return path, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("locale %s not found", name)
+}
+
+// LookupDef returns the definition of the named locale: either a built-in,
+// or one compiled by localedef
+func LookupDef(name string) (Def, error) {
+	if name == "C" || name == "POSIX" {
+		return POSIX(), nil
+	}
+	path, err := Find(name)
+	if err != nil {
+		return Def{}, err
+	}
+	return LoadDef(path)
+}
+
+// Load builds the definition for each category from the locale selected for
+// it. A category whose locale can't be loaded falls back to POSIX, and the
+// first such failure is returned along with the definition.
+func (l Locale) Load() (Def, error) {
+	loaded := make(map[string]Def)
+	var firstErr error
+	var out Def
+	for _, category := range Categories {
+		name := l.Name(category)
+		def, ok := loaded[name]
+		if !ok {
+			var err error
+			if def, err = LookupDef(name); err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("cannot set %s to %s: %s", category, name, err)
+				}
+				def = POSIX()
+			}
+			loaded[name] = def
+		}
+		out.SetCategory(category, def)
+	}
+	return out, firstErr
+}
+
+// SetCategory replaces one category with the same category of another
+// definition
+func (def *Def) SetCategory(category string, from Def) {
+	switch category {
+	case "LC_CTYPE":
+		def.Ctype = from.Ctype
+	case "LC_COLLATE":
+		def.Collate = from.Collate
+	case "LC_MONETARY":
+		def.Monetary = from.Monetary
+	case "LC_NUMERIC":
+		def.Numeric = from.Numeric
+	case "LC_TIME":
+		def.Time = from.Time
+	case "LC_MESSAGES":
+		def.Messages = from.Messages
+	}
+}
+
+var (
+	currentOnce sync.Once
+	current     Def
+)
+
+// Current returns the locale selected by the environment, loading it the
+// first time it is called. Categories that can't be loaded use POSIX.
+func Current() Def {
+	currentOnce.Do(func() {
+		current, _ = FromEnv().Load()
+	})
+	return current
+}
@@ -0,0 +1,170 @@
+package locale
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPOSIXSource(t *testing.T) {
+	src, err := ioutil.ReadFile("POSIX.locale")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(src) != posixSource {
+		t.Errorf("posix.go is out of date with POSIX.locale")
+	}
+}
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		l        Locale
+		category string
+		want     string
+	}{
+		{Locale{}, "LC_TIME", "POSIX"},
+		{Locale{lang: "fr"}, "LC_TIME", "fr"},
+		{Locale{lang: "fr", time: "de"}, "LC_TIME", "de"},
+		{Locale{lang: "fr", time: "de"}, "LC_NUMERIC", "fr"},
+		{Locale{lang: "fr", time: "de", all: "C"}, "LC_TIME", "C"},
+	}
+	for _, test := range tests {
+		if got := test.l.Name(test.category); got != test.want {
+			t.Errorf("%+v: %s is %s, expected %s", test.l, test.category, got, test.want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "locale")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	os.Setenv("LOCPATH", filepath.Join(dir, "missing")+string(filepath.ListSeparator)+dir)
+	defer os.Unsetenv("LOCPATH")
+
+	input := "LC_NUMERIC\ndecimal_point \"<comma>\"\nthousands_sep \"\"\ngrouping -1\nEND LC_NUMERIC\n"
+	def, err := ParseDef(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := def.MarshalBinary()
+	if err := ioutil.WriteFile(filepath.Join(dir, "de_DE.utf8"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := Find("de_DE.UTF-8")
+	if err != nil || path != filepath.Join(dir, "de_DE.utf8") {
+		t.Errorf("found %q, %v", path, err)
+	}
+
+	got, err := Locale{lang: "C", numeric: "de_DE.UTF-8"}.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Numeric.decimalPoint != "," || got.Time.abday[0] != "Sun" {
+		t.Errorf("loaded %+v", got)
+	}
+	if got.Ctype.mbCurMax != 1 {
+		t.Errorf("the C locale has mb_cur_max %d", got.Ctype.mbCurMax)
+	}
+
+	got, err = Locale{lang: "xx_YY"}.Load()
+	if err == nil {
+		t.Errorf("expected an error loading a missing locale")
+	}
+	if got.Numeric.decimalPoint != "." {
+		t.Errorf("missing locale didn't fall back to POSIX: %+v", got.Numeric)
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		l    Locale
+		want string
+	}{
+		{Locale{}, "LANG=\nLC_CTYPE=\"POSIX\"\nLC_COLLATE=\"POSIX\"\nLC_MONETARY=\"POSIX\"\n" +
+			"LC_NUMERIC=\"POSIX\"\nLC_TIME=\"POSIX\"\nLC_MESSAGES=\"POSIX\"\nLC_ALL=\n"},
+		{Locale{lang: "fr", time: "de"}, "LANG=fr\nLC_CTYPE=\"fr\"\nLC_COLLATE=\"fr\"\nLC_MONETARY=\"fr\"\n" +
+			"LC_NUMERIC=\"fr\"\nLC_TIME=de\nLC_MESSAGES=\"fr\"\nLC_ALL=\n"},
+		{Locale{lang: "fr", time: "de", all: "C"}, "LANG=fr\nLC_CTYPE=\"C\"\nLC_COLLATE=\"C\"\nLC_MONETARY=\"C\"\n" +
+			"LC_NUMERIC=\"C\"\nLC_TIME=\"C\"\nLC_MESSAGES=\"C\"\nLC_ALL=C\n"},
+	}
+	for _, test := range tests {
+		if got := test.l.String(); got != test.want {
+			t.Errorf("%+v printed\n%s\nexpected\n%s", test.l, got, test.want)
+		}
+	}
+}
+
+func TestKeyword(t *testing.T) {
+	def := POSIX()
+	tests := []struct {
+		name, category, value, assignment string
+	}{
+		{"decimal_point", "LC_NUMERIC", ".", `decimal_point="."`},
+		{"grouping", "LC_NUMERIC", "-1", "grouping=-1"},
+		{"p_sign_posn", "LC_MONETARY", "-1", "p_sign_posn=-1"},
+		{"am_pm", "LC_TIME", "AM;PM", `am_pm="AM;PM"`},
+		{"charmap", "LC_CTYPE", "ANSI_X3.4-1968", `charmap="ANSI_X3.4-1968"`},
+		{"yesexpr", "LC_MESSAGES", "^[yY]", `yesexpr="^[yY]"`},
+	}
+	for _, test := range tests {
+		k, ok := def.Keyword(test.name)
+		if !ok {
+			t.Errorf("%s not found", test.name)
+			continue
+		}
+		if k.Category != test.category || k.String() != test.value || k.Assignment() != test.assignment {
+			t.Errorf("%s is %s %q %q", test.name, k.Category, k.String(), k.Assignment())
+		}
+	}
+	if _, ok := def.Keyword("LC_NUMERIC"); ok {
+		t.Errorf("a category was found as a keyword")
+	}
+	if n := len(def.Keywords("LC_NUMERIC")); n != 3 {
+		t.Errorf("LC_NUMERIC has %d keywords", n)
+	}
+	if got := quote(`a"$b`); got != `"a\"\$b"` {
+		t.Errorf("quoted as %s", got)
+	}
+}
+
+func TestAvailable(t *testing.T) {
+	dir, err := ioutil.TempDir("", "locale")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	os.Setenv("LOCPATH", dir)
+	defer os.Unsetenv("LOCPATH")
+	os.Setenv("I18NPATH", dir)
+	defer os.Unsetenv("I18NPATH")
+
+	data, _ := POSIX().MarshalBinary()
+	os.Mkdir(filepath.Join(dir, "charmaps"), 0755)
+	for name, content := range map[string][]byte{
+		"fr_FR":               data,
+		".hidden":             data,
+		"README":              []byte("not a locale"),
+		"charmaps/ISO-8859-1": []byte("CHARMAP\nEND CHARMAP\n"),
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got, want := Available(), []string{"C", "POSIX", "fr_FR"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("available locales are %q, expected %q", got, want)
+	}
+	if got, want := Charmaps(), []string{"ISO-8859-1", "UTF-8"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("available charmaps are %q, expected %q", got, want)
+	}
+	if path, err := FindCharmap("ISO-8859-1"); err != nil || path != filepath.Join(dir, "charmaps", "ISO-8859-1") {
+		t.Errorf("found %q, %v", path, err)
+	}
+}
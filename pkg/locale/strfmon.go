@@ -0,0 +1,310 @@
+package locale
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Format writes monetary values as described by format, in the way strfmon
+// does. Each conversion specification takes the next value, and has the form
+//
+//	%[flags][width][#left][.right]i|n
+//
+// where i uses the international currency symbol and n the local one. The
+// flags are =f to pad with f, ^ to not group digits, + or ( for how
+// negative values are shown, ! to leave out the currency symbol, and - to
+// justify the value to the left of its field.
+func (m Monetary) Format(format string, values ...float64) (string, error) {
+	var out strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			out.WriteByte(format[i])
+			continue
+		}
+		if i+1 < len(format) && format[i+1] == '%' {
+			out.WriteByte('%')
+			i++
+			continue
+		}
+		c, end, err := parseMonetarySpec(format, i)
+		if err != nil {
+			return out.String(), err
+		}
+		if len(values) == 0 {
+			return out.String(), errors.New("not enough values for the format")
+		}
+		out.WriteString(m.format(c, values[0]))
+		values = values[1:]
+		i = end
+	}
+	return out.String(), nil
+}
+
+// monetarySpec is a parsed strfmon conversion specification
+type monetarySpec struct {
+	fill          string
+	noGrouping    bool
+	parentheses   bool
+	noSymbol      bool
+	left          bool
+	width         int
+	leftPrec      int // -1 if not given
+	rightPrec     int // -1 if not given
+	international bool
+}
+
+// parseMonetarySpec reads the specification starting at the % at format[i],
+// returning it and the index of its last byte
+func parseMonetarySpec(format string, i int) (monetarySpec, int, error) {
+	c := monetarySpec{fill: " ", leftPrec: -1, rightPrec: -1}
+	start := i
+	number := func() int {
+		n := 0
+		for i < len(format) && isDigit(format[i]) {
+			n = n*10 + int(format[i]-'0')
+			i++
+		}
+		return n
+	}
+
+	i++
+flags:
+	for i < len(format) {
+		switch format[i] {
+		case '=':
+			if i+1 >= len(format) {
+				break flags
+			}
+			_, size := utf8.DecodeRuneInString(format[i+1:])
+			c.fill = format[i+1 : i+1+size]
+			i += size
+		case '^':
+			c.noGrouping = true
+		case '+':
+			c.parentheses = false
+		case '(':
+			c.parentheses = true
+		case '!':
+			c.noSymbol = true
+		case '-':
+			c.left = true
+		default:
+			break flags
+		}
+		i++
+	}
+	c.width = number()
+	if i < len(format) && format[i] == '#' {
+		i++
+		c.leftPrec = number()
+	}
+	if i < len(format) && format[i] == '.' {
+		i++
+		c.rightPrec = number()
+	}
+	if i >= len(format) {
+		return c, i, fmt.Errorf("incomplete conversion specification %q", format[start:])
+	}
+	switch format[i] {
+	case 'i':
+		c.international = true
+	case 'n':
+	default:
+		return c, i, fmt.Errorf("unknown conversion specification %q", format[start:i+1])
+	}
+	return c, i, nil
+}
+
+// ifUnset returns n, or fallback if n is the -1 of an unset keyword
+func ifUnset(n, fallback int) int {
+	if n < 0 {
+		return fallback
+	}
+	return n
+}
+
+// format writes one value
+func (m Monetary) format(c monetarySpec, value float64) string {
+	negative := value < 0
+	if negative {
+		value = -value
+	}
+
+	symbol, space := m.currencySymbol, " "
+	frac := m.fracDigits
+	csPrecedes := [2]int{m.pCsPrecedes, m.nCsPrecedes}
+	sepBySpace := [2]int{m.pSepBySpace, m.nSepBySpace}
+	signPosn := [2]int{m.pSignPosn, m.nSignPosn}
+	if c.international {
+		// The fourth character of int_curr_symbol separates it from the value
+		symbol = m.intCurrSymbol
+		if len(symbol) == 4 {
+			symbol, space = symbol[:3], symbol[3:]
+		}
+		frac = ifUnset(m.intFracDigits, frac)
+		csPrecedes[0] = ifUnset(m.intPcsPrecedes, csPrecedes[0])
+		csPrecedes[1] = ifUnset(m.intNcsPrecedes, csPrecedes[1])
+		sepBySpace[0] = ifUnset(m.intPsepBySpace, sepBySpace[0])
+		sepBySpace[1] = ifUnset(m.intNsepBySpace, sepBySpace[1])
+		signPosn[0] = ifUnset(m.intPsignPosn, signPosn[0])
+		signPosn[1] = ifUnset(m.intNsignPosn, signPosn[1])
+	}
+	if c.noSymbol {
+		symbol = ""
+	}
+	if c.rightPrec >= 0 {
+		frac = c.rightPrec
+	}
+	frac = ifUnset(frac, 2)
+
+	// The digits, grouped and padded to the left precision
+	s := strconv.FormatFloat(value, 'f', frac, 64)
+	intPart, fracPart := s, ""
+	if dot := strings.IndexByte(s, '.'); dot >= 0 {
+		intPart, fracPart = s[:dot], s[dot+1:]
+	}
+	grouping := m.monGrouping
+	if c.noGrouping {
+		grouping = nil
+	}
+	num := group(intPart, m.monThousandsSep, grouping)
+	if c.leftPrec > len(intPart) {
+		width := utf8.RuneCountInString(group(strings.Repeat("0", c.leftPrec), m.monThousandsSep, grouping))
+		num = strings.Repeat(c.fill, width-utf8.RuneCountInString(num)) + num
+	}
+	if fracPart != "" {
+		num += orDefault(m.monDecimalPoint, ".") + fracPart
+	}
+
+	signs := [2]string{m.positiveSign, orDefault(m.negativeSign, "-")}
+	var prefix, suffix [2]string
+	for i := range prefix {
+		posn := ifUnset(signPosn[i], 1)
+		if c.parentheses && i == 1 {
+			posn = 0
+		}
+		prefix[i], suffix[i] = surround(signs[i], symbol, space, csPrecedes[i] != 0, ifUnset(sepBySpace[i], 0), posn)
+	}
+
+	n := 0
+	if negative {
+		n = 1
+	}
+	out := prefix[n] + num + suffix[n]
+	if c.leftPrec >= 0 {
+		// Positive and negative values line up
+		out = padTo(prefix[n], maxLen(prefix[0], prefix[1]), false) + num +
+			padTo(suffix[n], maxLen(suffix[0], suffix[1]), true)
+	}
+	return padTo(out, c.width, c.left)
+}
+
+func maxLen(a, b string) int {
+	if n, m := utf8.RuneCountInString(a), utf8.RuneCountInString(b); n > m {
+		return n
+	}
+	return utf8.RuneCountInString(b)
+}
+
+// padTo pads s with spaces to width characters, on the right if left is set
+func padTo(s string, width int, left bool) string {
+	n := width - utf8.RuneCountInString(s)
+	if n <= 0 {
+		return s
+	}
+	if left {
+		return s + strings.Repeat(" ", n)
+	}
+	return strings.Repeat(" ", n) + s
+}
+
+// surround returns what goes before and after the digits of a value, given
+// the sign, the currency symbol and how the locale places them. When sign
+// and symbol are next to each other, sep_by_space 1 puts space between them
+// and the value, and 2 puts it between them. Otherwise 1 separates the
+// symbol from the value, and 2 the sign from the value.
+func surround(sign, symbol, space string, csPrecedes bool, sepBySpace, signPosn int) (string, string) {
+	if signPosn == 0 {
+		if symbol == "" {
+			return "(", ")"
+		}
+		if csPrecedes {
+			return "(" + symbol + spaceIf(sepBySpace == 1, space), ")"
+		}
+		return "(", spaceIf(sepBySpace == 1, space) + symbol + ")"
+	}
+
+	// The parts in order: 's' is the sign, 'c' the symbol and '#' the value
+	type part struct {
+		kind byte
+		text string
+	}
+	parts := []part{{'#', ""}, {'c', symbol}}
+	if csPrecedes {
+		parts = []part{{'c', symbol}, {'#', ""}}
+	}
+	at := 0 // Where the sign goes
+	switch signPosn {
+	case 2:
+		at = 2
+	case 3:
+		at = 1 - b2i(csPrecedes)
+	case 4:
+		at = 2 - b2i(csPrecedes)
+	}
+	parts = append(parts[:at], append([]part{{'s', sign}}, parts[at:]...)...)
+
+	var kinds []byte
+	var kept []part
+	for _, p := range parts {
+		if p.kind == '#' || p.text != "" {
+			kinds = append(kinds, p.kind)
+			kept = append(kept, p)
+		}
+	}
+	adjacent := strings.Contains(string(kinds), "sc") || strings.Contains(string(kinds), "cs")
+	spaced := func(a, b byte) bool {
+		pair := func(x, y byte) bool { return a == x && b == y || a == y && b == x }
+		switch {
+		case sepBySpace == 1 && adjacent:
+			return a == '#' || b == '#'
+		case sepBySpace == 1:
+			return pair('c', '#')
+		case sepBySpace == 2 && adjacent:
+			return pair('s', 'c')
+		case sepBySpace == 2:
+			return pair('s', '#')
+		}
+		return false
+	}
+
+	var before, after strings.Builder
+	out := &before
+	for i, p := range kept {
+		if i > 0 && spaced(kinds[i-1], kinds[i]) {
+			out.WriteString(space)
+		}
+		if p.kind == '#' {
+			out = &after
+		}
+		out.WriteString(p.text)
+	}
+	return before.String(), after.String()
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func spaceIf(b bool, space string) string {
+	if b {
+		return space
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+package locale
+
+import (
+	"strings"
+	"testing"
+)
+
+const enUSMonetary = `LC_MONETARY
+int_curr_symbol     "<U>SD<space>"
+currency_symbol     "<dollar-sign>"
+mon_decimal_point   "<period>"
+mon_thousands_sep   "<comma>"
+mon_grouping        3;3
+positive_sign       ""
+negative_sign       "<hyphen>"
+int_frac_digits     2
+frac_digits         2
+p_cs_precedes       1
+p_sep_by_space      0
+n_cs_precedes       1
+n_sep_by_space      0
+p_sign_posn         1
+n_sign_posn         1
+int_p_sep_by_space  1
+int_n_sep_by_space  1
+END LC_MONETARY
+`
+
+const deDEMonetary = `LC_MONETARY
+int_curr_symbol     "EUR "
+currency_symbol     "<U20AC>"
+mon_decimal_point   "<comma>"
+mon_thousands_sep   "<period>"
+mon_grouping        3
+positive_sign       ""
+negative_sign       "<hyphen>"
+int_frac_digits     2
+frac_digits         2
+p_cs_precedes       0
+p_sep_by_space      1
+n_cs_precedes       0
+n_sep_by_space      1
+p_sign_posn         1
+n_sign_posn         1
+END LC_MONETARY
+`
+
+func parseMonetary(t *testing.T, src string) Monetary {
+	def, err := ParseDef(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return def.Monetary
+}
+
+func TestFormatMonetary(t *testing.T) {
+	us := parseMonetary(t, enUSMonetary)
+	de := parseMonetary(t, deDEMonetary)
+	posix := POSIX().Monetary
+	tests := []struct {
+		m      Monetary
+		format string
+		values []float64
+		want   string
+	}{
+		{us, "%n|%n", []float64{1234.567, -1234.567}, "$1,234.57|-$1,234.57"},
+		{us, "%i|%i", []float64{1234.567, -1234.567}, "USD 1,234.57|-USD 1,234.57"},
+		{us, "[%^=*#6n] [%=*#6i]", []float64{1234.567, 1234.567}, "[ $**1234.57] [ USD **1,234.57]"},
+		{us, "[%11n] [%-11n] [%(n] [%!n]", []float64{123.45, 123.45, -123.45, -123.45}, "[    $123.45] [$123.45    ] [($123.45)] [-123.45]"},
+		{us, "%(#5n|%(#5n", []float64{1, -1}, " $     1.00 |($     1.00)"},
+		{us, "%.0n %.3n 100%%", []float64{1234.6, 0.1}, "$1,235 $0.100 100%"},
+		{de, "%n|%n|%i", []float64{1234.567, -1234.567, 1234567.891}, "1.234,57 €|-1.234,57 €|1.234.567,89 EUR"},
+		{posix, "%n|%n|%i", []float64{1234.567, -1234.5, 0}, "1234.57|-1234.50|0.00"},
+	}
+	for _, test := range tests {
+		got, err := test.m.Format(test.format, test.values...)
+		if err != nil {
+			t.Errorf("%q: %s", test.format, err)
+		} else if got != test.want {
+			t.Errorf("%q formatted %v as %q, expected %q", test.format, test.values, got, test.want)
+		}
+	}
+
+	for _, format := range []string{"%n %n", "%q", "%#5"} {
+		if _, err := us.Format(format, 1); err == nil {
+			t.Errorf("expected an error for %q", format)
+		}
+	}
+}
+
+func TestSurround(t *testing.T) {
+	// Each of the sign positions and separations, for a currency symbol
+	// before and after the value
+	tests := []struct {
+		csPrecedes bool
+		sep, posn  int
+		want       string
+	}{
+		{true, 0, 1, "-$1"}, {true, 1, 1, "-$ 1"}, {true, 2, 1, "- $1"},
+		{false, 0, 1, "-1$"}, {false, 1, 1, "-1 $"}, {false, 2, 1, "- 1$"},
+		{true, 1, 2, "$ 1-"}, {true, 2, 2, "$1 -"}, {false, 1, 2, "1 $-"}, {false, 2, 2, "1$ -"},
+		{true, 1, 3, "-$ 1"}, {true, 2, 3, "- $1"}, {false, 1, 3, "1 -$"}, {false, 2, 3, "1- $"},
+		{true, 1, 4, "$- 1"}, {true, 2, 4, "$ -1"}, {false, 1, 4, "1 $-"}, {false, 2, 4, "1$ -"},
+		{true, 1, 0, "($ 1)"}, {false, 0, 0, "(1$)"},
+	}
+	for _, test := range tests {
+		before, after := surround("-", "$", " ", test.csPrecedes, test.sep, test.posn)
+		if got := before + "1" + after; got != test.want {
+			t.Errorf("%+v gave %q, expected %q", test, got, test.want)
+		}
+	}
+}
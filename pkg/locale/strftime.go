@@ -0,0 +1,303 @@
+package locale
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default formats, used when the locale leaves the corresponding keyword empty
+const (
+	defaultDTFmt    = "%a %b %e %H:%M:%S %Y"
+	defaultDFmt     = "%m/%d/%y"
+	defaultTFmt     = "%H:%M:%S"
+	defaultTFmtAmPm = "%I:%M:%S %p"
+)
+
+func orDefault(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+// Format writes t as described by format, in the way strftime does. Every
+// conversion specification in POSIX is supported, including the E and O
+// modifiers and the 0 and + flags with a field width for %C, %F, %G and %Y.
+// A specification that isn't recognised is copied to the output.
+func (lt Time) Format(t time.Time, format string) string {
+	var out strings.Builder
+	lt.format(&out, t, format, 0)
+	return out.String()
+}
+
+// spec is a parsed conversion specification
+type spec struct {
+	flag     byte // '0', '+' or 0
+	width    int  // The minimum field width, or 0
+	modifier byte // 'E', 'O' or 0
+	verb     byte
+}
+
+// parseSpec reads the conversion specification that starts after the % at
+// format[i], returning it and the index of its last byte
+func parseSpec(format string, i int) (spec, int, bool) {
+	var s spec
+	i++
+	if i < len(format) && (format[i] == '0' || format[i] == '+') {
+		s.flag = format[i]
+		i++
+	}
+	for i < len(format) && isDigit(format[i]) {
+		s.width = s.width*10 + int(format[i]-'0')
+		i++
+	}
+	if i < len(format) && (format[i] == 'E' || format[i] == 'O') {
+		s.modifier = format[i]
+		i++
+	}
+	if i >= len(format) {
+		return s, len(format) - 1, false
+	}
+	s.verb = format[i]
+	return s, i, true
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// maxNesting is how deep formats may expand into other formats. The locale's
+// own formats may refer to each other, or to themselves, without end.
+const maxNesting = 8
+
+// format writes t as described by format, which is nested in depth others
+func (lt Time) format(out *strings.Builder, t time.Time, format string, depth int) {
+	if depth > maxNesting {
+		out.WriteString(format)
+		return
+	}
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			out.WriteByte(format[i])
+			continue
+		}
+		s, end, ok := parseSpec(format, i)
+		if !ok || !lt.convert(out, t, s, depth) {
+			out.WriteString(format[i : end+1])
+		}
+		i = end
+	}
+}
+
+// era returns the era the day of t falls in
+func (lt Time) era(t time.Time) (Era, bool) {
+	for _, era := range lt.eras {
+		if era.contains(t.Year(), int(t.Month()), t.Day()) {
+			return era, true
+		}
+	}
+	return Era{}, false
+}
+
+// number writes n with at least width digits. With the O modifier, the
+// locale's alternative digits are used when they include n.
+func (lt Time) number(out *strings.Builder, s spec, n, width int, pad byte) {
+	if s.modifier == 'O' && n >= 0 && n < len(lt.altDigits) {
+		out.WriteString(lt.altDigits[n])
+		return
+	}
+	out.WriteString(padNumber(n, width, pad))
+}
+
+// padNumber writes n with at least width characters, padding with pad
+func padNumber(n, width int, pad byte) string {
+	digits, sign := strconv.Itoa(n), ""
+	if n < 0 {
+		digits, sign = digits[1:], "-"
+	}
+	padding := ""
+	if n := width - len(sign) - len(digits); n > 0 {
+		padding = strings.Repeat(string(pad), n)
+	}
+	if pad == '0' {
+		return sign + padding + digits
+	}
+	return padding + sign + digits
+}
+
+// year writes a year or century with the flag and width of s. Without a flag
+// it is padded with zeros to min digits. The + flag adds a + when the number
+// has more than min digits, counting it in the width.
+func year(out *strings.Builder, s spec, n, min int) {
+	width := min
+	if s.flag == 0 {
+		out.WriteString(padNumber(n, width, '0'))
+		return
+	}
+	if s.width > 0 {
+		width = s.width
+	}
+	digits := strconv.Itoa(n)
+	if s.flag == '+' && n >= 0 && len(digits) > min {
+		out.WriteByte('+')
+		width--
+	}
+	out.WriteString(padNumber(n, width, '0'))
+}
+
+// floorDiv divides rounding towards minus infinity, so that the century of
+// -1 is -1
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+func floorMod(a, b int) int {
+	return a - floorDiv(a, b)*b
+}
+
+// convert writes one conversion, reporting whether the verb was recognised
+func (lt Time) convert(out *strings.Builder, t time.Time, s spec, depth int) bool {
+	// Modifiers only apply to some conversions, and are ignored elsewhere
+	if s.modifier == 'E' && !strings.ContainsRune("cCxXyY", rune(s.verb)) ||
+		s.modifier == 'O' && !strings.ContainsRune("deHImMSuUVwWy", rune(s.verb)) {
+		s.modifier = 0
+	}
+
+	switch s.verb {
+	case 'a':
+		out.WriteString(item(lt.abday, int(t.Weekday())))
+	case 'A':
+		out.WriteString(item(lt.day, int(t.Weekday())))
+	case 'b', 'h':
+		out.WriteString(item(lt.abmon, int(t.Month())-1))
+	case 'B':
+		out.WriteString(item(lt.mon, int(t.Month())-1))
+	case 'c':
+		f := orDefault(lt.dtFmt, defaultDTFmt)
+		if s.modifier == 'E' {
+			f = orDefault(lt.eraDTFmt, f)
+		}
+		lt.format(out, t, f, depth+1)
+	case 'C':
+		if era, ok := lt.era(t); ok && s.modifier == 'E' {
+			out.WriteString(era.name)
+		} else {
+			year(out, s, floorDiv(t.Year(), 100), 2)
+		}
+	case 'd':
+		lt.number(out, s, t.Day(), 2, '0')
+	case 'D':
+		lt.format(out, t, "%m/%d/%y", depth+1)
+	case 'e':
+		lt.number(out, s, t.Day(), 2, ' ')
+	case 'F':
+		ys := spec{flag: '+', width: 4, verb: 'Y'}
+		if s.flag != 0 || s.width != 0 {
+			ys.flag = s.flag
+			ys.width = s.width - 6
+		}
+		year(out, ys, t.Year(), 4)
+		lt.format(out, t, "-%m-%d", depth+1)
+	case 'g':
+		y, _ := t.ISOWeek()
+		out.WriteString(padNumber(floorMod(y, 100), 2, '0'))
+	case 'G':
+		y, _ := t.ISOWeek()
+		year(out, s, y, 4)
+	case 'H':
+		lt.number(out, s, t.Hour(), 2, '0')
+	case 'I':
+		lt.number(out, s, (t.Hour()+11)%12+1, 2, '0')
+	case 'j':
+		out.WriteString(padNumber(t.YearDay(), 3, '0'))
+	case 'm':
+		lt.number(out, s, int(t.Month()), 2, '0')
+	case 'M':
+		lt.number(out, s, t.Minute(), 2, '0')
+	case 'n':
+		out.WriteByte('\n')
+	case 'p':
+		if t.Hour() < 12 {
+			out.WriteString(lt.am)
+		} else {
+			out.WriteString(lt.pm)
+		}
+	case 'r':
+		lt.format(out, t, orDefault(lt.tFmtAmPm, defaultTFmtAmPm), depth+1)
+	case 'R':
+		lt.format(out, t, "%H:%M", depth+1)
+	case 's':
+		out.WriteString(strconv.FormatInt(t.Unix(), 10))
+	case 'S':
+		lt.number(out, s, t.Second(), 2, '0')
+	case 't':
+		out.WriteByte('\t')
+	case 'T':
+		lt.format(out, t, "%H:%M:%S", depth+1)
+	case 'u':
+		lt.number(out, s, (int(t.Weekday())+6)%7+1, 1, '0')
+	case 'U':
+		lt.number(out, s, (t.YearDay()+6-int(t.Weekday()))/7, 2, '0')
+	case 'V':
+		_, w := t.ISOWeek()
+		lt.number(out, s, w, 2, '0')
+	case 'w':
+		lt.number(out, s, int(t.Weekday()), 1, '0')
+	case 'W':
+		lt.number(out, s, (t.YearDay()+6-(int(t.Weekday())+6)%7)/7, 2, '0')
+	case 'x':
+		f := orDefault(lt.dFmt, defaultDFmt)
+		if s.modifier == 'E' {
+			f = orDefault(lt.eraDFmt, f)
+		}
+		lt.format(out, t, f, depth+1)
+	case 'X':
+		f := orDefault(lt.tFmt, defaultTFmt)
+		if s.modifier == 'E' {
+			f = orDefault(lt.eraTFmt, f)
+		}
+		lt.format(out, t, f, depth+1)
+	case 'y':
+		if era, ok := lt.era(t); ok && s.modifier == 'E' {
+			out.WriteString(padNumber(era.year(t.Year()), 1, '0'))
+		} else {
+			lt.number(out, s, floorMod(t.Year(), 100), 2, '0')
+		}
+	case 'Y':
+		if era, ok := lt.era(t); ok && s.modifier == 'E' {
+			lt.format(out, t, orDefault(era.format, "%EC%Ey"), depth+1)
+		} else {
+			year(out, s, t.Year(), 4)
+		}
+	case 'z':
+		_, offset := t.Zone()
+		sign := byte('+')
+		if offset < 0 {
+			sign, offset = '-', -offset
+		}
+		out.WriteByte(sign)
+		out.WriteString(padNumber(offset/3600, 2, '0'))
+		out.WriteString(padNumber(offset/60%60, 2, '0'))
+	case 'Z':
+		name, _ := t.Zone()
+		out.WriteString(name)
+	case '%':
+		out.WriteByte('%')
+	default:
+		return false
+	}
+	return true
+}
+
+// item returns list[i], or "" when the locale doesn't define it
+func item(list []string, i int) string {
+	if i < 0 || i >= len(list) {
+		return ""
+	}
+	return list[i]
+}
@@ -0,0 +1,138 @@
+package locale
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// japaneseTime returns the LC_TIME of the era test, which has eras and
+// alternative digits
+func japaneseTime(t testing.TB) Time {
+	def, err := ParseDef(strings.NewReader(parseTests[6]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return def.Time
+}
+
+func TestFormat(t *testing.T) {
+	posix := POSIX().Time
+	japanese := japaneseTime(t)
+	date := time.Date(2009, 2, 13, 23, 31, 30, 0, time.UTC)
+	tests := []struct {
+		lt     Time
+		t      time.Time
+		format string
+		want   string
+	}{
+		{posix, date, "%a %A %b %B %h", "Fri Friday Feb February Feb"},
+		{posix, date, "%c", "Fri Feb 13 23:31:30 2009"},
+		{posix, date, "%C %d %D %e %F", "20 13 02/13/09 13 2009-02-13"},
+		{posix, date, "%g %G %H %I %j %m %M", "09 2009 23 11 044 02 31"},
+		{posix, date, "%p %r %R %S %T", "PM 11:31:30 PM 23:31 30 23:31:30"},
+		{posix, date, "%u %U %V %w %W", "5 06 07 5 06"},
+		{posix, date, "%x %X %y %Y %z %Z %s", "02/13/09 23:31:30 09 2009 +0000 UTC 1234567890"},
+		{posix, date, "%n%t%%", "\n\t%"},
+		{posix, date, "%Ec|%EY|%Od|%OH", "Fri Feb 13 23:31:30 2009|2009|13|23"},
+		{posix, date, "%Q %Ed %", "%Q 13 %"},
+		{posix, date.In(time.FixedZone("X", -(5*3600 + 30*60))), "%H %z", "18 -0530"},
+		{posix, time.Date(2009, 2, 3, 1, 2, 3, 0, time.UTC), "%e|%I|%p", " 3|01|AM"},
+		{posix, time.Date(2008, 12, 29, 0, 0, 0, 0, time.UTC), "%G %g %V", "2009 09 01"},
+
+		// Flags and field widths
+		{posix, time.Date(5, 1, 1, 0, 0, 0, 0, time.UTC), "%Y %C %y", "0005 00 05"},
+		{posix, date, "%+6Y|%06Y|%+F|%+12F", "002009|002009|2009-02-13|002009-02-13"},
+		{posix, time.Date(12345, 1, 1, 0, 0, 0, 0, time.UTC), "%Y|%+4Y|%F|%+3C", "12345|+12345|+12345-01-01|+123"},
+
+		// Eras and alternative digits
+		{japanese, date, "%EC|%Ey|%EY|%Ex", "平成|21|平成21年|平成21年02月13日"},
+		{japanese, date, "%Od日%OH時", "十三日二十三時"},
+		{japanese, time.Date(1989, 6, 1, 0, 0, 0, 0, time.UTC), "%EY", "平成元年"},
+		{japanese, time.Date(1926, 12, 25, 0, 0, 0, 0, time.UTC), "%EY", "昭和元年"},
+		{japanese, time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC), "%EY", "明治33年"},
+		{japanese, time.Date(-5, 1, 1, 0, 0, 0, 0, time.UTC), "%EY", "紀元前5年"},
+	}
+	for _, test := range tests {
+		if got := test.lt.Format(test.t, test.format); got != test.want {
+			t.Errorf("%q formatted %v as %q, expected %q", test.format, test.t, got, test.want)
+		}
+	}
+}
+
+func TestFormatSelfReference(t *testing.T) {
+	// Formats that expand into themselves are left unexpanded, eventually
+	lt := POSIX().Time
+	lt.dtFmt = "%c"
+	lt.dFmt = "%Y %X"
+	lt.tFmt = "%x"
+	date := time.Date(2009, 2, 13, 23, 31, 30, 0, time.UTC)
+	if got, want := lt.Format(date, "%c"), "%c"; got != want {
+		t.Errorf("%%c: got %q, expected %q", got, want)
+	}
+	if got, want := lt.Format(date, "%x"), "2009 2009 2009 2009 %Y %X"; got != want {
+		t.Errorf("%%x: got %q, expected %q", got, want)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	posix := POSIX().Time
+	japanese := japaneseTime(t)
+	date := time.Date(2009, 2, 13, 23, 31, 30, 0, time.UTC)
+	tests := []struct {
+		lt     Time
+		value  string
+		format string
+		want   time.Time
+	}{
+		{posix, "Fri Feb 13 23:31:30 2009", "%c", date},
+		{posix, "friday  FEBRUARY 13 11:31:30 pm 2009", "%A %B %d %r %Y", date},
+		{posix, "2009-02-13T23:31:30+0100", "%FT%T%z", time.Date(2009, 2, 13, 23, 31, 30, 0, time.FixedZone("", 3600))},
+		{posix, "2009 044", "%Y %j", time.Date(2009, 2, 13, 0, 0, 0, 0, time.UTC)},
+		{posix, "02/13/68", "%D", time.Date(2068, 2, 13, 0, 0, 0, 0, time.UTC)},
+		{posix, "02/13/69", "%x", time.Date(1969, 2, 13, 0, 0, 0, 0, time.UTC)},
+		{posix, "19 09", "%C %y", time.Date(1909, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{posix, "12:05 AM", "%I:%M %p", time.Date(0, 1, 1, 0, 5, 0, 0, time.UTC)},
+		{posix, "1234567890", "%s", date},
+		{posix, "100%", "%Y%%", time.Date(100, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{japanese, "平成21年02月13日", "%Ex", time.Date(2009, 2, 13, 0, 0, 0, 0, time.UTC)},
+		{japanese, "平成元年06月01日", "%Ex", time.Date(1989, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{japanese, "明治33年", "%EY", time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{japanese, "紀元前5年", "%EY", time.Date(-5, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{japanese, "昭和 2", "%EC %Ey", time.Date(1927, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{japanese, "二月十三日", "%Om月%Od日", time.Date(0, 2, 13, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		got, err := test.lt.Parse(test.value, test.format)
+		if err != nil {
+			t.Errorf("%q: %s", test.value, err)
+		} else if !got.Equal(test.want) {
+			t.Errorf("%q parsed as %v, expected %v", test.value, got, test.want)
+		}
+	}
+
+	for _, test := range []struct{ value, format string }{
+		{"2009-02-30", "%F"},
+		{"Fri Feb", "%c"},
+		{"2009-02-13 extra", "%F"},
+		{"24:00", "%H:%M"},
+		{"Frday", "%a"},
+		{"13", "%"},
+		{"13", "%Q"},
+	} {
+		if got, err := posix.Parse(test.value, test.format); err == nil {
+			t.Errorf("%q with %q parsed as %v, expected an error", test.value, test.format, got)
+		}
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	posix := POSIX().Time
+	date := time.Date(1999, 12, 31, 8, 59, 1, 0, time.UTC)
+	for _, format := range []string{"%c", "%x %X", "%D %r", "%F %T %z", "%A, %e %B %Y %R:%S", "%s"} {
+		got, err := posix.Parse(posix.Format(date, format), format)
+		if err != nil || !got.Equal(date) {
+			t.Errorf("%q: %v, %v", format, got, err)
+		}
+	}
+}
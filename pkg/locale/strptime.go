@@ -0,0 +1,385 @@
+package locale
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Parse reads a time written as described by format, in the way strptime
+// does. White space in the format matches any amount of white space in the
+// value, including none, and names are matched without regard to case.
+// Fields that aren't given are taken from January 1st of year 0, at
+// midnight UTC, as with the time package's Parse.
+func (lt Time) Parse(value, format string) (time.Time, error) {
+	p := &timeParser{lt: lt, value: value, month: 1, day: 1, era: -1}
+	if err := p.parse(format); err != nil {
+		return time.Time{}, err
+	}
+	if p.pos < len(p.value) {
+		return time.Time{}, fmt.Errorf("parsing %q: extra text %q", value, value[p.pos:])
+	}
+	return p.time(value)
+}
+
+// timeParser holds the fields read so far
+type timeParser struct {
+	lt    Time
+	value string
+	pos   int
+
+	year, century, yy      int
+	haveYear, haveCentury  bool
+	haveYY                 bool
+	month, day, yday       int
+	haveMonthDay, haveYday bool
+	hour, minute, second   int
+	pm, havePM             bool
+	era, eraYear           int // era is an index into lt.eras, or -1
+	haveEraYear            bool
+	unix                   int64
+	haveUnix               bool
+	loc                    *time.Location
+}
+
+// errorf reports a failure at the current position
+func (p *timeParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("parsing %q at %q: %s", p.value, p.value[p.pos:], fmt.Sprintf(format, args...))
+}
+
+func (p *timeParser) skipSpace() {
+	for p.pos < len(p.value) {
+		r, size := utf8.DecodeRuneInString(p.value[p.pos:])
+		if !unicode.IsSpace(r) {
+			return
+		}
+		p.pos += size
+	}
+}
+
+// hasPrefix reports whether the rest of the value starts with s, ignoring
+// case
+func (p *timeParser) hasPrefix(s string) bool {
+	rest := p.value[p.pos:]
+	return s != "" && len(rest) >= len(s) && strings.EqualFold(rest[:len(s)], s)
+}
+
+// name reads the longest of the names given, returning its index in its list
+func (p *timeParser) name(what string, lists ...[]string) (int, error) {
+	best, length := -1, 0
+	for _, list := range lists {
+		for i, name := range list {
+			if len(name) > length && p.hasPrefix(name) {
+				best, length = i, len(name)
+			}
+		}
+	}
+	if best < 0 {
+		return 0, p.errorf("expected %s", what)
+	}
+	p.pos += length
+	return best, nil
+}
+
+// number reads a decimal number of at most digits digits, after optional
+// white space, and checks that it is between min and max. With the O
+// modifier, the locale's alternative digits are also accepted.
+func (p *timeParser) number(s spec, digits, min, max int) (int, error) {
+	p.skipSpace()
+	n, length := 0, 0
+	if s.modifier == 'O' {
+		for i, alt := range p.lt.altDigits {
+			if len(alt) > length && p.hasPrefix(alt) {
+				n, length = i, len(alt)
+			}
+		}
+	}
+	if length > 0 {
+		p.pos += length
+	} else {
+		start := p.pos
+		for p.pos < len(p.value) && p.pos-start < digits && isDigit(p.value[p.pos]) {
+			n = n*10 + int(p.value[p.pos]-'0')
+			p.pos++
+		}
+		if p.pos == start {
+			return 0, p.errorf("expected a number")
+		}
+	}
+	if n < min || n > max {
+		return 0, p.errorf("%d is out of range", n)
+	}
+	return n, nil
+}
+
+// signed reads a number that may start with a sign
+func (p *timeParser) signed(s spec, digits int) (int, error) {
+	p.skipSpace()
+	negative := false
+	if p.pos < len(p.value) && (p.value[p.pos] == '-' || p.value[p.pos] == '+') {
+		negative = p.value[p.pos] == '-'
+		p.pos++
+	}
+	n, err := p.number(s, digits, 0, int(^uint(0)>>1))
+	if negative {
+		n = -n
+	}
+	return n, err
+}
+
+func (p *timeParser) parse(format string) error {
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\v' || c == '\f' || c == '\r' {
+			p.skipSpace()
+			continue
+		}
+		if c != '%' {
+			if p.pos >= len(p.value) || p.value[p.pos] != c {
+				return p.errorf("expected %q", c)
+			}
+			p.pos++
+			continue
+		}
+		s, end, ok := parseSpec(format, i)
+		if !ok {
+			return fmt.Errorf("incomplete conversion specification %q", format[i:])
+		}
+		if err := p.convert(s); err != nil {
+			return err
+		}
+		i = end
+	}
+	return nil
+}
+
+// convert reads one conversion
+func (p *timeParser) convert(s spec) error {
+	if s.modifier == 'E' && !strings.ContainsRune("cCxXyY", rune(s.verb)) ||
+		s.modifier == 'O' && !strings.ContainsRune("deHImMSuUVwWy", rune(s.verb)) {
+		s.modifier = 0
+	}
+	lt := p.lt
+
+	var err error
+	switch s.verb {
+	case 'a', 'A':
+		_, err = p.name("a weekday", lt.day, lt.abday)
+	case 'b', 'B', 'h':
+		p.month, err = p.name("a month", lt.mon, lt.abmon)
+		p.month++
+		p.haveMonthDay = true
+	case 'c':
+		f := orDefault(lt.dtFmt, defaultDTFmt)
+		if s.modifier == 'E' {
+			f = orDefault(lt.eraDTFmt, f)
+		}
+		err = p.parse(f)
+	case 'C':
+		if s.modifier == 'E' && p.era >= 0 {
+			_, err = p.name("the era "+lt.eras[p.era].name, []string{lt.eras[p.era].name})
+		} else if s.modifier == 'E' && len(lt.eras) > 0 {
+			names := make([]string, len(lt.eras))
+			for i, era := range lt.eras {
+				names[i] = era.name
+			}
+			p.era, err = p.name("an era", names)
+		} else {
+			p.century, err = p.signed(s, 2)
+			p.haveCentury = true
+		}
+	case 'd', 'e':
+		p.day, err = p.number(s, 2, 1, 31)
+		p.haveMonthDay = true
+	case 'D':
+		err = p.parse("%m/%d/%y")
+	case 'F':
+		err = p.parse("%Y-%m-%d")
+	case 'g':
+		_, err = p.number(s, 2, 0, 99)
+	case 'G':
+		_, err = p.signed(s, 4)
+	case 'H':
+		p.hour, err = p.number(s, 2, 0, 23)
+	case 'I':
+		p.hour, err = p.number(s, 2, 1, 12)
+		p.hour %= 12
+	case 'j':
+		p.yday, err = p.number(s, 3, 1, 366)
+		p.haveYday = true
+	case 'm':
+		p.month, err = p.number(s, 2, 1, 12)
+		p.haveMonthDay = true
+	case 'M':
+		p.minute, err = p.number(s, 2, 0, 59)
+	case 'n', 't':
+		p.skipSpace()
+	case 'p':
+		var i int
+		i, err = p.name("AM or PM", []string{lt.am, lt.pm})
+		p.pm, p.havePM = i == 1, true
+	case 'r':
+		err = p.parse(orDefault(lt.tFmtAmPm, defaultTFmtAmPm))
+	case 'R':
+		err = p.parse("%H:%M")
+	case 's':
+		var n int
+		n, err = p.signed(s, 20)
+		p.unix, p.haveUnix = int64(n), true
+	case 'S':
+		p.second, err = p.number(s, 2, 0, 60)
+	case 'T':
+		err = p.parse("%H:%M:%S")
+	case 'u':
+		_, err = p.number(s, 1, 1, 7)
+	case 'U', 'W':
+		_, err = p.number(s, 2, 0, 53)
+	case 'V':
+		_, err = p.number(s, 2, 1, 53)
+	case 'w':
+		_, err = p.number(s, 1, 0, 6)
+	case 'x':
+		f := orDefault(lt.dFmt, defaultDFmt)
+		if s.modifier == 'E' {
+			f = orDefault(lt.eraDFmt, f)
+		}
+		err = p.parse(f)
+	case 'X':
+		f := orDefault(lt.tFmt, defaultTFmt)
+		if s.modifier == 'E' {
+			f = orDefault(lt.eraTFmt, f)
+		}
+		err = p.parse(f)
+	case 'y':
+		if s.modifier == 'E' && len(lt.eras) > 0 {
+			p.eraYear, err = p.signed(s, 9)
+			p.haveEraYear = true
+		} else {
+			p.yy, err = p.number(s, 2, 0, 99)
+			p.haveYY = true
+		}
+	case 'Y':
+		if s.modifier == 'E' && len(lt.eras) > 0 {
+			err = p.eraFullYear()
+		} else {
+			p.year, err = p.signed(s, 4)
+			p.haveYear = true
+		}
+	case 'z':
+		err = p.zone()
+	case 'Z':
+		start := p.pos
+		for p.pos < len(p.value) && unicode.IsLetter(rune(p.value[p.pos])) {
+			p.pos++
+		}
+		switch strings.ToUpper(p.value[start:p.pos]) {
+		case "":
+			err = p.errorf("expected a time zone name")
+		case "UTC", "GMT":
+			p.loc = time.UTC
+		}
+	case '%':
+		if p.pos >= len(p.value) || p.value[p.pos] != '%' {
+			err = p.errorf("expected %%")
+		}
+		p.pos++
+	default:
+		err = fmt.Errorf("unknown conversion specification %%%c", s.verb)
+	}
+	return err
+}
+
+// eraFullYear reads %EY by trying the format of each era in turn. Within
+// it, %EC only matches the era being tried, and the year defaults to the
+// era's first, as in a format like "%EC元年".
+func (p *timeParser) eraFullYear() error {
+	var first error
+	for i, era := range p.lt.eras {
+		try := *p
+		try.era, try.eraYear, try.haveEraYear = i, era.offset, true
+		if err := try.parse(orDefault(era.format, "%EC%Ey")); err != nil {
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+		*p = try
+		return nil
+	}
+	return first
+}
+
+// zone reads an offset from UTC, written +hhmm, +hh:mm or +hh, or Z
+func (p *timeParser) zone() error {
+	if p.hasPrefix("Z") {
+		p.pos++
+		p.loc = time.UTC
+		return nil
+	}
+	if p.pos >= len(p.value) || p.value[p.pos] != '+' && p.value[p.pos] != '-' {
+		return p.errorf("expected a time zone offset")
+	}
+	sign := 1
+	if p.value[p.pos] == '-' {
+		sign = -1
+	}
+	p.pos++
+	hours, err := p.number(spec{}, 2, 0, 24)
+	if err != nil {
+		return err
+	}
+	minutes := 0
+	if p.pos < len(p.value) && p.value[p.pos] == ':' {
+		p.pos++
+	}
+	if p.pos < len(p.value) && isDigit(p.value[p.pos]) {
+		if minutes, err = p.number(spec{}, 2, 0, 59); err != nil {
+			return err
+		}
+	}
+	p.loc = time.FixedZone("", sign*(hours*3600+minutes*60))
+	return nil
+}
+
+// time puts the fields together
+func (p *timeParser) time(value string) (time.Time, error) {
+	loc := p.loc
+	if loc == nil {
+		loc = time.UTC
+	}
+	if p.haveUnix {
+		return time.Unix(p.unix, 0).In(loc), nil
+	}
+
+	year := 0
+	switch {
+	case p.haveYear:
+		year = p.year
+	case p.era >= 0 && p.haveEraYear:
+		year = p.lt.eras[p.era].gregorian(p.eraYear)
+	case p.haveYY && p.haveCentury:
+		year = p.century*100 + p.yy
+	case p.haveYY && p.yy < 69:
+		year = 2000 + p.yy
+	case p.haveYY:
+		year = 1900 + p.yy
+	case p.haveCentury:
+		year = p.century * 100
+	}
+
+	hour := p.hour
+	if p.havePM && p.pm {
+		hour = hour%12 + 12
+	}
+
+	if p.haveYday && !p.haveMonthDay {
+		return time.Date(year, 1, p.yday, hour, p.minute, p.second, 0, loc), nil
+	}
+	t := time.Date(year, time.Month(p.month), p.day, hour, p.minute, p.second, 0, loc)
+	if t.Day() != p.day {
+		return time.Time{}, fmt.Errorf("parsing %q: day %d is out of range for the month", value, p.day)
+	}
+	return t, nil
+}
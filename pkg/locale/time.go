@@ -32,10 +32,101 @@ type Time struct {
 type Era struct {
 	reverse   bool
 	offset    int
-	startDate string // String?? Use go date??
+	startDate string
 	endDate   string
 	name      string
 	format    string
+
+	// The dates parsed
+	start, end eraDate
+}
+
+// eraDate is a date in an era definition. An end date may instead be the
+// beginning or end of time, written -* and +*.
+type eraDate struct {
+	year, month, day int
+	infinity         int // -1 for the beginning of time, +1 for the end
+}
+
+// parseEraDate reads a date in the form yyyy/mm/dd, where the year may be
+// negative
+func parseEraDate(s string, open bool) (eraDate, error) {
+	if open && s == "-*" {
+		return eraDate{infinity: -1}, nil
+	}
+	if open && s == "+*" {
+		return eraDate{infinity: 1}, nil
+	}
+	var d eraDate
+	parts := strings.Split(s, "/")
+	if len(parts) == 3 {
+		var errs [3]error
+		d.year, errs[0] = strconv.Atoi(parts[0])
+		d.month, errs[1] = strconv.Atoi(parts[1])
+		d.day, errs[2] = strconv.Atoi(parts[2])
+		if errs[0] == nil && errs[1] == nil && errs[2] == nil &&
+			d.month >= 1 && d.month <= 12 && d.day >= 1 && d.day <= 31 {
+			return d, nil
+		}
+	}
+	return d, fmt.Errorf("date '%s' must be in the form yyyy/mm/dd", s)
+}
+
+// compare returns -1, 0 or 1 as the date is before, on or after the given day
+func (d eraDate) compare(year, month, day int) int {
+	if d.infinity != 0 {
+		return d.infinity
+	}
+	for _, diff := range []int{d.year - year, d.month - month, d.day - day} {
+		if diff < 0 {
+			return -1
+		} else if diff > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// parseDates fills in the parsed forms of the era's dates
+func (e *Era) parseDates() error {
+	var err error
+	if e.start, err = parseEraDate(e.startDate, false); err != nil {
+		return err
+	}
+	e.end, err = parseEraDate(e.endDate, true)
+	return err
+}
+
+// contains reports whether the day falls in the era, which runs from its
+// start date to its end date in either direction
+func (e Era) contains(year, month, day int) bool {
+	a, b := e.start.compare(year, month, day), e.end.compare(year, month, day)
+	return a*b <= 0
+}
+
+// year returns the number of a Gregorian year within the era
+func (e Era) year(gregorian int) int {
+	diff := gregorian - e.start.year
+	if diff < 0 {
+		diff = -diff
+	}
+	if e.reverse {
+		return e.offset - diff
+	}
+	return e.offset + diff
+}
+
+// gregorian is the inverse of year. The era's end date decides which way
+// from the start date the years are counted.
+func (e Era) gregorian(year int) int {
+	diff := year - e.offset
+	if e.reverse {
+		diff = -diff
+	}
+	if e.end.compare(e.start.year, e.start.month, e.start.day) < 0 {
+		return e.start.year - diff
+	}
+	return e.start.year + diff
 }
 
 // NewEra creates an era from an input string, as specified in Chapter 7 of the Base Definitions
@@ -65,14 +156,16 @@ func NewEra(input string) (Era, error) {
 	// Offset
 	offset, err := strconv.Atoi(parts[1])
 	if err != nil {
-		return Era{}, nil
+		return Era{}, fmt.Errorf("offset '%s' must be an integer", parts[1])
 	}
 	era.offset = offset
 
 	// Dates
-	// TODO: Check formatting?
 	era.startDate = parts[2]
 	era.endDate = parts[3]
+	if err := era.parseDates(); err != nil {
+		return Era{}, err
+	}
 
 	// Name
 	era.name = parts[4]
@@ -82,3 +175,12 @@ func NewEra(input string) (Era, error) {
 
 	return era, nil
 }
+
+// String returns the era in the form NewEra reads
+func (e Era) String() string {
+	direction := "+"
+	if e.reverse {
+		direction = "-"
+	}
+	return strings.Join([]string{direction, strconv.Itoa(e.offset), e.startDate, e.endDate, e.name, e.format}, ":")
+}
@@ -0,0 +1,130 @@
+package locale
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Validate checks the definition against the constraints in chapter 7 of the
+// Base Definitions that the parser can't enforce on its own. Each problem is
+// returned as a separate error; a definition with problems may still be used.
+func (def Def) Validate() []error {
+	var errs []error
+	warn := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	c := def.Ctype
+	if len(c.digit) > 0 && encodedChars(c.digit) != "0123456789" {
+		warn("LC_CTYPE: digit must be the digits 0 to 9, in order")
+	}
+	if both := overlap(c.upper, c.lower); len(both) > 0 {
+		warn("LC_CTYPE: %q are both upper and lower", both)
+	}
+	alnum := append(append(append([]Character(nil), c.upper...), c.lower...), c.alpha...)
+	alnum = append(alnum, c.digit...)
+	for _, class := range []struct {
+		name  string
+		chars []Character
+	}{{"punct", c.punct}, {"space", c.space}, {"cntrl", c.cntrl}} {
+		if both := overlap(class.chars, alnum); len(both) > 0 {
+			warn("LC_CTYPE: %q are in both %s and alnum", both, class.name)
+		}
+	}
+	if both := overlap(c.digit, c.alpha); len(both) > 0 {
+		warn("LC_CTYPE: %q are in both digit and alpha", both)
+	}
+	for _, conv := range []struct {
+		name string
+		m    map[Character]Character
+	}{{"toupper", c.toupper}, {"tolower", c.tolower}} {
+		for _, from := range sortedKeys(conv.m) {
+			to := conv.m[from]
+			if len(from.Bytes) > c.mbCurMax || len(to.Bytes) > c.mbCurMax {
+				warn("LC_CTYPE: %s maps %q to %q, which is longer than mb_cur_max", conv.name, from.Bytes, to.Bytes)
+			}
+		}
+	}
+
+	co := def.Collate
+	defined := map[string]bool{"IGNORE": true, "UNDEFINED": true}
+	seen := make(map[string]bool)
+	for _, o := range co.order {
+		if seen[o.id] && o.id != "..." {
+			warn("LC_COLLATE: %q appears more than once in the collation order", o.id)
+		}
+		seen[o.id] = true
+		defined[o.id] = true
+	}
+	for _, o := range co.order {
+		for _, w := range o.weights {
+			for _, id := range w {
+				if !defined[id] {
+					warn("LC_COLLATE: the weight %q of %q is not in the collation order", id, o.id)
+				}
+			}
+		}
+	}
+
+	m := def.Monetary
+	for _, v := range []struct {
+		name  string
+		value int
+		max   int
+	}{
+		{"p_cs_precedes", m.pCsPrecedes, 1},
+		{"n_cs_precedes", m.nCsPrecedes, 1},
+		{"int_p_cs_precedes", m.intPcsPrecedes, 1},
+		{"int_n_cs_precedes", m.intNcsPrecedes, 1},
+		{"p_sep_by_space", m.pSepBySpace, 2},
+		{"n_sep_by_space", m.nSepBySpace, 2},
+		{"int_p_sep_by_space", m.intPsepBySpace, 2},
+		{"int_n_sep_by_space", m.intNsepBySpace, 2},
+		{"p_sign_posn", m.pSignPosn, 4},
+		{"n_sign_posn", m.nSignPosn, 4},
+		{"int_p_sign_posn", m.intPsignPosn, 4},
+		{"int_n_sign_posn", m.intNsignPosn, 4},
+	} {
+		if v.value < -1 || v.value > v.max {
+			warn("LC_MONETARY: %s must be between 0 and %d, or -1", v.name, v.max)
+		}
+	}
+	if m.intCurrSymbol != "" && len(m.intCurrSymbol) != 4 {
+		warn("LC_MONETARY: int_curr_symbol must be four characters long")
+	}
+	for _, g := range [][]int{m.monGrouping, def.Numeric.grouping} {
+		for _, n := range g {
+			if n < 0 {
+				warn("grouping sizes must not be negative, except for a single -1")
+				break
+			}
+		}
+	}
+
+	return errs
+}
+
+func encodedChars(chars []Character) string {
+	var out []byte
+	for _, c := range chars {
+		out = append(out, c.Bytes...)
+	}
+	return string(out)
+}
+
+// overlap returns the encodings of characters in both a and b, sorted
+func overlap(a, b []Character) []string {
+	in := make(map[string]bool, len(a))
+	for _, c := range a {
+		in[c.Bytes] = true
+	}
+	var both []string
+	for _, c := range b {
+		if in[c.Bytes] {
+			both = append(both, c.Bytes)
+			delete(in, c.Bytes)
+		}
+	}
+	sort.Strings(both)
+	return both
+}
@@ -0,0 +1,25 @@
+package locale
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestValidateCaseOrder(t *testing.T) {
+	var def Def
+	def.Ctype.mbCurMax = 1
+	def.Ctype.toupper = make(map[Character]Character)
+	for _, r := range "zyxwvutsrq" {
+		def.Ctype.toupper[Character{string(r), r}] = Character{string(r - 'a' + 'A'), r - 'a' + 'A'}
+	}
+	def.Ctype.toupper[Character{"é", 'é'}] = Character{"É", 'É'}
+	def.Ctype.toupper[Character{"ß", 'ß'}] = Character{"ẞ", 'ẞ'}
+
+	// The warnings come in the order of the characters' encodings, every time
+	want := `[LC_CTYPE: toupper maps "ß" to "ẞ", which is longer than mb_cur_max LC_CTYPE: toupper maps "é" to "É", which is longer than mb_cur_max]`
+	for i := 0; i < 20; i++ {
+		if got := fmt.Sprint(def.Validate()); got != want {
+			t.Fatalf("got %s, wanted %s", got, want)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Package nls reads message catalogs, which hold the messages a utility
+// shows in each language, in the way catopen and catgets do
+package nls
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+)
+
+// Limits on set and message numbers, which start at 1
+const (
+	SetMax = 32767 // NL_SETMAX
+	MsgMax = 32767 // NL_MSGMAX
+)
+
+// SetDefault is the set of messages that come before any $set in a source
+// file (NL_SETD)
+const SetDefault = 1
+
+// Catalog holds messages, numbered within numbered sets
+type Catalog struct {
+	sets map[int]map[int]string
+}
+
+// Get returns a message, or def if the catalog doesn't have it. A nil
+// catalog has no messages, so a utility can go on with its own when Open
+// fails.
+func (c *Catalog) Get(set, msg int, def string) string {
+	if c == nil {
+		return def
+	}
+	if text, ok := c.sets[set][msg]; ok {
+		return text
+	}
+	return def
+}
+
+// Set adds a message, replacing any with the same numbers
+func (c *Catalog) Set(set, msg int, text string) {
+	c.set(set)[msg] = text
+}
+
+// set returns the messages of a set, adding it if it's new
+func (c *Catalog) set(set int) map[int]string {
+	if c.sets == nil {
+		c.sets = make(map[int]map[int]string)
+	}
+	if c.sets[set] == nil {
+		c.sets[set] = make(map[int]string)
+	}
+	return c.sets[set]
+}
+
+// Delete removes a message
+func (c *Catalog) Delete(set, msg int) {
+	delete(c.sets[set], msg)
+}
+
+// DeleteSet removes a set and all its messages
+func (c *Catalog) DeleteSet(set int) {
+	delete(c.sets, set)
+}
+
+// The compiled form of a catalog starts with a magic string and a version,
+// followed by the sets in order. Numbers are written as varints, and strings
+// are preceded by their length.
+const (
+	binaryMagic   = "PUMC"
+	binaryVersion = 1
+)
+
+var errTruncated = errors.New("message catalog is truncated")
+
+func sortedKeys(m map[int]map[int]string) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// MarshalBinary encodes the catalog in the form written by gencat
+func (c *Catalog) MarshalBinary() ([]byte, error) {
+	buf := []byte(binaryMagic)
+	var b [binary.MaxVarintLen64]byte
+	put := func(n int) {
+		buf = append(buf, b[:binary.PutUvarint(b[:], uint64(n))]...)
+	}
+	put(binaryVersion)
+	put(len(c.sets))
+	for _, set := range sortedKeys(c.sets) {
+		msgs := c.sets[set]
+		numbers := make([]int, 0, len(msgs))
+		for msg := range msgs {
+			numbers = append(numbers, msg)
+		}
+		sort.Ints(numbers)
+		put(set)
+		put(len(numbers))
+		for _, msg := range numbers {
+			put(msg)
+			put(len(msgs[msg]))
+			buf = append(buf, msgs[msg]...)
+		}
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a catalog written by MarshalBinary
+func (c *Catalog) UnmarshalBinary(data []byte) error {
+	if len(data) < len(binaryMagic) || string(data[:len(binaryMagic)]) != binaryMagic {
+		return errors.New("not a message catalog")
+	}
+	data = data[len(binaryMagic):]
+	var err error
+	get := func() int {
+		n, size := binary.Uvarint(data)
+		if size <= 0 && err == nil {
+			err = errTruncated
+		}
+		if err != nil {
+			return 0
+		}
+		data = data[size:]
+		return int(n)
+	}
+
+	if v := get(); v != binaryVersion && err == nil {
+		return fmt.Errorf("unsupported message catalog version %d", v)
+	}
+	var out Catalog
+	for sets := get(); sets > 0 && err == nil; sets-- {
+		set := out.set(get())
+		for msgs := get(); msgs > 0 && err == nil; msgs-- {
+			msg, n := get(), get()
+			if err == nil && n > len(data) {
+				err = errTruncated
+			}
+			if err != nil {
+				break
+			}
+			set[msg] = string(data[:n])
+			data = data[n:]
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if len(data) != 0 {
+		return errors.New("trailing data after message catalog")
+	}
+	*c = out
+	return nil
+}
+
+// Load reads a catalog written by gencat
+func Load(path string) (*Catalog, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Catalog{}
+	if err := c.UnmarshalBinary(data); err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	return c, nil
+}
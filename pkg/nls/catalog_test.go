@@ -0,0 +1,105 @@
+package nls
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	var c Catalog
+	if err := c.ReadSource(strings.NewReader(source)); err != nil {
+		t.Fatal(err)
+	}
+	for _, cat := range []Catalog{c, {}} {
+		data, err := cat.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Catalog
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if len(cat.sets) > 0 && !reflect.DeepEqual(got, cat) {
+			t.Errorf("round trip gave %+v, expected %+v", got, cat)
+		}
+		for n := range data[:len(data)-1] {
+			if err := got.UnmarshalBinary(data[:n]); err == nil {
+				t.Errorf("no error for a catalog truncated to %d bytes", n)
+			}
+		}
+		if err := got.UnmarshalBinary(append(data, 0)); err == nil {
+			t.Errorf("no error for trailing data")
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, v := range []string{"NLSPATH", "LANG", "LC_ALL", "LC_MESSAGES"} {
+		if old, ok := os.LookupEnv(v); ok {
+			defer os.Setenv(v, old)
+		} else {
+			defer os.Unsetenv(v)
+		}
+		os.Unsetenv(v)
+	}
+
+	var c Catalog
+	c.Set(SetDefault, 1, "Bonjour")
+	data, _ := c.MarshalBinary()
+	os.MkdirAll(filepath.Join(dir, "fr"), 0755)
+	if err := ioutil.WriteFile(filepath.Join(dir, "fr", "hello.cat"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("NLSPATH", filepath.Join(dir, "%L", "%N.cat")+":"+filepath.Join(dir, "%l", "%N.cat"))
+	os.Setenv("LANG", "fr_FR.UTF-8@euro")
+	got, err := Open("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg := got.Get(SetDefault, 1, "Hello"); msg != "Bonjour" {
+		t.Errorf("got %q", msg)
+	}
+
+	os.Setenv("LC_MESSAGES", "de_DE")
+	got, err = Open("hello")
+	if err == nil {
+		t.Errorf("found a catalog for de_DE")
+	}
+	if msg := got.Get(SetDefault, 1, "Hello"); msg != "Hello" {
+		t.Errorf("a nil catalog gave %q", msg)
+	}
+
+	if got, err := Open(filepath.Join(dir, "fr", "hello.cat")); err != nil || got.Get(1, 1, "") != "Bonjour" {
+		t.Errorf("opening a path: %v", err)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	got := expand("/a/%L/%l/%t/%c/%N/%%/%x%", "cat", "fr_CA.UTF-8@euro")
+	if want := "/a/fr_CA.UTF-8@euro/fr/CA/UTF-8/cat/%/%x%"; got != want {
+		t.Errorf("expanded to %q, expected %q", got, want)
+	}
+}
+
+func TestAnswers(t *testing.T) {
+	for response, want := range map[string][2]bool{
+		"y":     {true, false},
+		"Yes":   {true, false},
+		"no":    {false, true},
+		"maybe": {false, false},
+	} {
+		if Affirmative(response) != want[0] || Negative(response) != want[1] {
+			t.Errorf("%q: affirmative %t, negative %t", response, Affirmative(response), Negative(response))
+		}
+	}
+}
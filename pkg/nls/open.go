@@ -0,0 +1,124 @@
+package nls
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/fwip/posix-utils/pkg/locale"
+)
+
+// DefaultNLSPath is searched for catalogs when NLSPATH isn't set
+const DefaultNLSPath = "/usr/share/posix-utils/nls/%L/%N:/usr/share/posix-utils/nls/%l/%N"
+
+// Open finds and loads the catalog called name, for the language of
+// LC_MESSAGES. A name containing a slash is a pathname. Otherwise each
+// template in NLSPATH is tried in turn, with these substitutions:
+//
+//	%N  the name
+//	%L  the locale of LC_MESSAGES
+//	%l  its language
+//	%t  its territory
+//	%c  its codeset
+//	%%  a single %
+func Open(name string) (*Catalog, error) {
+	if strings.Contains(name, "/") {
+		return Load(name)
+	}
+	nlspath := os.Getenv("NLSPATH")
+	if nlspath == "" {
+		nlspath = DefaultNLSPath
+	}
+	loc := locale.FromEnv().Name("LC_MESSAGES")
+	for _, template := range filepath.SplitList(nlspath) {
+		if template == "" {
+			continue
+		}
+		path := expand(template, name, loc)
+		if fi, err := os.Stat(path); err == nil && fi.Mode().IsRegular() {
+			return Load(path)
+		}
+	}
+	return nil, fmt.Errorf("message catalog %s not found", name)
+}
+
+// expand substitutes the name and parts of the locale name, which has the
+// form language[_territory][.codeset][@modifier], into a template
+func expand(template, name, loc string) string {
+	lang, territory, codeset := loc, "", ""
+	if i := strings.IndexByte(lang, '@'); i >= 0 {
+		lang = lang[:i]
+	}
+	if i := strings.IndexByte(lang, '.'); i >= 0 {
+		lang, codeset = lang[:i], lang[i+1:]
+	}
+	if i := strings.IndexByte(lang, '_'); i >= 0 {
+		lang, territory = lang[:i], lang[i+1:]
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(template); i++ {
+		if template[i] != '%' || i+1 == len(template) {
+			out.WriteByte(template[i])
+			continue
+		}
+		i++
+		switch template[i] {
+		case 'N':
+			out.WriteString(name)
+		case 'L':
+			out.WriteString(loc)
+		case 'l':
+			out.WriteString(lang)
+		case 't':
+			out.WriteString(territory)
+		case 'c':
+			out.WriteString(codeset)
+		case '%':
+			out.WriteByte('%')
+		default:
+			out.WriteByte('%')
+			out.WriteByte(template[i])
+		}
+	}
+	return out.String()
+}
+
+var (
+	answersOnce sync.Once
+	yes, no     *regexp.Regexp
+)
+
+// answers compiles the yesexpr and noexpr of the current locale. An
+// expression that doesn't compile is replaced by the POSIX locale's.
+func answers() (*regexp.Regexp, *regexp.Regexp) {
+	answersOnce.Do(func() {
+		compile := func(expr, fallback string) *regexp.Regexp {
+			if re, err := regexp.CompilePOSIX(expr); err == nil && expr != "" {
+				return re
+			}
+			return regexp.MustCompilePOSIX(fallback)
+		}
+		m, posix := locale.Current().Messages, locale.POSIX().Messages
+		yes = compile(m.YesExpr(), posix.YesExpr())
+		no = compile(m.NoExpr(), posix.NoExpr())
+	})
+	return yes, no
+}
+
+// Affirmative reports whether a response to a yes-or-no question matches
+// the yesexpr of the current locale
+func Affirmative(response string) bool {
+	yes, _ := answers()
+	return yes.MatchString(response)
+}
+
+// Negative reports whether a response to a yes-or-no question matches the
+// noexpr of the current locale
+func Negative(response string) bool {
+	_, no := answers()
+	return no.MatchString(response)
+}
@@ -0,0 +1,213 @@
+package nls
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// SourceError reports a problem on a line of a message source file
+type SourceError struct {
+	Line int
+	Msg  string
+}
+
+func (e *SourceError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// sourceReader holds the state of a message source file as it is read
+type sourceReader struct {
+	lines *bufio.Scanner
+	line  int
+	set   int
+	quote rune // The quote character, or 0 if there is none
+}
+
+func (s *sourceReader) errorf(format string, args ...interface{}) error {
+	return &SourceError{s.line, fmt.Sprintf(format, args...)}
+}
+
+func (s *sourceReader) next() (string, bool) {
+	if !s.lines.Scan() {
+		return "", false
+	}
+	s.line++
+	return s.lines.Text(), true
+}
+
+// ReadSource adds the messages of a source file, in the format read by
+// gencat, to the catalog. Messages replace any with the same numbers.
+func (c *Catalog) ReadSource(r io.Reader) error {
+	s := &sourceReader{lines: bufio.NewScanner(r), set: SetDefault}
+	s.lines.Buffer(nil, 1<<20)
+	for {
+		line, ok := s.next()
+		if !ok {
+			break
+		}
+		if err := c.readLine(s, line); err != nil {
+			return err
+		}
+	}
+	if err := s.lines.Err(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// number reads a set or message number at the start of text, returning it
+// and the rest of the text
+func (s *sourceReader) number(text, what string, max int) (int, string, error) {
+	end := 0
+	for end < len(text) && text[end] >= '0' && text[end] <= '9' {
+		end++
+	}
+	n, err := strconv.Atoi(text[:end])
+	if err != nil || n < 1 || n > max {
+		return 0, "", s.errorf("%s number must be between 1 and %d", what, max)
+	}
+	return n, text[end:], nil
+}
+
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+func (c *Catalog) readLine(s *sourceReader, line string) error {
+	if line == "" {
+		return nil
+	}
+	if line[0] == '$' {
+		return c.directive(s, line[1:])
+	}
+
+	msg, rest, err := s.number(line, "message", MsgMax)
+	if err != nil {
+		return err
+	}
+	if rest == "" {
+		c.Delete(s.set, msg)
+		return nil
+	}
+	if !isBlank(rest[0]) {
+		return s.errorf("a message number must be followed by a space or tab")
+	}
+	text, err := s.text(rest[1:])
+	if err != nil {
+		return err
+	}
+	c.Set(s.set, msg, text)
+	return nil
+}
+
+// directive handles a line starting with $: either a comment, or one of
+// $set, $delset and $quote
+func (c *Catalog) directive(s *sourceReader, line string) error {
+	if line == "" || isBlank(line[0]) {
+		return nil
+	}
+	name, arg := line, ""
+	if i := strings.IndexAny(line, " \t"); i >= 0 {
+		name, arg = line[:i], strings.TrimLeft(line[i:], " \t")
+	}
+	switch name {
+	case "set", "delset":
+		n, rest, err := s.number(arg, "set", SetMax)
+		if err != nil {
+			return err
+		}
+		if rest != "" && !isBlank(rest[0]) {
+			return s.errorf("a set number must be followed by a space or tab")
+		}
+		if name == "set" {
+			s.set = n
+			c.set(n)
+		} else {
+			c.DeleteSet(n)
+		}
+	case "quote":
+		s.quote = 0
+		for _, r := range arg {
+			s.quote = r
+			break
+		}
+	default:
+		return s.errorf("unknown directive $%s", name)
+	}
+	return nil
+}
+
+// text decodes the text of a message, which may be quoted, may contain
+// escape sequences, and continues on the next line after a backslash at the
+// end of a line
+func (s *sourceReader) text(line string) (string, error) {
+	var out strings.Builder
+	quoted := false
+	if s.quote != 0 && strings.HasPrefix(line, string(s.quote)) {
+		quoted = true
+		line = line[len(string(s.quote)):]
+	}
+
+	for {
+		continued := false
+		for i := 0; i < len(line); {
+			if quoted && strings.HasPrefix(line[i:], string(s.quote)) {
+				return out.String(), nil
+			}
+			c := line[i]
+			i++
+			if c != '\\' {
+				out.WriteByte(c)
+				continue
+			}
+			if i == len(line) {
+				continued = true
+				break
+			}
+			c = line[i]
+			i++
+			switch c {
+			case 'n':
+				out.WriteByte('\n')
+			case 't':
+				out.WriteByte('\t')
+			case 'v':
+				out.WriteByte('\v')
+			case 'b':
+				out.WriteByte('\b')
+			case 'r':
+				out.WriteByte('\r')
+			case 'f':
+				out.WriteByte('\f')
+			case '0', '1', '2', '3', '4', '5', '6', '7':
+				n := int(c - '0')
+				for j := 0; j < 2 && i < len(line) && line[i] >= '0' && line[i] <= '7'; j++ {
+					n = n*8 + int(line[i]-'0')
+					i++
+				}
+				if n > 0xff {
+					return "", s.errorf("octal escape \\%o is too large for a byte", n)
+				}
+				out.WriteByte(byte(n))
+			default:
+				// Backslashes and quotes stand for themselves, as does
+				// anything else that's escaped
+				out.WriteByte(c)
+			}
+		}
+		if !continued {
+			if quoted {
+				return "", s.errorf("missing closing %c", s.quote)
+			}
+			return out.String(), nil
+		}
+		next, ok := s.next()
+		if !ok {
+			return "", s.errorf("a message continues past the end of the file")
+		}
+		line = next
+	}
+}
@@ -0,0 +1,83 @@
+package nls
+
+import (
+	"strings"
+	"testing"
+)
+
+const source = `$ A comment
+1 Default set
+$set 2 Errors
+1 cannot open %s\n
+2 tab\there, octal \101\1022
+3 continued \
+on the next line
+4 ` + "\n" + `5 gone
+5
+$quote "
+6 "  quoted  " and then a comment
+7 "escaped \" quote"
+8 "a quoted \
+continuation"
+$quote
+9 "not quoted"
+$set 3
+1 doomed
+$delset 3 Gone again
+$set 4
+1 back\\slash
+`
+
+func TestReadSource(t *testing.T) {
+	var c Catalog
+	if err := c.ReadSource(strings.NewReader(source)); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		set, msg int
+		want     string
+	}{
+		{1, 1, "Default set"},
+		{2, 1, "cannot open %s\n"},
+		{2, 2, "tab\there, octal AB2"},
+		{2, 3, "continued on the next line"},
+		{2, 4, ""},
+		{2, 5, "default"},
+		{2, 6, "  quoted  "},
+		{2, 7, `escaped " quote`},
+		{2, 8, "a quoted continuation"},
+		{2, 9, `"not quoted"`},
+		{3, 1, "default"},
+		{4, 1, `back\slash`},
+	}
+	for _, test := range tests {
+		if got := c.Get(test.set, test.msg, "default"); got != test.want {
+			t.Errorf("message %d of set %d is %q, expected %q", test.msg, test.set, got, test.want)
+		}
+	}
+}
+
+func TestReadSourceErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		line   int
+	}{
+		{"1 ok\nx bad\n", 2},
+		{"0 zero\n", 1},
+		{"1x\n", 1},
+		{"$set\n", 1},
+		{"$set 40000\n", 1},
+		{"$set 2x\n", 1},
+		{"\n$nonsense 1\n", 2},
+		{"$quote \"\n1 \"unclosed\n", 2},
+		{"1 continues \\\n", 1},
+		{"1 \\777\n", 1},
+	}
+	for _, test := range tests {
+		var c Catalog
+		err := c.ReadSource(strings.NewReader(test.source))
+		if e, ok := err.(*SourceError); !ok || e.Line != test.line {
+			t.Errorf("%q: expected an error on line %d, got %v", test.source, test.line, err)
+		}
+	}
+}
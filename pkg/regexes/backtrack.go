@@ -0,0 +1,182 @@
+package regexes
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// matcher tries every way of matching an expression at one position, to
+// find the longest. It's only used for expressions that the regexp package
+// can't take.
+type matcher struct {
+	re   *Regexp
+	s    string
+	sets map[*charSet]*regexp.Regexp
+
+	best []int // The longest match so far, or nil
+}
+
+// cont is called with where a match of part of the expression ended. It
+// returns true to stop looking for longer matches.
+type cont func(pos int, caps []int) bool
+
+// backtrack returns the leftmost-longest match in s starting at or after from
+func (re *Regexp) backtrack(s string, from int) []int {
+	m := &matcher{re: re, s: s, sets: make(map[*charSet]*regexp.Regexp)}
+	for start := from; start <= len(s); start += runeLen(s[start:]) {
+		caps := make([]int, 2*(re.numSub+1))
+		for i := range caps {
+			caps[i] = -1
+		}
+		m.match(re.tree, start, caps, func(pos int, caps []int) bool {
+			if m.best == nil || pos > m.best[1] {
+				m.best = append([]int(nil), caps...)
+				m.best[0], m.best[1] = start, pos
+			}
+			// Nothing can be longer than the rest of s
+			return pos == len(s)
+		})
+		if m.best != nil {
+			return m.best
+		}
+		if start == len(s) {
+			break
+		}
+	}
+	return nil
+}
+
+func runeLen(s string) int {
+	if s == "" {
+		return 1
+	}
+	_, size := utf8.DecodeRuneInString(s)
+	return size
+}
+
+// match matches n at pos, and calls k with each place it could end
+func (m *matcher) match(n *node, pos int, caps []int, k cont) bool {
+	s := m.s
+	switch n.op {
+	case opLiteral, opAny, opSet:
+		if pos >= len(s) {
+			return false
+		}
+		r, size := utf8.DecodeRuneInString(s[pos:])
+		if !m.matchRune(n, r) {
+			return false
+		}
+		return k(pos+size, caps)
+	case opBOL:
+		return pos == 0 && k(pos, caps)
+	case opEOL:
+		return pos == len(s) && k(pos, caps)
+	case opConcat:
+		return m.concat(n.subs, pos, caps, k)
+	case opAlternate:
+		for _, sub := range n.subs {
+			if m.match(sub, pos, caps, k) {
+				return true
+			}
+		}
+		return false
+	case opGroup:
+		return m.match(n.subs[0], pos, caps, func(end int, c []int) bool {
+			c = append([]int(nil), c...)
+			c[2*n.n], c[2*n.n+1] = pos, end
+			return k(end, c)
+		})
+	case opRepeat:
+		return m.repeat(n, 0, pos, caps, k, make(map[repeatState]bool))
+	case opBackref:
+		start, end := caps[2*n.n], caps[2*n.n+1]
+		if start < 0 {
+			return false
+		}
+		sub := s[start:end]
+		if pos+len(sub) > len(s) {
+			return false
+		}
+		if got := s[pos : pos+len(sub)]; got != sub && !(m.re.foldCase && strings.EqualFold(got, sub)) {
+			return false
+		}
+		return k(pos+len(sub), caps)
+	}
+	return false
+}
+
+func (m *matcher) concat(subs []*node, pos int, caps []int, k cont) bool {
+	if len(subs) == 0 {
+		return k(pos, caps)
+	}
+	return m.match(subs[0], pos, caps, func(end int, c []int) bool {
+		return m.concat(subs[1:], end, c, k)
+	})
+}
+
+// repeatState is where a repetition has got to. Repetitions that reach the
+// same state continue the same way, so only the first needs to carry on.
+type repeatState struct {
+	count int
+	pos   int
+	caps  string
+}
+
+// repeat matches further repetitions of n, having matched count already.
+// seen holds the states already tried with k, which without it can be
+// exponentially many, as in \(a*\)*b.
+func (m *matcher) repeat(n *node, count, pos int, caps []int, k cont, seen map[repeatState]bool) bool {
+	// Past the minimum, the count only matters if there's a maximum
+	state := repeatState{count, pos, capsKey(caps)}
+	if n.max < 0 && count > n.min {
+		state.count = n.min
+	}
+	if seen[state] {
+		return false
+	}
+	seen[state] = true
+
+	if n.max < 0 || count < n.max {
+		// An empty repetition may still set a subexpression, for a
+		// back-reference to match. Once it doesn't, the state is seen.
+		stop := m.match(n.subs[0], pos, caps, func(end int, c []int) bool {
+			return m.repeat(n, count+1, end, c, k, seen)
+		})
+		if stop {
+			return true
+		}
+	}
+	return count >= n.min && k(pos, caps)
+}
+
+// capsKey packs caps into a string, to be part of a map key
+func capsKey(caps []int) string {
+	b := make([]byte, 0, 4*len(caps))
+	for _, c := range caps {
+		b = strconv.AppendInt(b, int64(c), 36)
+		b = append(b, ',')
+	}
+	return string(b)
+}
+
+// matchRune reports whether a character matches a single-character node
+func (m *matcher) matchRune(n *node, r rune) bool {
+	switch n.op {
+	case opAny:
+		return true
+	case opLiteral:
+		return r == n.r || (m.re.foldCase && strings.EqualFold(string(r), string(n.r)))
+	}
+	re, ok := m.sets[n.set]
+	if !ok {
+		flags := "(?s)"
+		if m.re.foldCase {
+			flags = "(?si)"
+		}
+		re = regexp.MustCompile(flags + "^" + n.set.syntax() + "$")
+		m.sets[n.set] = re
+	}
+	return re.MatchString(string(r))
+}
@@ -0,0 +1,412 @@
+package regexes
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+// DupMax is the largest count an interval may give, RE_DUP_MAX
+const DupMax = 255
+
+// Error reports a pattern that isn't a valid regular expression
+type Error struct {
+	Expr string
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Expr, e.Msg)
+}
+
+type opcode uint8
+
+const (
+	opLiteral   opcode = iota // r
+	opAny                     // Any character
+	opSet                     // A bracket expression
+	opBOL                     // ^
+	opEOL                     // $
+	opConcat                  // subs, in order
+	opAlternate               // Any of subs
+	opGroup                   // A subexpression, numbered n, of subs[0]
+	opRepeat                  // subs[0], from min to max times; max is -1 for no limit
+	opBackref                 // The text matched by subexpression n
+)
+
+// node is a parsed regular expression
+type node struct {
+	op       opcode
+	r        rune
+	set      *charSet
+	subs     []*node
+	min, max int
+	n        int
+}
+
+// charSet is a bracket expression
+type charSet struct {
+	negate  bool
+	runes   []rune
+	ranges  [][2]rune
+	classes []string
+}
+
+var classes = map[string]bool{
+	"alnum": true, "alpha": true, "blank": true, "cntrl": true, "digit": true, "graph": true,
+	"lower": true, "print": true, "punct": true, "space": true, "upper": true, "xdigit": true,
+}
+
+// parser holds the state of parsing one expression
+type parser struct {
+	expr     string
+	pos      int
+	extended bool
+	groups   int // Subexpressions opened so far
+	closed   map[int]bool
+	backrefs bool
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &Error{p.expr, fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) more() bool {
+	return p.pos < len(p.expr)
+}
+
+func (p *parser) peek() rune {
+	r, _ := utf8.DecodeRuneInString(p.expr[p.pos:])
+	return r
+}
+
+func (p *parser) next() rune {
+	r, size := utf8.DecodeRuneInString(p.expr[p.pos:])
+	p.pos += size
+	return r
+}
+
+// lookingAt reports whether the rest of the expression starts with s
+func (p *parser) lookingAt(s string) bool {
+	return len(p.expr)-p.pos >= len(s) && p.expr[p.pos:p.pos+len(s)] == s
+}
+
+// parse parses a whole basic or extended regular expression
+func parse(expr string, extended bool) (*node, *parser, error) {
+	p := &parser{expr: expr, extended: extended, closed: make(map[int]bool)}
+	n, err := p.alternation(0)
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.more() {
+		// Only an unmatched close can stop the parse early
+		return nil, nil, p.errorf("unmatched )")
+	}
+	return n, p, nil
+}
+
+// alternation parses branches separated by | in an ERE, until the end of
+// the expression or the close of the group at depth
+func (p *parser) alternation(depth int) (*node, error) {
+	var branches []*node
+	for {
+		b, err := p.branch(depth)
+		if err != nil {
+			return nil, err
+		}
+		branches = append(branches, b)
+		if !p.extended || !p.lookingAt("|") {
+			break
+		}
+		p.pos++
+	}
+	if len(branches) == 1 {
+		return branches[0], nil
+	}
+	return &node{op: opAlternate, subs: branches}, nil
+}
+
+// atGroupEnd reports whether the parser is at the close of a group
+func (p *parser) atGroupEnd(depth int) bool {
+	if depth == 0 {
+		return false
+	}
+	if p.extended {
+		return p.lookingAt(")")
+	}
+	return p.lookingAt(`\)`)
+}
+
+// branch parses a sequence of repeated atoms
+func (p *parser) branch(depth int) (*node, error) {
+	cat := &node{op: opConcat}
+	start := true // At the start of the expression or subexpression
+	for p.more() && !p.atGroupEnd(depth) && !(p.extended && p.lookingAt("|")) {
+		if p.extended && depth == 0 && p.lookingAt(")") {
+			// An unmatched ) is an error in an ERE
+			return nil, p.errorf("unmatched )")
+		}
+		atom, err := p.atom(start, depth)
+		if err != nil {
+			return nil, err
+		}
+		// In a BRE, a * after a leading ^ is itself
+		start = atom.op == opBOL && !p.extended
+		if !start {
+			if atom, err = p.repeats(atom); err != nil {
+				return nil, err
+			}
+		}
+		cat.subs = append(cat.subs, atom)
+	}
+	if len(cat.subs) == 1 {
+		return cat.subs[0], nil
+	}
+	return cat, nil
+}
+
+// atom parses a single character, bracket expression, anchor, group or
+// back-reference. start says whether the atom begins an expression, where
+// a BRE treats ^ as an anchor and * as itself.
+func (p *parser) atom(start bool, depth int) (*node, error) {
+	c := p.next()
+	switch c {
+	case '.':
+		return &node{op: opAny}, nil
+	case '[':
+		set, err := p.bracket()
+		if err != nil {
+			return nil, err
+		}
+		return &node{op: opSet, set: set}, nil
+	case '^':
+		if p.extended || start {
+			return &node{op: opBOL}, nil
+		}
+	case '$':
+		// In a BRE, $ is an anchor only at the end of the expression or
+		// subexpression
+		if p.extended || !p.more() || p.atGroupEnd(depth) {
+			return &node{op: opEOL}, nil
+		}
+	case '*':
+		if p.extended && !start {
+			return nil, p.errorf("repetition-operator operand invalid")
+		}
+	case '+', '?':
+		if p.extended {
+			return nil, p.errorf("repetition-operator operand invalid")
+		}
+	case '{':
+		if p.extended && p.more() && isDigit(p.peek()) {
+			return nil, p.errorf("repetition-operator operand invalid")
+		}
+	case '(':
+		if p.extended {
+			return p.group(depth)
+		}
+	case '\\':
+		if !p.more() {
+			return nil, p.errorf("trailing backslash")
+		}
+		e := p.next()
+		switch {
+		case e == '(' && !p.extended:
+			return p.group(depth)
+		case e == ')' && !p.extended:
+			return nil, p.errorf(`unmatched \)`)
+		case e == '{' && !p.extended:
+			return nil, p.errorf("repetition-operator operand invalid")
+		case e >= '1' && e <= '9':
+			n := int(e - '0')
+			if !p.closed[n] {
+				return nil, p.errorf("invalid back reference \\%c", e)
+			}
+			p.backrefs = true
+			return &node{op: opBackref, n: n}, nil
+		}
+		return &node{op: opLiteral, r: e}, nil
+	}
+	return &node{op: opLiteral, r: c}, nil
+}
+
+// group parses a subexpression, after its opening parenthesis
+func (p *parser) group(depth int) (*node, error) {
+	p.groups++
+	n := p.groups
+	sub, err := p.alternation(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+	if !p.atGroupEnd(depth + 1) {
+		if p.extended {
+			return nil, p.errorf("unmatched (")
+		}
+		return nil, p.errorf(`unmatched \(`)
+	}
+	if p.extended {
+		p.pos++
+	} else {
+		p.pos += 2
+	}
+	p.closed[n] = true
+	return &node{op: opGroup, n: n, subs: []*node{sub}}, nil
+}
+
+// repeats parses any repetition operators following an atom
+func (p *parser) repeats(atom *node) (*node, error) {
+	for p.more() {
+		min, max := 0, -1
+		switch {
+		case p.lookingAt("*"):
+			p.pos++
+		case p.extended && p.lookingAt("+"):
+			p.pos++
+			min = 1
+		case p.extended && p.lookingAt("?"):
+			p.pos++
+			max = 1
+		case p.extended && p.lookingAt("{") && p.pos+1 < len(p.expr) && isDigit(rune(p.expr[p.pos+1])):
+			p.pos++
+			var err error
+			if min, max, err = p.interval("}"); err != nil {
+				return nil, err
+			}
+		case !p.extended && p.lookingAt(`\{`):
+			p.pos += 2
+			var err error
+			if min, max, err = p.interval(`\}`); err != nil {
+				return nil, err
+			}
+		default:
+			return atom, nil
+		}
+		if atom.op == opBOL || atom.op == opEOL {
+			return nil, p.errorf("repetition-operator operand invalid")
+		}
+		atom = &node{op: opRepeat, min: min, max: max, subs: []*node{atom}}
+	}
+	return atom, nil
+}
+
+// interval parses the m, m, or m,n of an interval, and its closing brace
+func (p *parser) interval(end string) (min, max int, err error) {
+	number := func() (int, bool) {
+		start := p.pos
+		for p.more() && isDigit(p.peek()) {
+			p.pos++
+		}
+		if start == p.pos {
+			return 0, false
+		}
+		n, err := strconv.Atoi(p.expr[start:p.pos])
+		return n, err == nil
+	}
+	min, ok := number()
+	if !ok {
+		return 0, 0, p.errorf("invalid interval")
+	}
+	max = min
+	if p.lookingAt(",") {
+		p.pos++
+		if max, ok = number(); !ok {
+			max = -1
+		}
+	}
+	if !p.lookingAt(end) {
+		return 0, 0, p.errorf("unterminated interval")
+	}
+	p.pos += len(end)
+	if min > DupMax || max > DupMax || (max >= 0 && max < min) {
+		return 0, 0, p.errorf("invalid interval {%d,%d}", min, max)
+	}
+	return min, max, nil
+}
+
+// bracket parses a bracket expression, after its [
+func (p *parser) bracket() (*charSet, error) {
+	set := &charSet{}
+	if p.lookingAt("^") {
+		p.pos++
+		set.negate = true
+	}
+	first := true
+	for {
+		if !p.more() {
+			return nil, p.errorf("unmatched [")
+		}
+		if p.lookingAt("]") && !first {
+			p.pos++
+			return set, nil
+		}
+		first = false
+
+		if p.lookingAt("[:") {
+			end := indexFrom(p.expr, ":]", p.pos+2)
+			if end < 0 {
+				return nil, p.errorf("unmatched [:")
+			}
+			name := p.expr[p.pos+2 : end]
+			if !classes[name] {
+				return nil, p.errorf("invalid character class %s", name)
+			}
+			set.classes = append(set.classes, name)
+			p.pos = end + 2
+			continue
+		}
+		lo, err := p.bracketChar()
+		if err != nil {
+			return nil, err
+		}
+		// A - is a range, unless it's last
+		if p.lookingAt("-") && p.pos+1 < len(p.expr) && p.expr[p.pos+1] != ']' {
+			p.pos++
+			hi, err := p.bracketChar()
+			if err != nil {
+				return nil, err
+			}
+			if hi < lo {
+				return nil, p.errorf("invalid range %c-%c", lo, hi)
+			}
+			set.ranges = append(set.ranges, [2]rune{lo, hi})
+			continue
+		}
+		set.runes = append(set.runes, lo)
+	}
+}
+
+// bracketChar parses one character in a bracket expression, which may be a
+// collating symbol or an equivalence class. Each stands for its single
+// character, as there are no multi-character collating elements.
+func (p *parser) bracketChar() (rune, error) {
+	for _, delim := range []string{".", "="} {
+		if !p.lookingAt("[" + delim) {
+			continue
+		}
+		end := indexFrom(p.expr, delim+"]", p.pos+2)
+		if end < 0 {
+			return 0, p.errorf("unmatched [%s", delim)
+		}
+		name := p.expr[p.pos+2 : end]
+		r, size := utf8.DecodeRuneInString(name)
+		if name == "" || size != len(name) {
+			return 0, p.errorf("invalid collating element %s", name)
+		}
+		p.pos = end + 2
+		return r, nil
+	}
+	return p.next(), nil
+}
+
+func indexFrom(s, sub string, from int) int {
+	for i := from; i+len(sub) <= len(s); i++ {
+		if s[i:i+len(sub)] == sub {
+			return i
+		}
+	}
+	return -1
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
@@ -0,0 +1,212 @@
+// Package regexes implements POSIX basic and extended regular expressions
+package regexes
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+)
+
+// Flags select the syntax of an expression and how it matches
+type Flags uint
+
+const (
+	// Extended selects an extended regular expression (ERE) rather than a
+	// basic one (BRE)
+	Extended Flags = 1 << iota
+	// IgnoreCase matches without regard to case
+	IgnoreCase
+)
+
+// Regexp is a compiled POSIX basic or extended regular expression. A match
+// is the leftmost, and of those the longest.
+//
+// Expressions are translated for the regexp package where it can express
+// them. It can't express back-references, and it rejects repetitions that
+// nest to more than 1000 in all, such as \(a\{40\}\)\{30\}, although each
+// interval is within DupMax. Those use a slower backtracking matcher
+// instead.
+type Regexp struct {
+	expr     string
+	numSub   int
+	re       *regexp.Regexp
+	tree     *node
+	foldCase bool
+}
+
+// Compile parses a regular expression
+func Compile(expr string, flags Flags) (*Regexp, error) {
+	tree, p, err := parse(expr, flags&Extended != 0)
+	if err != nil {
+		return nil, err
+	}
+	re := &Regexp{expr: expr, numSub: p.groups, tree: tree, foldCase: flags&IgnoreCase != 0}
+	if p.backrefs {
+		return re, nil
+	}
+
+	var b strings.Builder
+	b.WriteString("(?s")
+	if re.foldCase {
+		b.WriteString("i")
+	}
+	b.WriteString(")")
+	translate(&b, tree)
+	if re.re, err = regexp.Compile(b.String()); err != nil {
+		// Repetitions that nest too deep for the regexp package are left
+		// to the backtracking matcher
+		if serr, ok := err.(*syntax.Error); ok && serr.Code == syntax.ErrInvalidRepeatSize {
+			re.re = nil
+			return re, nil
+		}
+		return nil, &Error{expr, err.Error()}
+	}
+	re.re.Longest()
+	return re, nil
+}
+
+// MustCompile is like Compile, but panics if the expression can't be parsed
+func MustCompile(expr string, flags Flags) *Regexp {
+	re, err := Compile(expr, flags)
+	if err != nil {
+		panic(err)
+	}
+	return re
+}
+
+// String returns the source of the expression
+func (re *Regexp) String() string {
+	return re.expr
+}
+
+// NumSubexp returns the number of parenthesized subexpressions
+func (re *Regexp) NumSubexp() int {
+	return re.numSub
+}
+
+// MatchString reports whether s contains a match
+func (re *Regexp) MatchString(s string) bool {
+	if re.re != nil {
+		return re.re.MatchString(s)
+	}
+	return re.FindStringSubmatchIndex(s) != nil
+}
+
+// FindStringSubmatchIndex returns the start and end of the match in s,
+// followed by those of each subexpression, or nil if there is none. A
+// subexpression that took no part in the match is at -1, -1.
+func (re *Regexp) FindStringSubmatchIndex(s string) []int {
+	if re.re != nil {
+		return re.re.FindStringSubmatchIndex(s)
+	}
+	return re.backtrack(s, 0)
+}
+
+// FindAllStringSubmatchIndex returns the successive non-overlapping
+// matches in s, as FindStringSubmatchIndex does, up to n of them, or all of
+// them if n is negative. An empty match right after a previous one isn't
+// counted, and ^ only matches at the start of s.
+func (re *Regexp) FindAllStringSubmatchIndex(s string, n int) [][]int {
+	if re.re != nil {
+		return re.re.FindAllStringSubmatchIndex(s, n)
+	}
+	var all [][]int
+	prevEnd := -1
+	for pos := 0; pos <= len(s) && (n < 0 || len(all) < n); {
+		m := re.backtrack(s, pos)
+		if m == nil {
+			break
+		}
+		if m[1] == m[0] && m[0] == prevEnd {
+			// Skip an empty match adjacent to the last one
+			if m[0] == len(s) {
+				break
+			}
+			pos = m[0] + runeLen(s[m[0]:])
+			continue
+		}
+		all = append(all, m)
+		prevEnd = m[1]
+		pos = m[1]
+		if m[1] == m[0] {
+			if pos == len(s) {
+				break
+			}
+			pos += runeLen(s[pos:])
+		}
+	}
+	return all
+}
+
+// translate writes the tree in the syntax of the regexp package
+func translate(b *strings.Builder, n *node) {
+	switch n.op {
+	case opLiteral:
+		b.WriteString(regexp.QuoteMeta(string(n.r)))
+	case opAny:
+		b.WriteString(".")
+	case opSet:
+		b.WriteString(n.set.syntax())
+	case opBOL:
+		b.WriteString("^")
+	case opEOL:
+		b.WriteString("$")
+	case opConcat:
+		for _, sub := range n.subs {
+			translate(b, sub)
+		}
+	case opAlternate:
+		b.WriteString("(?:")
+		for i, sub := range n.subs {
+			if i > 0 {
+				b.WriteString("|")
+			}
+			translate(b, sub)
+		}
+		b.WriteString(")")
+	case opGroup:
+		b.WriteString("(")
+		translate(b, n.subs[0])
+		b.WriteString(")")
+	case opRepeat:
+		b.WriteString("(?:")
+		translate(b, n.subs[0])
+		b.WriteString(")")
+		switch {
+		case n.min == 0 && n.max < 0:
+			b.WriteString("*")
+		case n.min == 1 && n.max < 0:
+			b.WriteString("+")
+		case n.max < 0:
+			b.WriteString("{" + strconv.Itoa(n.min) + ",}")
+		default:
+			b.WriteString("{" + strconv.Itoa(n.min) + "," + strconv.Itoa(n.max) + "}")
+		}
+	}
+}
+
+// syntax returns the bracket expression in the syntax of the regexp package
+func (set *charSet) syntax() string {
+	var b strings.Builder
+	b.WriteString("[")
+	if set.negate {
+		b.WriteString("^")
+	}
+	for _, r := range set.runes {
+		b.WriteString(quoteSetRune(r))
+	}
+	for _, rg := range set.ranges {
+		b.WriteString(quoteSetRune(rg[0]) + "-" + quoteSetRune(rg[1]))
+	}
+	for _, class := range set.classes {
+		b.WriteString("[:" + class + ":]")
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// quoteSetRune escapes a character for a character class
+func quoteSetRune(r rune) string {
+	return `\x{` + strconv.FormatInt(int64(r), 16) + `}`
+}
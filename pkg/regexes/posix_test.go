@@ -0,0 +1,217 @@
+package regexes
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var posixTests = []struct {
+	expr  string
+	flags Flags
+	input string
+	want  []int // The match and its subexpressions, or nil
+}{
+	// Basic regular expressions
+	{"abc", 0, "xabcx", []int{1, 4}},
+	{"a.c", 0, "a\nc", []int{0, 3}},
+	{"a*", 0, "baaa", []int{0, 0}},
+	{"ba*", 0, "baaa", []int{0, 4}},
+	{"*a", 0, "x*a", []int{1, 3}},
+	{`\(*a\)`, 0, "*a", []int{0, 2, 0, 2}},
+	{"^*a", 0, "*a", []int{0, 2}},
+	{"a^b$c", 0, "a^b$c", []int{0, 5}},
+	{"^ab$", 0, "ab", []int{0, 2}},
+	{"^ab$", 0, "abc", nil},
+	{`a\{2\}`, 0, "aaa", []int{0, 2}},
+	{`a\{2,\}`, 0, "aaaa", []int{0, 4}},
+	{`a\{1,2\}b`, 0, "aaab", []int{1, 4}},
+	{`a+?|`, 0, "a+?|", []int{0, 4}},
+	{`\(a\)\(b\)*`, 0, "abb", []int{0, 3, 0, 1, 2, 3}},
+	{`\(a\)\|b`, 0, "a|b", []int{0, 3, 0, 1}},
+	{`\.\*\[`, 0, ".*[", []int{0, 3}},
+	{`{1}`, 0, "{1}", []int{0, 3}},
+	// Back-references
+	{`\(a*\)b\1`, 0, "aabaa", []int{0, 5, 0, 2}},
+	{`\(a*\)b\1`, 0, "aaba", []int{1, 4, 1, 2}},
+	{`\([a-c]\)\1`, 0, "abccb", []int{2, 4, 2, 3}},
+	{`^\(.*\)\1$`, 0, "abcabc", []int{0, 6, 0, 3}},
+	{`\(x\)\1`, IgnoreCase, "xX", []int{0, 2, 0, 1}},
+	{`\(.\)\1`, 0, "abcd", nil},
+	// Repetitions nesting beyond what the regexp package allows
+	{`\(a\{40\}\)\{30\}`, 0, strings.Repeat("a", 1201), []int{0, 1200, 1160, 1200}},
+	{"(a{2}){255}b", Extended, strings.Repeat("a", 510) + "b", []int{0, 511, 508, 510}},
+	{"(a{255}){255}", Extended, "aaa", nil},
+	// Bracket expressions
+	{"[abc]", 0, "xxb", []int{2, 3}},
+	{"[^abc]", 0, "abx", []int{2, 3}},
+	{"[]a]", 0, "]", []int{0, 1}},
+	{"[^]a]", 0, "]ab", []int{2, 3}},
+	{"[a-]", 0, "-", []int{0, 1}},
+	{"[[:digit:]]*", 0, "12a", []int{0, 2}},
+	{"[[:alpha:][:space:]]*", 0, "ab c1", []int{0, 4}},
+	{"[[.-.]a]", 0, "-", []int{0, 1}},
+	{"[[=e=]]", 0, "e", []int{0, 1}},
+	{`[\]`, 0, `\`, []int{0, 1}},
+	{"[.]", 0, "a.", []int{1, 2}},
+	{"[é-ë]", 0, "aê", []int{1, 3}},
+	// Extended regular expressions
+	{"a+", Extended, "baa", []int{1, 3}},
+	{"ab?c", Extended, "ac", []int{0, 2}},
+	{"a|ab", Extended, "ab", []int{0, 2}},
+	{"(a|ab)(c|bcd)", Extended, "abcd", []int{0, 4, 0, 1, 1, 4}},
+	{"(a)|b", Extended, "b", []int{0, 1, -1, -1}},
+	{"a{2,3}", Extended, "aaaa", []int{0, 3}},
+	{"a{,3}", Extended, "a{,3}", []int{0, 5}},
+	{"x{", Extended, "x{", []int{0, 2}},
+	{"^a|b$", Extended, "cab", []int{2, 3}},
+	{`\(a\)`, Extended, "(a)", []int{0, 3}},
+	{"()a", Extended, "a", []int{0, 1, 0, 0}},
+	{"(a*)+b", Extended, "aab", []int{0, 3, 0, 2}},
+	{`(a)\1`, Extended, "aa", []int{0, 2, 0, 1}},
+	{"ABC", Extended | IgnoreCase, "xabc", []int{1, 4}},
+	{"[a-c]+", Extended | IgnoreCase, "xABC", []int{1, 4}},
+}
+
+func TestCompile(t *testing.T) {
+	for _, tt := range posixTests {
+		re, err := Compile(tt.expr, tt.flags)
+		if err != nil {
+			t.Errorf("%q: %s", tt.expr, err)
+			continue
+		}
+		got := re.FindStringSubmatchIndex(tt.input)
+		if len(got) > len(tt.want) && tt.want != nil {
+			got = got[:len(tt.want)]
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q on %q: got %v, expected %v", tt.expr, tt.input, got, tt.want)
+		}
+		if re.MatchString(tt.input) != (tt.want != nil) {
+			t.Errorf("%q on %q: MatchString disagrees", tt.expr, tt.input)
+		}
+	}
+}
+
+// TestBacktrack checks the backtracking matcher against the regexp package
+func TestBacktrack(t *testing.T) {
+	for _, tt := range posixTests {
+		re, err := Compile(tt.expr, tt.flags)
+		if err != nil || re.re == nil {
+			continue
+		}
+		want := re.re.FindStringSubmatchIndex(tt.input)
+		got := re.backtrack(tt.input, 0)
+		if !reflect.DeepEqual(got[:min(2, len(got))], want[:min(2, len(want))]) {
+			t.Errorf("%q on %q: backtracking got %v, expected %v", tt.expr, tt.input, got, want)
+		}
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// TestRegexpLimits checks which expressions fall back to backtracking
+// because the regexp package can't take them
+func TestRegexpLimits(t *testing.T) {
+	tests := []struct {
+		expr      string
+		backtrack bool
+	}{
+		{`a\{255\}`, false},
+		{`\(a\{10\}\)\{100\}`, false},
+		{`\(a\{10\}\)\{101\}`, true},
+		{`\(a\{255\}\)\{255\}`, true},
+		{`\(a\)\1`, true},
+	}
+	for _, tt := range tests {
+		if re := MustCompile(tt.expr, 0); (re.re == nil) != tt.backtrack {
+			t.Errorf("%q: backtracking is %v, expected %v", tt.expr, re.re == nil, tt.backtrack)
+		}
+	}
+}
+
+// TestBacktrackRepeats checks that nested repetitions, which can split the
+// input in exponentially many ways, still match in reasonable time
+func TestBacktrackRepeats(t *testing.T) {
+	tests := []struct {
+		expr  string
+		input string
+		want  []int
+	}{
+		{`\(a*\)*\1b`, strings.Repeat("a", 30), nil},
+		{`\(a*\)*\1b`, strings.Repeat("a", 30) + "b", []int{0, 31}},
+		{`\(a*\)*\1b`, "xb", []int{1, 2}},
+		{`\(a*\)*\1$`, "aaaa", []int{0, 4}},
+		{`\(\(a*\)*b\)*\1c`, strings.Repeat("ab", 10), nil},
+	}
+	for _, tt := range tests {
+		done := make(chan []int, 1)
+		go func() {
+			done <- MustCompile(tt.expr, 0).FindStringSubmatchIndex(tt.input)
+		}()
+		select {
+		case got := <-done:
+			if !reflect.DeepEqual(got[:min(2, len(got))], tt.want) {
+				t.Errorf("%q on %q: got %v, expected %v", tt.expr, tt.input, got, tt.want)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("%q on %q: timed out", tt.expr, tt.input)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		expr  string
+		flags Flags
+	}{
+		{`\(a`, 0},
+		{`a\)`, 0},
+		{`a\{1`, 0},
+		{`a\{2,1\}`, 0},
+		{`a\{256\}`, 0},
+		{`\{1\}`, 0},
+		{"[a", 0},
+		{"[[:nope:]]", 0},
+		{"[z-a]", 0},
+		{`\1`, 0},
+		{`\(a\1\)`, 0},
+		{`a\`, 0},
+		{"(a", Extended},
+		{"a)", Extended},
+		{"+a", Extended},
+		{"^*", Extended},
+	}
+	for _, tt := range tests {
+		if _, err := Compile(tt.expr, tt.flags); err == nil {
+			t.Errorf("%q: expected an error", tt.expr)
+		} else if _, ok := err.(*Error); !ok {
+			t.Errorf("%q: error %v isn't an *Error", tt.expr, err)
+		}
+	}
+}
+
+func TestFindAll(t *testing.T) {
+	tests := []struct {
+		expr  string
+		input string
+		want  [][]int
+	}{
+		{"a*", "baaac", [][]int{{0, 0}, {1, 4}, {5, 5}}},
+		{"^a", "aaa", [][]int{{0, 1}}},
+		{`\(a\)\1`, "aaaaa", [][]int{{0, 2, 0, 1}, {2, 4, 2, 3}}},
+		{`\(b*\)\1`, "abb", [][]int{{0, 0, 0, 0}, {1, 3, 1, 2}}},
+	}
+	for _, tt := range tests {
+		got := MustCompile(tt.expr, 0).FindAllStringSubmatchIndex(tt.input, -1)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q on %q: got %v, expected %v", tt.expr, tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package tsort
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// Nodes returns every node, in the order they were first added
+func (s *Sorter) Nodes() []string {
+	return append([]string(nil), s.names...)
+}
+
+func (s *Sorter) lookup(ids []int) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = s.names[id]
+	}
+	return out
+}
+
+// Successors returns the nodes that must come directly after name
+func (s *Sorter) Successors(name string) []string {
+	id, ok := s.index[name]
+	if !ok {
+		return nil
+	}
+	return s.lookup(s.succs[id])
+}
+
+// Predecessors returns the nodes that must come directly before name
+func (s *Sorter) Predecessors(name string) []string {
+	id, ok := s.index[name]
+	if !ok {
+		return nil
+	}
+	return s.lookup(s.preds[id])
+}
+
+// Components returns the strongly connected components of the graph, found
+// with Tarjan's algorithm. Components are returned in topological order, and
+// the nodes of each are in the order they were first added. Any component with
+// more than one node, or a node with an edge to itself, is a cycle.
+func (s *Sorter) Components() [][]string {
+	n := len(s.names)
+	const unvisited = -1
+	index := make([]int, n)
+	lowlink := make([]int, n)
+	onStack := make([]bool, n)
+	for i := range index {
+		index[i] = unvisited
+	}
+
+	// The depth-first search is iterative, as build graphs can easily be deep
+	// enough to make recursion costly.
+	type frame struct {
+		id   int
+		next int // Index into succs[id] of the next edge to follow
+	}
+	var components [][]int
+	var stack []int
+	counter := 0
+	for root := 0; root < n; root++ {
+		if index[root] != unvisited {
+			continue
+		}
+		call := []frame{{id: root}}
+		index[root], lowlink[root] = counter, counter
+		counter++
+		stack = append(stack, root)
+		onStack[root] = true
+
+		for len(call) > 0 {
+			f := &call[len(call)-1]
+			if f.next < len(s.succs[f.id]) {
+				w := s.succs[f.id][f.next]
+				f.next++
+				if index[w] == unvisited {
+					index[w], lowlink[w] = counter, counter
+					counter++
+					stack = append(stack, w)
+					onStack[w] = true
+					call = append(call, frame{id: w})
+				} else if onStack[w] && index[w] < lowlink[f.id] {
+					lowlink[f.id] = index[w]
+				}
+				continue
+			}
+
+			v := f.id
+			call = call[:len(call)-1]
+			if len(call) > 0 {
+				parent := call[len(call)-1].id
+				if lowlink[v] < lowlink[parent] {
+					lowlink[parent] = lowlink[v]
+				}
+			}
+			if lowlink[v] != index[v] {
+				continue
+			}
+			var component []int
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				component = append(component, w)
+				if w == v {
+					break
+				}
+			}
+			sort.Ints(component)
+			components = append(components, component)
+		}
+	}
+
+	// Tarjan's algorithm finds components in reverse topological order
+	out := make([][]string, len(components))
+	for i, c := range components {
+		out[len(components)-1-i] = s.lookup(c)
+	}
+	return out
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language
+func (s *Sorter) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph tsort {")
+	for id, name := range s.names {
+		if len(s.succs[id]) == 0 && len(s.preds[id]) == 0 {
+			fmt.Fprintf(bw, "\t%s;\n", strconv.Quote(name))
+		}
+		for _, succ := range s.succs[id] {
+			fmt.Fprintf(bw, "\t%s -> %s;\n", strconv.Quote(name), strconv.Quote(s.names[succ]))
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
@@ -1,87 +1,187 @@
 package tsort
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
 
+// Sorter builds a graph from partial orderings and sorts it topologically.
+// Nodes are kept in the order they were first seen, which makes every
+// ordering it produces deterministic.
 type Sorter struct {
-	orderings map[string][]string
+	index map[string]int // Maps a node's name to its ID
+	names []string       // Maps a node's ID to its name
+	succs [][]int        // Successors of each node, in the order they were added
+	preds [][]int        // Predecessors of each node, in the order they were added
+	edges map[edge]struct{}
 }
 
-func inSlice(haystack []string, needle string) bool {
-	for _, j := range haystack {
-		if needle == j {
-			return true
-		}
+type edge struct {
+	from, to int
+}
+
+// CycleError reports the cycles that had to be broken to produce an ordering
+type CycleError struct {
+	Cycles [][]string
+}
+
+func (e *CycleError) Error() string {
+	var parts []string
+	for _, c := range e.Cycles {
+		parts = append(parts, strings.Join(c, " "))
 	}
-	return false
+	return fmt.Sprintf("input contains a loop: %s", strings.Join(parts, "; "))
 }
 
-func (s *Sorter) Add(items []string) {
-	if s.orderings == nil {
-		s.orderings = make(map[string][]string)
+// node returns the ID for name, adding it to the graph if necessary
+func (s *Sorter) node(name string) int {
+	if s.index == nil {
+		s.index = make(map[string]int)
+		s.edges = make(map[edge]struct{})
 	}
-	if len(items) == 0 {
-		return
+	if id, ok := s.index[name]; ok {
+		return id
 	}
-	if len(items) == 1 {
-		s.orderings[items[0]] = nil
+	id := len(s.names)
+	s.index[name] = id
+	s.names = append(s.names, name)
+	s.succs = append(s.succs, nil)
+	s.preds = append(s.preds, nil)
+	return id
+}
+
+// Add records that each item must come before the next. A single item is
+// added to the graph with no ordering constraints.
+func (s *Sorter) Add(items []string) {
+	if len(items) == 0 {
 		return
 	}
-	for i := 0; i < len(items)-1; i++ {
-		if !inSlice(s.orderings[items[i]], items[i+1]) {
-			s.orderings[items[i]] = append(s.orderings[items[i]], items[i+1])
+	prev := s.node(items[0])
+	for _, item := range items[1:] {
+		next := s.node(item)
+		e := edge{prev, next}
+		if _, ok := s.edges[e]; !ok {
+			s.edges[e] = struct{}{}
+			s.succs[prev] = append(s.succs[prev], next)
+			s.preds[next] = append(s.preds[next], prev)
 		}
+		prev = next
 	}
 }
 
-func (s *Sorter) heads() []string {
-	var out []string
-	candidates := make(map[string]struct{})
-	for k := range s.orderings {
-		candidates[k] = struct{}{}
+// Order returns every node such that each comes after all of its
+// predecessors, using Kahn's algorithm. Ties are broken by the order in which
+// nodes were first added.
+//
+// If the graph contains cycles, each one is broken by releasing its
+// earliest-seen node, and a full ordering is still returned along with a
+// *CycleError describing the cycles.
+func (s *Sorter) Order() ([]string, error) {
+	levels, err := s.Levels()
+	out := make([]string, 0, len(s.names))
+	for _, level := range levels {
+		out = append(out, level...)
 	}
-	for _, values := range s.orderings {
-		for _, v := range values {
-			if _, ok := candidates[v]; ok {
-				delete(candidates, v)
-			}
-		}
+	return out, err
+}
+
+// Levels groups the nodes into stages: every node's predecessors are all in
+// earlier stages, so the nodes within a stage may be processed in parallel.
+// Each node is placed in the earliest stage it can be. Cycles are broken as
+// they are by Order, which visits the stages in sequence.
+func (s *Sorter) Levels() ([][]string, error) {
+	n := len(s.names)
+	indegree := make([]int, n)
+	for id := range s.preds {
+		indegree[id] = len(s.preds[id])
 	}
-	for k := range candidates {
-		out = append(out, k)
+	queued := make([]bool, n)
+	var level []int
+	for id := 0; id < n; id++ {
+		if indegree[id] == 0 {
+			level = append(level, id)
+			queued[id] = true
+		}
 	}
 
-	return out
-}
+	var levels [][]string
+	var cycles [][]string
+	done := 0
+	cursor := 0 // All nodes before the cursor have been queued
+	for done < n {
+		if len(level) == 0 {
+			for queued[cursor] {
+				cursor++
+			}
+			cycle := s.findCycle(cursor, queued)
+			// Report the cycle starting from the node that will be released
+			first := 0
+			for i, id := range cycle {
+				if id < cycle[first] {
+					first = i
+				}
+			}
+			release := cycle[first]
+			names := make([]string, 0, len(cycle))
+			for i := range cycle {
+				names = append(names, s.names[cycle[(first+i)%len(cycle)]])
+			}
+			cycles = append(cycles, names)
+			level = append(level, release)
+			queued[release] = true
+		}
 
-func (s *Sorter) isBlocked(n string) bool {
-	for _, items := range s.orderings {
-		for _, i := range items {
-			if i == n {
-				return true
+		var next []int
+		names := make([]string, len(level))
+		for i, id := range level {
+			names[i] = s.names[id]
+			for _, succ := range s.succs[id] {
+				indegree[succ]--
+				if indegree[succ] <= 0 && !queued[succ] {
+					next = append(next, succ)
+					queued[succ] = true
+				}
 			}
 		}
+		levels = append(levels, names)
+		done += len(level)
+		// Successors are released in the order their predecessors were
+		// processed, not the order they were seen
+		sort.Ints(next)
+		level = next
 	}
-	return false
+
+	if len(cycles) > 0 {
+		return levels, &CycleError{cycles}
+	}
+	return levels, nil
 }
 
-func (s *Sorter) Order() ([]string, error) {
-	var out []string
-	var candidates = s.heads()
-	for len(candidates) > 0 {
-		var head string
-		head, candidates = candidates[0], candidates[1:]
-		out = append(out, head)
-		unblocked := s.orderings[head]
-		delete(s.orderings, head)
-		for _, u := range unblocked {
-			if !s.isBlocked(u) {
-				candidates = append(candidates, u)
+// findCycle walks backwards from start through nodes that haven't been
+// queued. Every such node has an unqueued predecessor, so the walk must
+// eventually revisit a node, closing a cycle. The cycle is returned in
+// forward order.
+func (s *Sorter) findCycle(start int, queued []bool) []int {
+	position := make(map[int]int)
+	var path []int
+	id := start
+	for {
+		if i, ok := position[id]; ok {
+			cycle := path[i:]
+			// Reverse the predecessor walk so the cycle reads along its edges
+			for l, r := 0, len(cycle)-1; l < r; l, r = l+1, r-1 {
+				cycle[l], cycle[r] = cycle[r], cycle[l]
+			}
+			return cycle
+		}
+		position[id] = len(path)
+		path = append(path, id)
+		for _, p := range s.preds[id] {
+			if !queued[p] {
+				id = p
+				break
 			}
 		}
 	}
-	if len(s.orderings) > 0 {
-		return nil, fmt.Errorf("Hmm. Still items left. Ordering impossible?")
-	}
-
-	return out, nil
 }
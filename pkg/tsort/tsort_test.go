@@ -69,3 +69,129 @@ func TestTsort(t *testing.T) {
 		}
 	}
 }
+
+func TestDeterministic(t *testing.T) {
+	var sorter Sorter
+	sorter.Add([]string{"c"})
+	sorter.Add([]string{"b", "d"})
+	sorter.Add([]string{"a", "d"})
+	sorter.Add([]string{"e"})
+	order, err := sorter.Order()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(order, " "), "c b a e d"; got != want {
+		t.Errorf("got %s, wanted %s", got, want)
+	}
+}
+
+func TestDeterministicLevels(t *testing.T) {
+	// d is released before c, but c was seen first
+	var sorter Sorter
+	sorter.Add([]string{"a", "c"})
+	sorter.Add([]string{"b", "d"})
+	sorter.Add([]string{"b", "c"})
+	order, err := sorter.Order()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(order, " "), "a b c d"; got != want {
+		t.Errorf("got %s, wanted %s", got, want)
+	}
+}
+
+func TestCycle(t *testing.T) {
+	var sorter Sorter
+	sorter.Add(strings.Fields("start a b c a"))
+	sorter.Add(strings.Fields("c end"))
+	order, err := sorter.Order()
+
+	cerr, ok := err.(*CycleError)
+	if !ok {
+		t.Fatalf("expected a *CycleError, got %v", err)
+	}
+	if len(cerr.Cycles) != 1 || strings.Join(cerr.Cycles[0], " ") != "a b c" {
+		t.Errorf("cycles: got %q, wanted [[a b c]]", cerr.Cycles)
+	}
+	if got, want := strings.Join(order, " "), "start a b c end"; got != want {
+		t.Errorf("order: got %s, wanted %s", got, want)
+	}
+}
+
+func TestSelfLoop(t *testing.T) {
+	var sorter Sorter
+	sorter.Add([]string{"a", "a"})
+	sorter.Add([]string{"a", "b"})
+	order, err := sorter.Order()
+	if _, ok := err.(*CycleError); !ok {
+		t.Errorf("expected a *CycleError, got %v", err)
+	}
+	if got, want := strings.Join(order, " "), "a b"; got != want {
+		t.Errorf("order: got %s, wanted %s", got, want)
+	}
+}
+
+func BenchmarkOrder(b *testing.B) {
+	// A layered graph with 100k edges, much like a large build graph
+	const width, depth = 100, 100
+	var pairs [][]string
+	for d := 0; d < depth-1; d++ {
+		for i := 0; i < width; i++ {
+			for j := 0; j < 10; j++ {
+				from := fmt.Sprintf("n%d_%d", d, i)
+				to := fmt.Sprintf("n%d_%d", d+1, (i*7+j)%width)
+				pairs = append(pairs, []string{from, to})
+			}
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var sorter Sorter
+		for _, p := range pairs {
+			sorter.Add(p)
+		}
+		if _, err := sorter.Order(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestLevels(t *testing.T) {
+	var sorter Sorter
+	sorter.Add(strings.Fields("a b d"))
+	sorter.Add(strings.Fields("c d"))
+	sorter.Add(strings.Fields("a d"))
+	sorter.Add(strings.Fields("e"))
+	levels, err := sorter.Levels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fmt.Sprint(levels), "[[a c e] [b] [d]]"; got != want {
+		t.Errorf("got %s, wanted %s", got, want)
+	}
+}
+
+func TestComponents(t *testing.T) {
+	var sorter Sorter
+	sorter.Add(strings.Fields("start a b c a"))
+	sorter.Add(strings.Fields("c end x y x"))
+	sorter.Add(strings.Fields("lone"))
+	got := fmt.Sprint(sorter.Components())
+	if want := "[[lone] [start] [a b c] [end] [x y]]"; got != want {
+		t.Errorf("got %s, wanted %s", got, want)
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	var sorter Sorter
+	sorter.Add(strings.Fields("a b"))
+	sorter.Add([]string{`say "hi"`})
+	var out strings.Builder
+	if err := sorter.WriteDOT(&out); err != nil {
+		t.Fatal(err)
+	}
+	want := "digraph tsort {\n\t\"a\" -> \"b\";\n\t\"say \\\"hi\\\"\";\n}\n"
+	if out.String() != want {
+		t.Errorf("got %q, wanted %q", out.String(), want)
+	}
+}
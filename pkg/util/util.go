@@ -0,0 +1,154 @@
+// Package util is the runtime the utilities share: where their input and
+// output go, how they report problems, and the exit status that results
+package util
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/fwip/posix-utils/pkg/locale"
+)
+
+// UsageStatus is the exit status after a usage error. Utilities whose
+// specifications give a status for trouble of any kind use that instead.
+const UsageStatus = 2
+
+// Utility holds the state of a running utility
+type Utility struct {
+	Name   string // Used to begin each diagnostic
+	Usage  string // The usage message, written after a usage error
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	status int
+	exit   func(int) // os.Exit if nil
+}
+
+// New returns a Utility using the standard input, output and error
+func New(name, usage string) *Utility {
+	return &Utility{
+		Name:   name,
+		Usage:  usage,
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+}
+
+// Message returns the text of an error as a diagnostic shows it. An error
+// about a file is written as "file: message", without the operation that
+// failed.
+func Message(err error) string {
+	switch e := err.(type) {
+	case *os.PathError:
+		return e.Path + ": " + e.Err.Error()
+	case *os.LinkError:
+		return e.Old + ": " + e.Err.Error()
+	}
+	return err.Error()
+}
+
+// Report writes a diagnostic to standard error without changing the exit
+// status, for a utility that works out its own
+func (u *Utility) Report(err error) {
+	fmt.Fprintf(u.Stderr, "%s: %s\n", u.Name, Message(err))
+}
+
+// Warn writes a diagnostic to standard error, and sets the exit status to
+// at least 1, so that the utility can carry on and fail at the end
+func (u *Utility) Warn(err error) {
+	u.Report(err)
+	u.SetStatus(1)
+}
+
+// Warnf formats a diagnostic, like Warn
+func (u *Utility) Warnf(format string, args ...interface{}) {
+	u.Warn(fmt.Errorf(format, args...))
+}
+
+// Fatal writes a diagnostic and exits with status
+func (u *Utility) Fatal(err error, status int) {
+	fmt.Fprintf(u.Stderr, "%s: %s\n", u.Name, Message(err))
+	u.Exit(status)
+}
+
+// UsageError writes a diagnostic and the usage message, and exits with
+// status
+func (u *Utility) UsageError(err error, status int) {
+	fmt.Fprintf(u.Stderr, "%s: %s\n", u.Name, Message(err))
+	fmt.Fprint(u.Stderr, u.Usage)
+	if !strings.HasSuffix(u.Usage, "\n") {
+		fmt.Fprintln(u.Stderr)
+	}
+	u.Exit(status)
+}
+
+// SetStatus raises the exit status to status. It never lowers it, so an
+// earlier failure isn't forgotten.
+func (u *Utility) SetStatus(status int) {
+	if status > u.status {
+		u.status = status
+	}
+}
+
+// Status returns the exit status so far
+func (u *Utility) Status() int {
+	return u.status
+}
+
+// Exit exits with status, or the exit status so far if that is higher
+func (u *Utility) Exit(status int) {
+	u.SetStatus(status)
+	if u.exit == nil {
+		os.Exit(u.status)
+	}
+	u.exit(u.status)
+}
+
+// Open opens a file operand for reading. The name "-" means the standard
+// input, which isn't closed by the ReadCloser.
+func (u *Utility) Open(name string) (io.ReadCloser, error) {
+	if name == "-" {
+		return ioutil.NopCloser(u.Stdin), nil
+	}
+	return os.Open(name)
+}
+
+// Operands returns the file operands, or "-" for the standard input if there
+// are none
+func Operands(operands []string) []string {
+	if len(operands) == 0 {
+		return []string{"-"}
+	}
+	return operands
+}
+
+// Locale returns the locale selected by the environment, as
+// setlocale(LC_ALL, "") would, loading it the first time it is needed
+func Locale() locale.Def {
+	return locale.Current()
+}
+
+// Multibyte reports whether the characters of the current locale may take
+// more than one byte. A locale that names a UTF-8 codeset, such as
+// en_US.UTF-8, counts even if it isn't installed.
+func Multibyte() bool {
+	if Locale().Ctype.MbCurMax() > 1 {
+		return true
+	}
+	name := locale.FromEnv().Name("LC_CTYPE")
+	dot := strings.IndexByte(name, '.')
+	if dot < 0 {
+		return false
+	}
+	codeset := name[dot+1:]
+	if at := strings.IndexByte(codeset, '@'); at >= 0 {
+		codeset = codeset[:at]
+	}
+	codeset = strings.ToLower(codeset)
+	return codeset == "utf-8" || codeset == "utf8"
+}
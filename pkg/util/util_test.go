@@ -0,0 +1,133 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testUtility returns a Utility that writes to buffers, and records its exit
+// status rather than exiting
+func testUtility(usage string) (u *Utility, stderr *bytes.Buffer, exited *int) {
+	u = New("util", usage)
+	stderr = new(bytes.Buffer)
+	exited = new(int)
+	*exited = -1
+	u.Stderr = stderr
+	u.exit = func(status int) { *exited = status }
+	return u, stderr, exited
+}
+
+func TestMessage(t *testing.T) {
+	_, err := os.Open("/nonexistent/file")
+	if got := Message(err); got != "/nonexistent/file: no such file or directory" {
+		t.Errorf("got %q", got)
+	}
+	if got := Message(errors.New("plain")); got != "plain" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestReport(t *testing.T) {
+	u, stderr, _ := testUtility("")
+	u.Report(errors.New("noted"))
+	if want := "util: noted\n"; stderr.String() != want {
+		t.Errorf("wrote %q, expected %q", stderr.String(), want)
+	}
+	if u.Status() != 0 {
+		t.Errorf("status %d, expected 0", u.Status())
+	}
+}
+
+func TestWarn(t *testing.T) {
+	u, stderr, exited := testUtility("")
+	if u.Status() != 0 {
+		t.Errorf("initial status %d", u.Status())
+	}
+	u.Warnf("%s: bad", "file")
+	u.Warn(errors.New("worse"))
+	if want := "util: file: bad\nutil: worse\n"; stderr.String() != want {
+		t.Errorf("wrote %q, expected %q", stderr.String(), want)
+	}
+	if u.Status() != 1 || *exited != -1 {
+		t.Errorf("status %d, exited %d", u.Status(), *exited)
+	}
+
+	// A warning's status outlasts a successful exit
+	u.Exit(0)
+	if *exited != 1 {
+		t.Errorf("exited %d, expected 1", *exited)
+	}
+	u.SetStatus(2)
+	u.SetStatus(1)
+	if u.Status() != 2 {
+		t.Errorf("status %d, expected 2", u.Status())
+	}
+}
+
+func TestFatal(t *testing.T) {
+	u, stderr, exited := testUtility("usage: util [-x] file")
+	u.UsageError(errors.New("illegal option -- y"), UsageStatus)
+	if want := "util: illegal option -- y\nusage: util [-x] file\n"; stderr.String() != want {
+		t.Errorf("wrote %q, expected %q", stderr.String(), want)
+	}
+	if *exited != 2 {
+		t.Errorf("exited %d", *exited)
+	}
+
+	u, stderr, exited = testUtility("")
+	u.Fatal(errors.New("broken"), 4)
+	if stderr.String() != "util: broken\n" || *exited != 4 {
+		t.Errorf("wrote %q, exited %d", stderr.String(), *exited)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	u, _, _ := testUtility("")
+	u.Stdin = strings.NewReader("input")
+	r, err := u.Open("-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := ioutil.ReadAll(r)
+	if string(data) != "input" {
+		t.Errorf("read %q", data)
+	}
+	if _, err := u.Open("/nonexistent/file"); err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+func TestOperands(t *testing.T) {
+	if got := Operands(nil); !reflect.DeepEqual(got, []string{"-"}) {
+		t.Errorf("got %q", got)
+	}
+	if got := Operands([]string{"a", "b"}); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestMultibyte(t *testing.T) {
+	for _, v := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if old, ok := os.LookupEnv(v); ok {
+			defer os.Setenv(v, old)
+		} else {
+			defer os.Unsetenv(v)
+		}
+		os.Unsetenv(v)
+	}
+	tests := []struct {
+		lang string
+		want bool
+	}{{"", false}, {"C", false}, {"en_US.UTF-8", true}, {"de_DE.utf8@euro", true}, {"en_US.ISO-8859-1", false}}
+	for _, tt := range tests {
+		os.Setenv("LANG", tt.lang)
+		if got := Multibyte(); got != tt.want {
+			t.Errorf("LANG=%s: got %v", tt.lang, got)
+		}
+	}
+}